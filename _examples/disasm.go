@@ -0,0 +1,105 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/djhworld/gomeboycolor/disasm"
+	"github.com/djhworld/gomeboycolor/types"
+)
+
+//size of a switchable ROM bank, bank 0 is mapped into 0x0000 -> 0x3FFF and every other bank
+//into 0x4000 -> 0x7FFF
+const DISASM_BANK_SIZE = 0x4000
+
+//Prints a listing of a ROM file, usage: disasm <path-to-rom-file> [start] [end]
+//where start and end are either a hex offset into the ROM file (e.g. 1C000) or a hex bank and
+//address within the bank as the CPU sees it (e.g. 07:4000)
+func runDisassembler(args []string) error {
+	if len(args) == 0 {
+		return errors.New("Please specify the location of a ROM to disassemble")
+	}
+
+	rom, err := retrieveROM(args[0])
+	if err != nil {
+		return err
+	}
+
+	var start, end int = 0x0000, len(rom) - 1
+	if len(args) > 1 {
+		if start, err = parseLocation(args[1]); err != nil {
+			return err
+		}
+	}
+
+	if len(args) > 2 {
+		if end, err = parseLocation(args[2]); err != nil {
+			return err
+		}
+	}
+
+	if start > end || end >= len(rom) {
+		return fmt.Errorf("Invalid ROM offset range 0x%X -> 0x%X for a ROM of %d bytes", start, end, len(rom))
+	}
+
+	out := bufio.NewWriter(os.Stdout)
+	defer out.Flush()
+
+	//each bank is listed at the addresses it is mapped to so jumps within it line up
+	for offset := start; offset <= end; {
+		var bank int = offset / DISASM_BANK_SIZE
+		var bankEnd int = (bank+1)*DISASM_BANK_SIZE - 1
+		if bankEnd > end {
+			bankEnd = end
+		}
+
+		fmt.Fprintf(out, "; bank %02X\n", bank)
+		for _, instr := range disasm.Disassemble(rom[offset:bankEnd+1], mappedAddress(offset)) {
+			fmt.Fprintln(out, instr.Listing())
+		}
+		offset = bankEnd + 1
+	}
+	return nil
+}
+
+//Where the byte at offset in the ROM file appears when its bank is switched in
+func mappedAddress(offset int) types.Word {
+	if offset < DISASM_BANK_SIZE {
+		return types.Word(offset)
+	}
+	return types.Word(DISASM_BANK_SIZE + offset%DISASM_BANK_SIZE)
+}
+
+//Parses a hex ROM offset or a bank:address pair into an offset into the ROM file
+func parseLocation(s string) (int, error) {
+	i := strings.Index(s, ":")
+	if i < 0 {
+		offset, err := strconv.ParseUint(s, 16, 32)
+		if err != nil {
+			return 0, fmt.Errorf("Could not parse ROM offset %s: %v", s, err)
+		}
+		return int(offset), nil
+	}
+
+	bank, err := strconv.ParseUint(s[:i], 16, 16)
+	if err != nil {
+		return 0, fmt.Errorf("Could not parse bank %s: %v", s, err)
+	}
+	address, err := strconv.ParseUint(s[i+1:], 16, 16)
+	if err != nil {
+		return 0, fmt.Errorf("Could not parse address %s: %v", s, err)
+	}
+
+	switch {
+	case bank == 0 && address < DISASM_BANK_SIZE:
+		return int(address), nil
+	case bank > 0 && address >= DISASM_BANK_SIZE && address < 2*DISASM_BANK_SIZE:
+		return int(bank)*DISASM_BANK_SIZE + int(address) - DISASM_BANK_SIZE, nil
+	default:
+		return 0, fmt.Errorf("Address 0x%04X is not where bank %02X is mapped", address, bank)
+	}
+}
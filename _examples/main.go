@@ -15,6 +15,13 @@ import (
 func main() {
 	runtime.GOMAXPROCS(runtime.NumCPU())
 
+	if len(os.Args) > 1 && os.Args[1] == "disasm" {
+		if err := runDisassembler(os.Args[2:]); err != nil {
+			log.Fatalf("ERROR: %v", err)
+		}
+		return
+	}
+
 	if len(os.Args) != 2 {
 		log.Fatalf("ERROR: %v", errors.New("Please specify the location of a ROM to boot"))
 	}
@@ -39,7 +39,7 @@ func NewTerminalIO(frameRateLock int64, headless bool, displayFps bool) *Termina
 	terminalDisplay := new(terminalDisplay)
 
 	return &TerminalIO{
-		inputoutput.NewCoreIO(frameRateLock, headless, frameRateReporter, terminalDisplay),
+		inputoutput.NewCoreIO(frameRateLock, headless, frameRateReporter, terminalDisplay, nil),
 		terminalDisplay,
 	}
 }
@@ -1,41 +1,520 @@
-/**
- * Just a place holder for now
- * User: danielharper
- * Date: 28/02/2013
- * Time: 21:26
- */
 package apu
 
 import (
+	"fmt"
 	"log"
 
 	"github.com/djhworld/gomeboycolor/components"
 	"github.com/djhworld/gomeboycolor/types"
 )
 
+const NAME = "APU"
+const PREFIX = NAME + ":"
+
+//Sound register addresses
+const (
+	NR10 types.Word = 0xFF10
+	NR11            = 0xFF11
+	NR12            = 0xFF12
+	NR13            = 0xFF13
+	NR14            = 0xFF14
+	NR21            = 0xFF16
+	NR22            = 0xFF17
+	NR23            = 0xFF18
+	NR24            = 0xFF19
+	NR30            = 0xFF1A
+	NR31            = 0xFF1B
+	NR32            = 0xFF1C
+	NR33            = 0xFF1D
+	NR34            = 0xFF1E
+	NR41            = 0xFF20
+	NR42            = 0xFF21
+	NR43            = 0xFF22
+	NR44            = 0xFF23
+	NR50            = 0xFF24
+	NR51            = 0xFF25
+	NR52            = 0xFF26
+
+	WAVE_RAM_START = 0xFF30
+	WAVE_RAM_END   = 0xFF3F
+
+	//CGB only, read only registers holding the current digital output of each channel
+	PCM12 = 0xFF76
+	PCM34 = 0xFF77
+)
+
+//The APU is clocked at the same rate as the CPU in normal speed mode
+const CLOCK_RATE int = 4194304
+
+//The frame sequencer runs at 512hz and clocks the length counters, sweep and envelopes
+const FRAME_SEQUENCER_PERIOD int = 8192
+
+//Scales the mixer output (-480 to 480) up to 16-bit PCM
+const PCM_SCALE int = 64
+
+//Bits that always read back as 1 for each register between 0xFF10 and 0xFF2F
+var readMasks [0x20]byte = [0x20]byte{
+	0x80, 0x3F, 0x00, 0xFF, 0xBF, //NR10 - NR14
+	0xFF, 0x3F, 0x00, 0xFF, 0xBF, //unused, NR21 - NR24
+	0x7F, 0xFF, 0x9F, 0xFF, 0xBF, //NR30 - NR34
+	0xFF, 0xFF, 0x00, 0x00, 0xBF, //unused, NR41 - NR44
+	0x00, 0x00, 0x70, //NR50 - NR52
+	0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, //unused
+}
+
+//Notified of every write made to the APU, with the APU clock it was made on
+type APUObserver interface {
+	OnAPUWrite(clock uint64, addr types.Word, value byte)
+}
+
 type APU struct {
-	mem [0x41]byte
+	registers              [0x20]byte //0xFF10 -> 0xFF2F
+	channel1               *PulseChannel
+	channel2               *PulseChannel
+	channel3               *WaveChannel
+	channel4               *NoiseChannel
+	channels               [4]Channel
+	powered                bool
+	frameSequencerStep     int
+	frameSequencerTimer    int
+	cpuSpeed               int
+	cpuCycleRemainder      int
+	sampleRate             int
+	time                   int
+	clock                  uint64 //cycles since the APU was created, unaffected by resets
+	observers              []APUObserver
+	output                 *stereoOutput
+	channelOutputs         [4]*stereoOutput //only set for channels that are being captured
+	muted                  [4]bool
+	soloed                 [4]bool
+	RunningColorGBHardware bool
 }
 
 func NewAPU() *APU {
 	var a *APU = new(APU)
+	a.channel1 = NewPulseChannel("CH1", true)
+	a.channel2 = NewPulseChannel("CH2", false)
+	a.channel3 = NewWaveChannel("CH3")
+	a.channel4 = NewNoiseChannel("CH4")
+	a.channels = [4]Channel{a.channel1, a.channel2, a.channel3, a.channel4}
 	a.Reset()
 	return a
 }
 
 func (apu *APU) Name() string {
-	return "APU"
+	return NAME
+}
+
+//Advances the sound channels and frame sequencer by the given number of CPU cycles
+func (apu *APU) Step(cycles int) {
+	//in CGB double speed mode the CPU is clocked twice as fast as the APU
+	apu.cpuCycleRemainder += cycles
+	cycles = apu.cpuCycleRemainder / apu.cpuSpeed
+	apu.cpuCycleRemainder -= cycles * apu.cpuSpeed
+	apu.clock += uint64(cycles)
+
+	if !apu.powered {
+		apu.time += cycles
+		return
+	}
+
+	for cycles > 0 {
+		//when generating samples run up to the next point that a channel's output can change,
+		//so that every change in amplitude is placed on the cycle it actually happens
+		var n int = cycles
+		if apu.sampleRate > 0 {
+			for _, ch := range apu.channels {
+				if t := ch.timerRemaining(); t < n {
+					n = t
+				}
+			}
+		}
+		if apu.frameSequencerTimer < n {
+			n = apu.frameSequencerTimer
+		}
+
+		for _, ch := range apu.channels {
+			ch.Step(n)
+		}
+
+		apu.frameSequencerTimer -= n
+		if apu.frameSequencerTimer <= 0 {
+			apu.frameSequencerTimer += FRAME_SEQUENCER_PERIOD
+			apu.clockFrameSequencer()
+		}
+
+		apu.time += n
+		apu.updateOutput()
+		cycles -= n
+	}
+}
+
+//Adds any change in the mixer output since it was last checked to the sample buffers
+func (apu *APU) updateOutput() {
+	if apu.sampleRate == 0 {
+		return
+	}
+
+	left, right := apu.Mix()
+	apu.output.update(apu.time, left, right)
+
+	for i, o := range apu.channelOutputs {
+		if o != nil {
+			left, right := apu.mixChannel(i)
+			o.update(apu.time, left, right)
+		}
+	}
+}
+
+//The CPU speed (1 or 2) determines how many CPU cycles make up one APU cycle
+func (apu *APU) SetCPUSpeed(speed int) {
+	if apu.cpuSpeed != speed {
+		log.Printf("%s Following CPU speed change to %dx speed", PREFIX, speed)
+		apu.cpuSpeed = speed
+		apu.cpuCycleRemainder = 0
+	}
+}
+
+//Sets the rate at which stereo samples are generated, a rate of 0 disables sample generation
+func (apu *APU) SetSampleRate(rate int) {
+	log.Println(PREFIX, "Setting sample rate to", rate, "hz")
+	apu.sampleRate = rate
+	apu.output = nil
+	if rate > 0 {
+		apu.output = newStereoOutput(rate)
+	}
+
+	for i, o := range apu.channelOutputs {
+		if o != nil && rate > 0 {
+			apu.channelOutputs[i] = newStereoOutput(rate)
+		} else {
+			apu.channelOutputs[i] = nil
+		}
+	}
+	apu.resetOutput()
+}
+
+func (apu *APU) SampleRate() int {
+	return apu.sampleRate
+}
+
+//Discards any buffered output
+func (apu *APU) resetOutput() {
+	apu.time = 0
+	if apu.sampleRate == 0 {
+		return
+	}
+
+	apu.output.reset()
+	for _, o := range apu.channelOutputs {
+		if o != nil {
+			o.reset()
+		}
+	}
+	apu.updateOutput()
+}
+
+//Returns the interleaved stereo samples generated since the last call. Captured channel
+//samples must be collected with ChannelSamples before this is called
+func (apu *APU) Samples() []int16 {
+	if apu.sampleRate == 0 {
+		apu.time = 0
+		return nil
+	}
+
+	samples := apu.output.endFrame(apu.time)
+	apu.time = 0
+	return samples
+}
+
+//Step | Length | Sweep | Envelope
+//  0  |  256hz |       |
+//  2  |  256hz | 128hz |
+//  4  |  256hz |       |
+//  6  |  256hz | 128hz |
+//  7  |        |       |   64hz
+func (apu *APU) clockFrameSequencer() {
+	switch apu.frameSequencerStep {
+	case 0, 4:
+		apu.clockLengths()
+	case 2, 6:
+		apu.clockLengths()
+		apu.channel1.ClockSweep()
+	case 7:
+		apu.channel1.ClockEnvelope()
+		apu.channel2.ClockEnvelope()
+		apu.channel4.ClockEnvelope()
+	}
+	apu.frameSequencerStep = (apu.frameSequencerStep + 1) & 0x07
+}
+
+func (apu *APU) clockLengths() {
+	apu.channel1.ClockLength()
+	apu.channel2.ClockLength()
+	apu.channel3.ClockLength()
+	apu.channel4.ClockLength()
+}
+
+//Mixes the channels into a left and right output. Each channel's DAC converts its 4-bit output
+//into a value between -15 and 15, NR51 routes the channels to either side and NR50 scales each
+//side by its master volume (1-8), giving a range of -480 to 480. Muted channels are left out, as
+//is every channel but the soloed ones when any channel is soloed
+func (apu *APU) Mix() (int, int) {
+	var left, right int
+	var soloing bool = apu.soloed != [4]bool{}
+
+	for i := range apu.channels {
+		if apu.muted[i] || (soloing && !apu.soloed[i]) {
+			continue
+		}
+		l, r := apu.mixChannel(i)
+		left += l
+		right += r
+	}
+	return left, right
+}
+
+//Returns a single channel's contribution to the left and right output
+func (apu *APU) mixChannel(i int) (int, int) {
+	var ch Channel = apu.channels[i]
+	if !ch.IsDACEnabled() {
+		return 0, 0
+	}
+
+	var left, right int
+	var amplitude int = int(ch.Output())*2 - 15
+	var panning byte = apu.registers[NR51-NR10]
+
+	if panning&(0x10<<uint(i)) != 0x00 {
+		left = amplitude
+	}
+
+	if panning&(0x01<<uint(i)) != 0x00 {
+		right = amplitude
+	}
+
+	var volume byte = apu.registers[NR50-NR10]
+	left *= int((volume>>4)&0x07) + 1
+	right *= int(volume&0x07) + 1
+	return left, right
+}
+
+//Channels are numbered 1 to 4 as they are on the hardware
+func channelIndex(channel int) (int, error) {
+	if channel < 1 || channel > 4 {
+		return 0, fmt.Errorf("%s Invalid sound channel %d, must be between 1 and 4", PREFIX, channel)
+	}
+	return channel - 1, nil
+}
+
+//Muted channels are left out of the mixer output
+func (apu *APU) SetChannelMuted(channel int, muted bool) error {
+	i, err := channelIndex(channel)
+	if err != nil {
+		return err
+	}
+	apu.muted[i] = muted
+	apu.updateOutput()
+	return nil
+}
+
+func (apu *APU) IsChannelMuted(channel int) bool {
+	i, err := channelIndex(channel)
+	return err == nil && apu.muted[i]
+}
+
+//When any channel is soloed only the soloed channels are heard
+func (apu *APU) SetChannelSolo(channel int, solo bool) error {
+	i, err := channelIndex(channel)
+	if err != nil {
+		return err
+	}
+	apu.soloed[i] = solo
+	apu.updateOutput()
+	return nil
+}
+
+func (apu *APU) IsChannelSoloed(channel int) bool {
+	i, err := channelIndex(channel)
+	return err == nil && apu.soloed[i]
+}
+
+//Starts or stops generating a separate sample stream for a channel. The stream follows the
+//channel's panning and the master volume but ignores mute and solo
+func (apu *APU) SetChannelCapture(channel int, capture bool) error {
+	i, err := channelIndex(channel)
+	if err != nil {
+		return err
+	}
+
+	if !capture {
+		apu.channelOutputs[i] = nil
+		return nil
+	}
+
+	if apu.sampleRate == 0 {
+		return fmt.Errorf("%s Cannot capture channel %d without a sample rate", PREFIX, channel)
+	}
+
+	if apu.channelOutputs[i] == nil {
+		o := newStereoOutput(apu.sampleRate)
+		//the frame is already under way so start the capture from the current amplitude
+		left, right := apu.mixChannel(i)
+		o.update(apu.time, left, right)
+		apu.channelOutputs[i] = o
+	}
+	return nil
+}
+
+func (apu *APU) IsChannelCaptured(channel int) bool {
+	i, err := channelIndex(channel)
+	return err == nil && apu.channelOutputs[i] != nil
+}
+
+//Returns the interleaved stereo samples generated for a captured channel during the current
+//frame, this must be called before Samples ends the frame
+func (apu *APU) ChannelSamples(channel int) []int16 {
+	i, err := channelIndex(channel)
+	if err != nil || apu.channelOutputs[i] == nil {
+		return nil
+	}
+	return apu.channelOutputs[i].endFrame(apu.time)
+}
+
+func (apu *APU) RegisterObserver(observer APUObserver) {
+	apu.observers = append(apu.observers, observer)
+}
+
+func (apu *APU) UnregisterObserver(observer APUObserver) {
+	for i, o := range apu.observers {
+		if o == observer {
+			apu.observers = append(apu.observers[:i], apu.observers[i+1:]...)
+			return
+		}
+	}
+}
+
+//The number of APU cycles that have run since the APU was created
+func (apu *APU) Clock() uint64 {
+	return apu.clock
+}
+
+func (apu *APU) IsPowered() bool {
+	return apu.powered
 }
 
 func (apu *APU) Read(addr types.Word) byte {
-	if addr == 0xFF26 {
-		return 0x00
+	switch {
+	case addr == NR52:
+		return apu.readNR52()
+	case addr >= WAVE_RAM_START && addr <= WAVE_RAM_END:
+		return apu.channel3.ReadWaveRAM(int(addr-WAVE_RAM_START), apu.RunningColorGBHardware)
+	case addr == PCM12:
+		return apu.channel2.Output()<<4 | apu.channel1.Output()
+	case addr == PCM34:
+		return apu.channel4.Output()<<4 | apu.channel3.Output()
+	default:
+		return apu.registers[addr-NR10] | readMasks[addr-NR10]
 	}
-	return apu.mem[addr-0xFF00]
 }
 
 func (apu *APU) Write(addr types.Word, value byte) {
-	apu.mem[addr-0xFF00] = value
+	if addr == PCM12 || addr == PCM34 {
+		return
+	}
+
+	for _, observer := range apu.observers {
+		observer.OnAPUWrite(apu.clock, addr, value)
+	}
+
+	//wave RAM is unaffected by the power state
+	if addr >= WAVE_RAM_START && addr <= WAVE_RAM_END {
+		apu.channel3.WriteWaveRAM(int(addr-WAVE_RAM_START), value, apu.RunningColorGBHardware)
+		return
+	}
+
+	if addr == NR52 {
+		apu.setPower(value&0x80 == 0x80)
+		apu.updateOutput()
+		return
+	}
+
+	if !apu.powered {
+		//DMG hardware still allows the length counters to be written while powered off
+		if !apu.RunningColorGBHardware {
+			apu.writeLengthWhilePoweredOff(addr, value)
+		}
+		return
+	}
+
+	apu.writeRegister(addr, value)
+	apu.updateOutput()
+}
+
+func (apu *APU) writeRegister(addr types.Word, value byte) {
+	apu.registers[addr-NR10] = value
+
+	switch {
+	case addr >= NR10 && addr <= NR14:
+		apu.channel1.Write(int(addr-NR10), value)
+	case addr >= NR21-1 && addr <= NR24:
+		apu.channel2.Write(int(addr-(NR21-1)), value)
+	case addr >= NR30 && addr <= NR34:
+		apu.channel3.Write(int(addr-NR30), value)
+	case addr >= NR41-1 && addr <= NR44:
+		apu.channel4.Write(int(addr-(NR41-1)), value)
+	}
+}
+
+func (apu *APU) writeLengthWhilePoweredOff(addr types.Word, value byte) {
+	switch addr {
+	case NR11:
+		apu.channel1.length.load(int(value & 0x3F))
+	case NR21:
+		apu.channel2.length.load(int(value & 0x3F))
+	case NR31:
+		apu.channel3.length.load(int(value))
+	case NR41:
+		apu.channel4.length.load(int(value & 0x3F))
+	}
+}
+
+//Bit 7 is the power state, bits 0-3 report whether each channel is currently playing
+func (apu *APU) readNR52() byte {
+	var value byte = readMasks[NR52-NR10]
+	if apu.powered {
+		value |= 0x80
+	}
+
+	for i, ch := range apu.channels {
+		if ch.IsEnabled() {
+			value |= 0x01 << uint(i)
+		}
+	}
+	return value
+}
+
+//Powering off clears every register from NR10 to NR51 and stops all channels, powering on
+//restarts the frame sequencer
+func (apu *APU) setPower(on bool) {
+	if apu.powered == on {
+		return
+	}
+
+	if on {
+		log.Println(PREFIX, "Powering on")
+		apu.frameSequencerStep = 0
+		apu.frameSequencerTimer = FRAME_SEQUENCER_PERIOD
+	} else {
+		log.Println(PREFIX, "Powering off")
+		for addr := NR10; addr <= NR51; addr++ {
+			apu.writeRegister(addr, 0x00)
+		}
+		for _, ch := range apu.channels {
+			ch.Reset()
+		}
+	}
+	apu.powered = on
 }
 
 func (apu *APU) LinkIRQHandler(m components.IRQHandler) {
@@ -43,5 +522,16 @@ func (apu *APU) LinkIRQHandler(m components.IRQHandler) {
 }
 
 func (apu *APU) Reset() {
-	log.Println(apu.Name()+": Resetting", apu.Name())
+	log.Println(PREFIX, "Resetting", apu.Name())
+	apu.registers = *new([0x20]byte)
+	for _, ch := range apu.channels {
+		ch.Reset()
+	}
+	apu.powered = false
+	apu.frameSequencerStep = 0
+	apu.frameSequencerTimer = FRAME_SEQUENCER_PERIOD
+	apu.cpuSpeed = 1
+	apu.cpuCycleRemainder = 0
+	apu.resetOutput()
+	apu.RunningColorGBHardware = false
 }
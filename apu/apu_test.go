@@ -0,0 +1,511 @@
+package apu
+
+import (
+	"math"
+	"testing"
+
+	"github.com/djhworld/gomeboycolor/types"
+	"github.com/stretchrcom/testify/assert"
+)
+
+func TestPulseRegisterReadMasks(t *testing.T) {
+	//given
+	a := NewAPU()
+	a.Write(NR52, 0x80)
+
+	//when
+	a.Write(NR10, 0x00)
+	a.Write(NR11, 0x80)
+	a.Write(NR13, 0x12)
+	a.Write(NR14, 0x00)
+
+	//then
+	assert.Equal(t, byte(0x80), a.Read(NR10))
+	assert.Equal(t, byte(0xBF), a.Read(NR11))
+	assert.Equal(t, byte(0xFF), a.Read(NR13))
+	assert.Equal(t, byte(0xBF), a.Read(NR14))
+}
+
+func TestPulseChannelProducesDutyWaveform(t *testing.T) {
+	//given
+	p := NewPulseChannel("test", false)
+	p.Write(1, 0x80) //50% duty
+	p.Write(2, 0xF0) //max volume, no envelope
+	p.Write(3, 0x00)
+	p.Write(4, 0x87) //trigger with frequency 0x700
+
+	//when
+	var outputs []byte
+	for i := 0; i < 8; i++ {
+		p.Step(p.period())
+		outputs = append(outputs, p.Output())
+	}
+
+	//then
+	assert.Equal(t, []byte{0, 0, 0, 0, 15, 15, 15, 15}, outputs)
+}
+
+func TestPulseChannelDisabledWhenDACOff(t *testing.T) {
+	//given
+	p := NewPulseChannel("test", false)
+	p.Write(2, 0xF0)
+	p.Write(4, 0x80)
+	assert.True(t, p.IsEnabled())
+
+	//when
+	p.Write(2, 0x00)
+
+	//then
+	assert.False(t, p.IsEnabled())
+}
+
+func TestPulseChannelLengthCounterExpires(t *testing.T) {
+	//given
+	p := NewPulseChannel("test", false)
+	p.Write(1, 0x3E) //length of 2
+	p.Write(2, 0xF0)
+	p.Write(4, 0xC0) //trigger with length enabled
+
+	//when
+	p.ClockLength()
+
+	//then
+	assert.True(t, p.IsEnabled())
+	p.ClockLength()
+	assert.False(t, p.IsEnabled())
+}
+
+func TestPulseChannelEnvelopeDecreasesVolume(t *testing.T) {
+	//given
+	p := NewPulseChannel("test", false)
+	p.Write(2, 0xF1) //volume 15, decreasing, period 1
+	p.Write(4, 0x80)
+
+	//when
+	p.ClockEnvelope()
+	p.ClockEnvelope()
+
+	//then
+	assert.Equal(t, byte(13), p.envelope.volume)
+}
+
+func TestPulseChannelSweepIncreasesFrequency(t *testing.T) {
+	//given
+	p := NewPulseChannel("test", true)
+	p.Write(0, 0x11) //period 1, increase, shift 1
+	p.Write(2, 0xF0)
+	p.Write(3, 0x00)
+	p.Write(4, 0x81) //trigger with frequency 0x100
+
+	//when
+	p.ClockSweep()
+
+	//then
+	assert.Equal(t, 0x180, p.frequency)
+	assert.True(t, p.IsEnabled())
+}
+
+func TestPulseChannelSweepOverflowDisablesChannel(t *testing.T) {
+	//given
+	p := NewPulseChannel("test", true)
+	p.Write(0, 0x11)
+	p.Write(2, 0xF0)
+	p.Write(3, 0xFF)
+	p.Write(4, 0x87) //trigger with frequency 0x7FF, overflow check happens immediately
+
+	//then
+	assert.False(t, p.IsEnabled())
+}
+
+func TestWaveChannelPlaysSamplesWithOutputLevel(t *testing.T) {
+	//given
+	w := NewWaveChannel("test")
+	w.WriteWaveRAM(0, 0xF8, false)
+	w.WriteWaveRAM(1, 0xE0, false)
+	w.Write(0, 0x80) //DAC on
+	w.Write(2, 0x40) //50% output level
+	w.Write(4, 0x80) //trigger
+
+	//when
+	w.Step(w.timer)
+	first := w.Output()
+	w.Step(w.period())
+	second := w.Output()
+
+	//then
+	assert.Equal(t, byte(0x04), first)
+	assert.Equal(t, byte(0x07), second)
+}
+
+func TestWaveChannelDisabledWhenDACOff(t *testing.T) {
+	//given
+	w := NewWaveChannel("test")
+	w.Write(0, 0x80)
+	w.Write(4, 0x80)
+	assert.True(t, w.IsEnabled())
+
+	//when
+	w.Write(0, 0x00)
+
+	//then
+	assert.False(t, w.IsEnabled())
+}
+
+func TestWaveRAMAccessWhilePlaying(t *testing.T) {
+	//given
+	a := NewAPU()
+	a.Write(NR52, 0x80)
+	for i := types.Word(0); i < 16; i++ {
+		a.Write(WAVE_RAM_START+i, byte(i))
+	}
+	a.Write(NR30, 0x80)
+	a.Write(NR34, 0x80)
+	a.Step(a.channel3.timer + a.channel3.period()*2) //channel now reading byte 1
+	a.Step(2)
+
+	//then DMG only allows access on the cycle the byte is read
+	assert.Equal(t, byte(0xFF), a.Read(WAVE_RAM_START+0x0A))
+
+	//then CGB returns the byte currently being read
+	a.RunningColorGBHardware = true
+	assert.Equal(t, byte(0x01), a.Read(WAVE_RAM_START+0x0A))
+}
+
+func TestNoiseChannelLFSR15BitSequence(t *testing.T) {
+	//given
+	n := NewNoiseChannel("test")
+	n.Write(2, 0xF0)
+	n.Write(3, 0x00) //15-bit mode, divisor 8, shift 0
+	n.Write(4, 0x80)
+
+	//when
+	n.Step(n.period())
+
+	//then
+	assert.Equal(t, uint16(0x3FFF), n.lfsr)
+	assert.Equal(t, byte(0), n.Output())
+}
+
+func TestNoiseChannel7BitModeSetsBit6(t *testing.T) {
+	//given
+	n := NewNoiseChannel("test")
+	n.Write(2, 0xF0)
+	n.Write(3, 0x08) //7-bit mode
+	n.Write(4, 0x80)
+	n.lfsr = 0x0001
+
+	//when
+	n.Step(n.period())
+
+	//then
+	assert.Equal(t, uint16(0x4040), n.lfsr)
+	assert.Equal(t, byte(15), n.Output())
+}
+
+func TestNoiseChannelPeriodFromDivisorAndShift(t *testing.T) {
+	//given
+	n := NewNoiseChannel("test")
+
+	//when
+	n.Write(3, 0x25) //shift 2, divisor code 5
+
+	//then
+	assert.Equal(t, 80<<2, n.period())
+}
+
+func TestNoiseChannelLargeClockShiftStopsLFSR(t *testing.T) {
+	//given
+	n := NewNoiseChannel("test")
+	n.Write(2, 0xF0)
+	n.Write(3, 0xE0)
+	n.Write(4, 0x80)
+
+	//when
+	n.Step(n.period() * 4)
+
+	//then
+	assert.Equal(t, uint16(0x7FFF), n.lfsr)
+}
+
+func TestNR52ReportsPowerAndChannelStatus(t *testing.T) {
+	//given
+	a := NewAPU()
+	assert.Equal(t, byte(0x70), a.Read(NR52))
+
+	//when
+	a.Write(NR52, 0x80)
+	a.Write(NR12, 0xF0)
+	a.Write(NR14, 0x80)
+	a.Write(NR42, 0xF0)
+	a.Write(NR44, 0x80)
+
+	//then
+	assert.Equal(t, byte(0xF9), a.Read(NR52))
+}
+
+func TestPowerOffClearsRegistersAndIgnoresWrites(t *testing.T) {
+	//given
+	a := NewAPU()
+	a.Write(NR52, 0x80)
+	a.Write(NR50, 0x77)
+	a.Write(NR12, 0xF0)
+	a.Write(NR14, 0x80)
+	a.Write(WAVE_RAM_START, 0x12)
+
+	//when
+	a.Write(NR52, 0x00)
+	a.Write(NR51, 0xFF)
+
+	//then
+	assert.Equal(t, byte(0x70), a.Read(NR52))
+	assert.Equal(t, byte(0x00), a.Read(NR50))
+	assert.Equal(t, byte(0x00), a.Read(NR51))
+	assert.Equal(t, byte(0x00), a.Read(NR12))
+	assert.Equal(t, byte(0x12), a.Read(WAVE_RAM_START))
+	assert.False(t, a.channel1.IsEnabled())
+}
+
+func TestFrameSequencerClocksLengthCounters(t *testing.T) {
+	//given
+	a := NewAPU()
+	a.Write(NR52, 0x80)
+	a.Write(NR21, 0x3E) //length of 2
+	a.Write(NR22, 0xF0)
+	a.Write(NR24, 0xC0)
+
+	//when
+	a.Step(FRAME_SEQUENCER_PERIOD) //step 0 clocks length
+	assert.True(t, a.channel2.IsEnabled())
+	a.Step(FRAME_SEQUENCER_PERIOD) //step 1 does not
+	assert.True(t, a.channel2.IsEnabled())
+	a.Step(FRAME_SEQUENCER_PERIOD) //step 2 clocks length
+
+	//then
+	assert.False(t, a.channel2.IsEnabled())
+	assert.Equal(t, byte(0x70|0x80), a.Read(NR52))
+}
+
+func TestFrameSequencerClocksEnvelopeOnStep7(t *testing.T) {
+	//given
+	a := NewAPU()
+	a.Write(NR52, 0x80)
+	a.Write(NR42, 0xF1)
+	a.Write(NR44, 0x80)
+
+	//when
+	a.Step(FRAME_SEQUENCER_PERIOD * 7)
+	assert.Equal(t, byte(15), a.channel4.envelope.volume)
+	a.Step(FRAME_SEQUENCER_PERIOD)
+
+	//then
+	assert.Equal(t, byte(14), a.channel4.envelope.volume)
+}
+
+func TestMixAppliesPanningAndMasterVolume(t *testing.T) {
+	//given
+	a := NewAPU()
+	a.Write(NR52, 0x80)
+	a.Write(NR50, 0x70) //left volume 8, right volume 1
+	a.Write(NR51, 0x12) //channel 1 left, channel 2 right
+	a.Write(NR12, 0xF0)
+	a.Write(NR14, 0x80)
+	a.Write(NR22, 0xF0)
+	a.Write(NR24, 0x80)
+	a.channel1.dutyStep = 7 //high for 12.5% duty
+	a.channel2.dutyStep = 0 //low for 12.5% duty
+
+	//when
+	left, right := a.Mix()
+
+	//then
+	assert.Equal(t, 15*8, left)
+	assert.Equal(t, -15, right)
+}
+
+func TestSamplesGeneratedAtSampleRate(t *testing.T) {
+	//given
+	a := NewAPU()
+	a.SetSampleRate(1024)
+
+	//when
+	a.Step(CLOCK_RATE / 2)
+	a.Step(CLOCK_RATE / 2)
+	samples := a.Samples()
+
+	//then
+	assert.Equal(t, 2048, len(samples))
+	assert.Equal(t, 0, len(a.Samples()))
+}
+
+func TestSamplesFollowMixerOutput(t *testing.T) {
+	//given
+	a := NewAPU()
+	a.SetSampleRate(44100)
+	a.Write(NR52, 0x80)
+	a.Write(NR50, 0x77)
+	a.Write(NR51, 0x10) //channel 1 left only
+	a.Write(NR11, 0x80) //50% duty
+	a.Write(NR12, 0xF0)
+	a.Write(NR13, 0xC0)
+	a.Write(NR14, 0x87) //2048hz
+
+	//when
+	a.Step(CLOCK_RATE / 16)
+	samples := a.Samples()
+
+	//then (skipping the start while the high pass filter settles)
+	var sum float64
+	var count int
+	for i := len(samples) / 2; i < len(samples); i += 2 {
+		sum += float64(samples[i]) * float64(samples[i])
+		count++
+		assert.Equal(t, int16(0), samples[i+1])
+	}
+	//the square wave swings between -15 and 15 on the DAC, scaled by the master volume
+	expected := float64(15 * 8 * PCM_SCALE)
+	assert.InDelta(t, expected, math.Sqrt(sum/float64(count)), expected*0.1)
+}
+
+func TestSamplesInDoubleSpeedMode(t *testing.T) {
+	//given
+	a := NewAPU()
+	a.SetSampleRate(1024)
+	a.SetCPUSpeed(2)
+
+	//when
+	a.Step(CLOCK_RATE)
+	a.Step(1)
+	a.Step(1)
+	samples := a.Samples()
+
+	//then
+	assert.Equal(t, 1024, len(samples))
+	assert.Equal(t, 0, a.cpuCycleRemainder)
+}
+
+func TestMutedChannelIsLeftOutOfMix(t *testing.T) {
+	//given
+	a := NewAPU()
+	a.Write(NR52, 0x80)
+	a.Write(NR50, 0x00)
+	a.Write(NR51, 0x33) //channels 1 and 2 on both sides
+	a.Write(NR22, 0xF0)
+
+	//when
+	err := a.SetChannelMuted(1, true)
+	left, right := a.Mix()
+
+	//then channel 1's DAC is off so only channel 2 (-15) is left
+	assert.Nil(t, err)
+	assert.True(t, a.IsChannelMuted(1))
+	assert.Equal(t, -15, left)
+	assert.Equal(t, -15, right)
+
+	//when channel 2 is muted as well
+	a.Write(NR12, 0xF0)
+	a.SetChannelMuted(2, true)
+	left, right = a.Mix()
+
+	//then
+	assert.Equal(t, 0, left)
+	assert.Equal(t, 0, right)
+}
+
+func TestSoloedChannelsAreTheOnlyOnesMixed(t *testing.T) {
+	//given
+	a := NewAPU()
+	a.Write(NR52, 0x80)
+	a.Write(NR50, 0x00)
+	a.Write(NR51, 0xFF)
+	a.Write(NR12, 0xF0)
+	a.Write(NR22, 0xF0)
+	a.Write(NR42, 0xF0)
+
+	//when
+	a.SetChannelSolo(4, true)
+	left, _ := a.Mix()
+
+	//then
+	assert.True(t, a.IsChannelSoloed(4))
+	assert.Equal(t, -15, left)
+
+	//when the solo is removed
+	a.SetChannelSolo(4, false)
+	left, _ = a.Mix()
+
+	//then
+	assert.Equal(t, -45, left)
+}
+
+func TestInvalidSoundChannel(t *testing.T) {
+	a := NewAPU()
+	assert.NotNil(t, a.SetChannelMuted(0, true))
+	assert.NotNil(t, a.SetChannelSolo(5, true))
+	assert.NotNil(t, a.SetChannelCapture(5, true))
+	assert.False(t, a.IsChannelMuted(5))
+}
+
+func TestCapturedChannelIgnoresMute(t *testing.T) {
+	//given
+	a := NewAPU()
+	a.SetSampleRate(44100)
+	a.Write(NR52, 0x80)
+	a.Write(NR50, 0x77)
+	a.Write(NR51, 0x22) //channel 2 on both sides
+	a.Write(NR21, 0x80)
+	a.Write(NR22, 0xF0)
+	a.Write(NR23, 0xC0)
+	a.Write(NR24, 0x87)
+	a.SetChannelMuted(2, true)
+
+	//when
+	err := a.SetChannelCapture(2, true)
+	a.Step(CLOCK_RATE / 16)
+	captured := a.ChannelSamples(2)
+	mixed := a.Samples()
+
+	//then
+	assert.Nil(t, err)
+	assert.Equal(t, len(mixed), len(captured))
+	assert.Nil(t, a.ChannelSamples(1))
+
+	var capturedPeak, mixedPeak int16
+	for i := range captured {
+		if captured[i] > capturedPeak {
+			capturedPeak = captured[i]
+		}
+		if mixed[i] > mixedPeak {
+			mixedPeak = mixed[i]
+		}
+	}
+	assert.True(t, capturedPeak > 5000)
+	assert.Equal(t, int16(0), mixedPeak)
+}
+
+func TestChannelCaptureNeedsSampleRate(t *testing.T) {
+	a := NewAPU()
+	assert.NotNil(t, a.SetChannelCapture(1, true))
+	assert.False(t, a.IsChannelCaptured(1))
+}
+
+func TestPCMRegistersHoldChannelOutput(t *testing.T) {
+	//given
+	a := NewAPU()
+	a.Write(NR52, 0x80)
+	a.Write(NR12, 0xA0)
+	a.Write(NR14, 0x80)
+	a.channel1.dutyStep = 7
+	a.Write(NR42, 0x50)
+	a.Write(NR44, 0x80)
+	a.channel4.lfsr = 0x7FFE
+
+	//then
+	assert.Equal(t, byte(0x0A), a.Read(PCM12))
+	assert.Equal(t, byte(0x50), a.Read(PCM34))
+
+	//when written to
+	a.Write(PCM12, 0xFF)
+
+	//then nothing changes
+	assert.Equal(t, byte(0x0A), a.Read(PCM12))
+}
@@ -0,0 +1,128 @@
+package apu
+
+import "math"
+
+//Band limited synthesis. Rather than point sampling the mixer output (which aliases badly for
+//square waves) every change in amplitude is added to the buffer as a band limited step, placed at
+//the exact clock cycle it happened on. Reading the buffer integrates the steps back into samples
+const (
+	BLIP_KERNEL_WIDTH int     = 16
+	BLIP_PHASES       int     = 64
+	BLIP_CUTOFF       float64 = 0.9 //fraction of the nyquist frequency that is passed through
+)
+
+//Impulse responses for each fractional sample position a step can start at
+var blipKernel [BLIP_PHASES][BLIP_KERNEL_WIDTH]float64
+
+func init() {
+	for phase := 0; phase < BLIP_PHASES; phase++ {
+		var sum float64
+		for i := 0; i < BLIP_KERNEL_WIDTH; i++ {
+			x := float64(i-BLIP_KERNEL_WIDTH/2+1) - float64(phase)/float64(BLIP_PHASES)
+			blipKernel[phase][i] = sinc(x*BLIP_CUTOFF) * blackmanWindow(x, float64(BLIP_KERNEL_WIDTH))
+			sum += blipKernel[phase][i]
+		}
+
+		//normalise so each step adds exactly its delta once integrated
+		for i := 0; i < BLIP_KERNEL_WIDTH; i++ {
+			blipKernel[phase][i] /= sum
+		}
+	}
+}
+
+func sinc(x float64) float64 {
+	if x == 0 {
+		return 1
+	}
+	return math.Sin(math.Pi*x) / (math.Pi * x)
+}
+
+func blackmanWindow(x, width float64) float64 {
+	if math.Abs(x) >= width/2 {
+		return 0
+	}
+	var n float64 = 2 * math.Pi * x / width
+	return 0.42 + 0.5*math.Cos(n) + 0.08*math.Cos(2*n)
+}
+
+//A mono buffer that resamples amplitude changes from an input clock rate down to a sample rate
+type BandLimitedBuffer struct {
+	sampleRate int
+	ratio      float64 //samples per clock
+	offset     float64 //position of the current frame start in samples
+	buffer     []float64
+	integrator float64
+}
+
+func NewBandLimitedBuffer(clockRate, sampleRate int) *BandLimitedBuffer {
+	b := new(BandLimitedBuffer)
+	b.sampleRate = sampleRate
+	b.ratio = float64(sampleRate) / float64(clockRate)
+	return b
+}
+
+func (b *BandLimitedBuffer) SampleRate() int {
+	return b.sampleRate
+}
+
+//Adds a change in amplitude at the given clock time relative to the start of the current frame
+func (b *BandLimitedBuffer) AddDelta(clockTime int, delta float64) {
+	if delta == 0 {
+		return
+	}
+
+	var t float64 = b.offset + float64(clockTime)*b.ratio
+	var index int = int(t)
+	var phase int = int((t - float64(index)) * float64(BLIP_PHASES))
+
+	if needed := index + BLIP_KERNEL_WIDTH; needed > len(b.buffer) {
+		b.buffer = append(b.buffer, make([]float64, needed-len(b.buffer))...)
+	}
+
+	kernel := &blipKernel[phase]
+	for i := 0; i < BLIP_KERNEL_WIDTH; i++ {
+		b.buffer[index+i] += delta * kernel[i]
+	}
+}
+
+//Ends the current frame after the given number of clocks, making the samples up to that point available
+func (b *BandLimitedBuffer) EndFrame(clocks int) {
+	b.offset += float64(clocks) * b.ratio
+}
+
+func (b *BandLimitedBuffer) SamplesAvailable() int {
+	return int(b.offset)
+}
+
+//Removes and returns all the samples that are available
+func (b *BandLimitedBuffer) ReadSamples() []float64 {
+	var count int = b.SamplesAvailable()
+	samples := make([]float64, count)
+
+	for i := 0; i < count; i++ {
+		if i < len(b.buffer) {
+			b.integrator += b.buffer[i]
+		}
+		samples[i] = b.integrator
+	}
+
+	//shift the remaining steps (which overlap into the next frame) down to the start of the buffer
+	if count < len(b.buffer) {
+		remaining := copy(b.buffer, b.buffer[count:])
+		for i := remaining; i < len(b.buffer); i++ {
+			b.buffer[i] = 0
+		}
+	} else {
+		for i := range b.buffer {
+			b.buffer[i] = 0
+		}
+	}
+	b.offset -= float64(count)
+	return samples
+}
+
+func (b *BandLimitedBuffer) Clear() {
+	b.buffer = b.buffer[:0]
+	b.offset = 0
+	b.integrator = 0
+}
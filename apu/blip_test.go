@@ -0,0 +1,63 @@
+package apu
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchrcom/testify/assert"
+)
+
+func TestBandLimitedStepSettlesToDelta(t *testing.T) {
+	//given
+	b := NewBandLimitedBuffer(CLOCK_RATE, 44100)
+
+	//when
+	b.AddDelta(1000, 1000)
+	b.EndFrame(CLOCK_RATE / 100)
+	samples := b.ReadSamples()
+
+	//then
+	assert.InDelta(t, 441, len(samples), 1)
+	assert.InDelta(t, 0, samples[0], 0.001)
+	assert.InDelta(t, 1000, samples[len(samples)-1], 0.001)
+}
+
+func TestBandLimitedSamplesCarryAcrossFrames(t *testing.T) {
+	//given
+	b := NewBandLimitedBuffer(CLOCK_RATE, 44100)
+	var total int
+
+	//when
+	for i := 0; i < 60; i++ {
+		b.EndFrame(CLOCK_RATE / 60)
+		total += len(b.ReadSamples())
+	}
+
+	//then
+	assert.InDelta(t, 44100, total, 1)
+}
+
+func TestBandLimitedRemovesFrequenciesAboveNyquist(t *testing.T) {
+	//given a square wave at ~30khz, which can't be represented at 44.1khz
+	b := NewBandLimitedBuffer(CLOCK_RATE, 44100)
+	var halfPeriod int = CLOCK_RATE / 60000
+	var level float64 = 1000
+
+	//when
+	b.AddDelta(0, level)
+	for clock := halfPeriod; clock < CLOCK_RATE/10; clock += halfPeriod {
+		level = -level
+		b.AddDelta(clock, 2*level)
+	}
+	b.EndFrame(CLOCK_RATE / 10)
+	samples := b.ReadSamples()
+
+	//then the aliased output is much quieter than the original wave
+	var sum float64
+	skip := len(samples) / 4
+	for _, s := range samples[skip:] {
+		sum += s * s
+	}
+	rms := math.Sqrt(sum / float64(len(samples)-skip))
+	assert.True(t, rms < 1000*0.1, "expected rms %f to be attenuated", rms)
+}
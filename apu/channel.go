@@ -0,0 +1,99 @@
+package apu
+
+//Length counter shared by all four channels. When enabled it counts down at 256hz
+//and silences the channel when it reaches zero
+type lengthCounter struct {
+	enabled bool
+	counter int
+	max     int
+}
+
+func newLengthCounter(max int) lengthCounter {
+	return lengthCounter{max: max}
+}
+
+//loads the counter from the length data in NRx1 (the counter is max - data)
+func (l *lengthCounter) load(data int) {
+	l.counter = l.max - data
+}
+
+//on trigger a counter that has run out is reloaded with the maximum length
+func (l *lengthCounter) trigger() {
+	if l.counter == 0 {
+		l.counter = l.max
+	}
+}
+
+//returns true when the counter has expired and the channel should be disabled
+func (l *lengthCounter) clock() bool {
+	if l.enabled && l.counter > 0 {
+		l.counter--
+		return l.counter == 0
+	}
+	return false
+}
+
+func (l *lengthCounter) reset() {
+	l.enabled = false
+	l.counter = 0
+}
+
+//Volume envelope used by the pulse and noise channels (NRx2)
+type volumeEnvelope struct {
+	initialVolume byte
+	increasing    bool
+	period        byte
+	timer         byte
+	volume        byte
+}
+
+func (e *volumeEnvelope) write(value byte) {
+	e.initialVolume = value >> 4
+	e.increasing = value&0x08 == 0x08
+	e.period = value & 0x07
+}
+
+//the DAC for a channel is powered when any of the upper 5 bits of NRx2 are set
+func (e *volumeEnvelope) dacEnabled() bool {
+	return e.initialVolume != 0 || e.increasing
+}
+
+func (e *volumeEnvelope) trigger() {
+	e.timer = e.period
+	e.volume = e.initialVolume
+}
+
+//clocked at 64hz by the frame sequencer
+func (e *volumeEnvelope) clock() {
+	if e.period == 0 {
+		return
+	}
+
+	if e.timer > 0 {
+		e.timer--
+	}
+
+	if e.timer == 0 {
+		e.timer = e.period
+		if e.increasing && e.volume < 0x0F {
+			e.volume++
+		} else if !e.increasing && e.volume > 0x00 {
+			e.volume--
+		}
+	}
+}
+
+func (e *volumeEnvelope) reset() {
+	*e = volumeEnvelope{}
+}
+
+//Common behaviour of the four sound channels
+type Channel interface {
+	Name() string
+	Step(cycles int)
+	Output() byte
+	IsEnabled() bool
+	IsDACEnabled() bool
+	Reset()
+	timerRemaining() int
+}
@@ -0,0 +1,133 @@
+package apu
+
+//Base divisors selected by the lower 3 bits of NR43
+var noiseDivisors [8]int = [8]int{8, 16, 32, 48, 64, 80, 96, 112}
+
+//Noise channel (channel 4), output is driven by a linear feedback shift register that
+//can run in either 15-bit or 7-bit mode
+type NoiseChannel struct {
+	name       string
+	enabled    bool
+	dacEnabled bool
+
+	lfsr        uint16
+	widthMode   bool
+	clockShift  byte
+	divisorCode byte
+	timer       int
+	length      lengthCounter
+	envelope    volumeEnvelope
+}
+
+func NewNoiseChannel(name string) *NoiseChannel {
+	var n *NoiseChannel = new(NoiseChannel)
+	n.name = name
+	n.Reset()
+	return n
+}
+
+func (n *NoiseChannel) Name() string {
+	return n.name
+}
+
+func (n *NoiseChannel) Reset() {
+	n.enabled = false
+	n.dacEnabled = false
+	n.lfsr = 0x7FFF
+	n.widthMode = false
+	n.clockShift = 0
+	n.divisorCode = 0
+	n.timer = n.period()
+	n.length = newLengthCounter(64)
+	n.envelope.reset()
+}
+
+func (n *NoiseChannel) IsEnabled() bool {
+	return n.enabled
+}
+
+func (n *NoiseChannel) IsDACEnabled() bool {
+	return n.dacEnabled
+}
+
+//Returns the current digital output of the channel (0x0 - 0xF)
+func (n *NoiseChannel) Output() byte {
+	if !n.enabled || !n.dacEnabled {
+		return 0
+	}
+	//output is the inverse of bit 0
+	return byte(^n.lfsr&0x01) * n.envelope.volume
+}
+
+//Advances the frequency timer, each time it expires the LFSR is shifted
+func (n *NoiseChannel) Step(cycles int) {
+	n.timer -= cycles
+	for n.timer <= 0 {
+		n.timer += n.period()
+		//clock shifts of 14 and 15 stop the LFSR from being clocked
+		if n.clockShift < 14 {
+			n.clockLFSR()
+		}
+	}
+}
+
+//cycles until the frequency timer next expires
+func (n *NoiseChannel) timerRemaining() int {
+	return n.timer
+}
+
+func (n *NoiseChannel) period() int {
+	return noiseDivisors[n.divisorCode] << n.clockShift
+}
+
+//bits 0 and 1 are XORed and shifted in at the top, in 7-bit mode the result is also put in bit 6
+func (n *NoiseChannel) clockLFSR() {
+	var xor uint16 = (n.lfsr & 0x01) ^ ((n.lfsr >> 1) & 0x01)
+	n.lfsr = (n.lfsr >> 1) | (xor << 14)
+	if n.widthMode {
+		n.lfsr = (n.lfsr &^ 0x40) | (xor << 6)
+	}
+}
+
+//reg is relative to NR40 (0 = unused, 1 = length, 2 = envelope, 3 = polynomial counter, 4 = control)
+func (n *NoiseChannel) Write(reg int, value byte) {
+	switch reg {
+	case 1:
+		n.length.load(int(value & 0x3F))
+	case 2:
+		n.envelope.write(value)
+		n.dacEnabled = n.envelope.dacEnabled()
+		if !n.dacEnabled {
+			n.enabled = false
+		}
+	case 3:
+		n.clockShift = value >> 4
+		n.widthMode = value&0x08 == 0x08
+		n.divisorCode = value & 0x07
+	case 4:
+		n.length.enabled = value&0x40 == 0x40
+		if value&0x80 == 0x80 {
+			n.trigger()
+		}
+	}
+}
+
+func (n *NoiseChannel) trigger() {
+	n.enabled = n.dacEnabled
+	n.length.trigger()
+	n.timer = n.period()
+	n.envelope.trigger()
+	n.lfsr = 0x7FFF
+}
+
+//clocked at 256hz by the frame sequencer
+func (n *NoiseChannel) ClockLength() {
+	if n.length.clock() {
+		n.enabled = false
+	}
+}
+
+//clocked at 64hz by the frame sequencer
+func (n *NoiseChannel) ClockEnvelope() {
+	n.envelope.clock()
+}
@@ -0,0 +1,81 @@
+package apu
+
+import "math"
+
+//How much charge the output capacitor keeps per clock cycle, this acts as a high pass filter
+//that removes the DC offset from the output
+const HIGH_PASS_CHARGE float64 = 0.999958
+
+//Models the capacitor that the output of the mixer passes through
+type highPassFilter struct {
+	charge float64
+	factor float64
+}
+
+func (f *highPassFilter) apply(in float64) float64 {
+	out := in - f.charge
+	f.charge = in - out*f.factor
+	return out
+}
+
+//A stereo sample stream, changes in the left and right amplitude are resampled through a pair
+//of band limited buffers and filtered on the way out
+type stereoOutput struct {
+	leftBuffer  *BandLimitedBuffer
+	rightBuffer *BandLimitedBuffer
+	lastLeft    int
+	lastRight   int
+	leftFilter  highPassFilter
+	rightFilter highPassFilter
+}
+
+func newStereoOutput(sampleRate int) *stereoOutput {
+	o := new(stereoOutput)
+	o.leftBuffer = NewBandLimitedBuffer(CLOCK_RATE, sampleRate)
+	o.rightBuffer = NewBandLimitedBuffer(CLOCK_RATE, sampleRate)
+	o.reset()
+	return o
+}
+
+//Adds any change in amplitude since the last update at the given clock time
+func (o *stereoOutput) update(time int, left, right int) {
+	o.leftBuffer.AddDelta(time, float64((left-o.lastLeft)*PCM_SCALE))
+	o.rightBuffer.AddDelta(time, float64((right-o.lastRight)*PCM_SCALE))
+	o.lastLeft, o.lastRight = left, right
+}
+
+//Ends the frame after the given number of clocks and returns its interleaved samples
+func (o *stereoOutput) endFrame(clocks int) []int16 {
+	o.leftBuffer.EndFrame(clocks)
+	o.rightBuffer.EndFrame(clocks)
+
+	left := o.leftBuffer.ReadSamples()
+	right := o.rightBuffer.ReadSamples()
+	samples := make([]int16, len(left)*2)
+	for i := range left {
+		samples[i*2] = toPCM(o.leftFilter.apply(left[i]))
+		samples[i*2+1] = toPCM(o.rightFilter.apply(right[i]))
+	}
+	return samples
+}
+
+//Discards any buffered output and restarts the filters
+func (o *stereoOutput) reset() {
+	o.leftBuffer.Clear()
+	o.rightBuffer.Clear()
+	o.lastLeft, o.lastRight = 0, 0
+	factor := math.Pow(HIGH_PASS_CHARGE, float64(CLOCK_RATE)/float64(o.leftBuffer.SampleRate()))
+	o.leftFilter = highPassFilter{factor: factor}
+	o.rightFilter = highPassFilter{factor: factor}
+}
+
+func toPCM(sample float64) int16 {
+	switch {
+	case sample > math.MaxInt16:
+		return math.MaxInt16
+	case sample < math.MinInt16:
+		return math.MinInt16
+	default:
+		return int16(math.Round(sample))
+	}
+}
@@ -0,0 +1,209 @@
+package apu
+
+//Waveforms for the four selectable duty cycles (12.5%, 25%, 50%, 75%)
+var dutyPatterns [4][8]byte = [4][8]byte{
+	{0, 0, 0, 0, 0, 0, 0, 1},
+	{1, 0, 0, 0, 0, 0, 0, 1},
+	{1, 0, 0, 0, 0, 1, 1, 1},
+	{0, 1, 1, 1, 1, 1, 1, 0},
+}
+
+//Square wave channel (channels 1 and 2). Only channel 1 has a frequency sweep unit,
+//the registers are addressed relative to NRx0 so channel 2 just ignores register 0
+type PulseChannel struct {
+	name       string
+	hasSweep   bool
+	enabled    bool
+	dacEnabled bool
+
+	duty      byte
+	dutyStep  int
+	frequency int
+	timer     int
+	length    lengthCounter
+	envelope  volumeEnvelope
+
+	sweepPeriod     byte
+	sweepNegate     bool
+	sweepShift      byte
+	sweepTimer      byte
+	sweepEnabled    bool
+	sweepNegateUsed bool
+	shadowFrequency int
+}
+
+func NewPulseChannel(name string, hasSweep bool) *PulseChannel {
+	var p *PulseChannel = new(PulseChannel)
+	p.name = name
+	p.hasSweep = hasSweep
+	p.Reset()
+	return p
+}
+
+func (p *PulseChannel) Name() string {
+	return p.name
+}
+
+func (p *PulseChannel) Reset() {
+	p.enabled = false
+	p.dacEnabled = false
+	p.duty = 0
+	p.dutyStep = 0
+	p.frequency = 0
+	p.timer = p.period()
+	p.length = newLengthCounter(64)
+	p.envelope.reset()
+	p.sweepPeriod = 0
+	p.sweepNegate = false
+	p.sweepShift = 0
+	p.sweepTimer = 0
+	p.sweepEnabled = false
+	p.sweepNegateUsed = false
+	p.shadowFrequency = 0
+}
+
+func (p *PulseChannel) IsEnabled() bool {
+	return p.enabled
+}
+
+func (p *PulseChannel) IsDACEnabled() bool {
+	return p.dacEnabled
+}
+
+//Returns the current digital output of the channel (0x0 - 0xF)
+func (p *PulseChannel) Output() byte {
+	if !p.enabled || !p.dacEnabled {
+		return 0
+	}
+	return dutyPatterns[p.duty][p.dutyStep] * p.envelope.volume
+}
+
+//Advances the frequency timer, each time it expires the duty position moves on by one step
+func (p *PulseChannel) Step(cycles int) {
+	p.timer -= cycles
+	for p.timer <= 0 {
+		p.timer += p.period()
+		p.dutyStep = (p.dutyStep + 1) & 0x07
+	}
+}
+
+//cycles until the frequency timer next expires
+func (p *PulseChannel) timerRemaining() int {
+	return p.timer
+}
+
+func (p *PulseChannel) period() int {
+	return (2048 - p.frequency) * 4
+}
+
+//reg is relative to NRx0 (0 = sweep, 1 = duty/length, 2 = envelope, 3 = freq lo, 4 = freq hi/control)
+func (p *PulseChannel) Write(reg int, value byte) {
+	switch reg {
+	case 0:
+		if !p.hasSweep {
+			return
+		}
+		p.sweepPeriod = (value >> 4) & 0x07
+		negate := value&0x08 == 0x08
+		//leaving negate mode after a negate calculation has been made disables the channel
+		if p.sweepNegate && !negate && p.sweepNegateUsed {
+			p.enabled = false
+		}
+		p.sweepNegate = negate
+		p.sweepShift = value & 0x07
+	case 1:
+		p.duty = value >> 6
+		p.length.load(int(value & 0x3F))
+	case 2:
+		p.envelope.write(value)
+		p.dacEnabled = p.envelope.dacEnabled()
+		if !p.dacEnabled {
+			p.enabled = false
+		}
+	case 3:
+		p.frequency = (p.frequency & 0x700) | int(value)
+	case 4:
+		p.frequency = (p.frequency & 0xFF) | (int(value&0x07) << 8)
+		p.length.enabled = value&0x40 == 0x40
+		if value&0x80 == 0x80 {
+			p.trigger()
+		}
+	}
+}
+
+func (p *PulseChannel) trigger() {
+	p.enabled = p.dacEnabled
+	p.length.trigger()
+	p.timer = p.period()
+	p.envelope.trigger()
+
+	if p.hasSweep {
+		p.shadowFrequency = p.frequency
+		p.sweepTimer = p.sweepReloadValue()
+		p.sweepEnabled = p.sweepPeriod != 0 || p.sweepShift != 0
+		p.sweepNegateUsed = false
+		if p.sweepShift != 0 {
+			p.calculateSweep()
+		}
+	}
+}
+
+//clocked at 256hz by the frame sequencer
+func (p *PulseChannel) ClockLength() {
+	if p.length.clock() {
+		p.enabled = false
+	}
+}
+
+//clocked at 64hz by the frame sequencer
+func (p *PulseChannel) ClockEnvelope() {
+	p.envelope.clock()
+}
+
+//clocked at 128hz by the frame sequencer
+func (p *PulseChannel) ClockSweep() {
+	if !p.hasSweep {
+		return
+	}
+
+	if p.sweepTimer > 0 {
+		p.sweepTimer--
+	}
+
+	if p.sweepTimer == 0 {
+		p.sweepTimer = p.sweepReloadValue()
+		if p.sweepEnabled && p.sweepPeriod != 0 {
+			newFrequency := p.calculateSweep()
+			if newFrequency <= 2047 && p.sweepShift != 0 {
+				p.shadowFrequency = newFrequency
+				p.frequency = newFrequency
+				//overflow check is run again with the new frequency
+				p.calculateSweep()
+			}
+		}
+	}
+}
+
+//a sweep period of 0 is treated as 8 by the sweep timer
+func (p *PulseChannel) sweepReloadValue() byte {
+	if p.sweepPeriod == 0 {
+		return 8
+	}
+	return p.sweepPeriod
+}
+
+//calculates the next sweep frequency, disabling the channel if it overflows
+func (p *PulseChannel) calculateSweep() int {
+	var newFrequency int = p.shadowFrequency >> p.sweepShift
+	if p.sweepNegate {
+		newFrequency = p.shadowFrequency - newFrequency
+		p.sweepNegateUsed = true
+	} else {
+		newFrequency = p.shadowFrequency + newFrequency
+	}
+
+	if newFrequency > 2047 {
+		p.enabled = false
+	}
+	return newFrequency
+}
@@ -0,0 +1,160 @@
+package apu
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"sync"
+
+	"github.com/djhworld/gomeboycolor/types"
+)
+
+//VGM files are timed in samples at 44100hz
+const VGM_SAMPLE_RATE int = 44100
+
+const (
+	VGM_VERSION     uint32 = 0x161 //first version with Game Boy DMG support
+	VGM_HEADER_SIZE int    = 0x100
+)
+
+//VGM commands
+const (
+	VGM_GB_DMG_WRITE byte = 0xB3 //register (relative to 0xFF10), value
+	VGM_WAIT         byte = 0x61 //16-bit number of samples
+	VGM_WAIT_60HZ    byte = 0x62 //735 samples
+	VGM_WAIT_50HZ    byte = 0x63 //882 samples
+	VGM_WAIT_SHORT   byte = 0x70 //0x70 - 0x7F wait 1 to 16 samples
+	VGM_END          byte = 0x66
+)
+
+type RegisterWrite struct {
+	Clock   uint64 //APU clock relative to the start of the recording
+	Address types.Word
+	Value   byte
+}
+
+//Logs every write made to the APU so that it can be exported as a VGM file. It must be registered
+//as an observer of the APU it was created for, it can be stopped from another goroutine
+type VGMRecorder struct {
+	lock       sync.Mutex
+	startClock uint64
+	endClock   uint64
+	stopped    bool
+	writes     []RegisterWrite
+}
+
+//Starts the log with the current state of the APU so that playback begins from the same state,
+//channels are not retriggered so any notes already playing are not heard
+func NewVGMRecorder(apu *APU) *VGMRecorder {
+	r := &VGMRecorder{startClock: apu.clock}
+
+	var power byte = 0x00
+	if apu.powered {
+		power = 0x80
+	}
+	r.log(0, NR52, power)
+
+	for i := 0; i <= WAVE_RAM_END-WAVE_RAM_START; i++ {
+		r.log(0, types.Word(WAVE_RAM_START+i), apu.channel3.waveRAM[i])
+	}
+
+	for addr := NR10; addr <= NR51; addr++ {
+		var value byte = apu.registers[addr-NR10]
+		if addr == NR14 || addr == NR24 || addr == NR34 || addr == NR44 {
+			value &^= 0x80
+		}
+		r.log(0, addr, value)
+	}
+	return r
+}
+
+func (r *VGMRecorder) OnAPUWrite(clock uint64, addr types.Word, value byte) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if !r.stopped {
+		r.log(clock-r.startClock, addr, value)
+	}
+}
+
+//Stops logging writes, endClock is the APU clock the recording finished on
+func (r *VGMRecorder) Stop(endClock uint64) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if !r.stopped {
+		r.stopped = true
+		r.endClock = endClock
+	}
+}
+
+func (r *VGMRecorder) log(clock uint64, addr types.Word, value byte) {
+	r.writes = append(r.writes, RegisterWrite{clock, addr, value})
+}
+
+func (r *VGMRecorder) Writes() []RegisterWrite {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.writes
+}
+
+func toVGMSamples(clock uint64) uint32 {
+	return uint32(clock * uint64(VGM_SAMPLE_RATE) / uint64(CLOCK_RATE))
+}
+
+//Writes the log as a VGM file, the recording must have been stopped first
+func (r *VGMRecorder) WriteVGM(w io.Writer) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if !r.stopped {
+		return errors.New(PREFIX + " VGM recording must be stopped before it can be written")
+	}
+
+	var data []byte
+	var position uint32
+
+	for _, write := range r.writes {
+		data = appendVGMWait(data, toVGMSamples(write.Clock)-position)
+		position = toVGMSamples(write.Clock)
+		data = append(data, VGM_GB_DMG_WRITE, byte(write.Address-NR10), write.Value)
+	}
+
+	var totalSamples uint32 = toVGMSamples(r.endClock - r.startClock)
+	if totalSamples > position {
+		data = appendVGMWait(data, totalSamples-position)
+	}
+	data = append(data, VGM_END)
+
+	header := make([]byte, VGM_HEADER_SIZE)
+	copy(header[0x00:], "Vgm ")
+	binary.LittleEndian.PutUint32(header[0x04:], uint32(VGM_HEADER_SIZE+len(data)-0x04))
+	binary.LittleEndian.PutUint32(header[0x08:], VGM_VERSION)
+	binary.LittleEndian.PutUint32(header[0x18:], totalSamples)
+	binary.LittleEndian.PutUint32(header[0x34:], uint32(VGM_HEADER_SIZE-0x34))
+	binary.LittleEndian.PutUint32(header[0x80:], uint32(CLOCK_RATE))
+
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+	_, err := w.Write(data)
+	return err
+}
+
+func appendVGMWait(data []byte, samples uint32) []byte {
+	for samples > 0 {
+		switch {
+		case samples <= 16:
+			return append(data, VGM_WAIT_SHORT+byte(samples-1))
+		case samples == 735:
+			return append(data, VGM_WAIT_60HZ)
+		case samples == 882:
+			return append(data, VGM_WAIT_50HZ)
+		default:
+			var n uint32 = samples
+			if n > 0xFFFF {
+				n = 0xFFFF
+			}
+			data = append(data, VGM_WAIT, byte(n), byte(n>>8))
+			samples -= n
+		}
+	}
+	return data
+}
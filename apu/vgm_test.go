@@ -0,0 +1,111 @@
+package apu
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchrcom/testify/assert"
+)
+
+func TestVGMRecorderStartsWithCurrentState(t *testing.T) {
+	//given
+	a := NewAPU()
+	a.Write(NR52, 0x80)
+	a.Write(NR12, 0xF0)
+	a.Write(NR14, 0x87)
+	a.Write(WAVE_RAM_START, 0x12)
+
+	//when
+	writes := NewVGMRecorder(a).Writes()
+
+	//then
+	assert.Equal(t, RegisterWrite{0, NR52, 0x80}, writes[0])
+	assert.Contains(t, writes, RegisterWrite{0, WAVE_RAM_START, 0x12})
+	assert.Contains(t, writes, RegisterWrite{0, NR12, 0xF0})
+	//channels are not retriggered
+	assert.Contains(t, writes, RegisterWrite{0, NR14, 0x07})
+}
+
+func TestVGMRecorderLogsWritesWithClock(t *testing.T) {
+	//given
+	a := NewAPU()
+	a.Step(1000)
+	r := NewVGMRecorder(a)
+	a.RegisterObserver(r)
+
+	//when
+	a.Step(CLOCK_RATE)
+	a.Write(NR52, 0x80)
+	a.Write(NR50, 0x77)
+	a.Write(PCM12, 0xFF)
+	a.UnregisterObserver(r)
+	a.Write(NR51, 0xFF)
+
+	//then
+	writes := r.Writes()
+	assert.Equal(t, []RegisterWrite{
+		{uint64(CLOCK_RATE), NR52, 0x80},
+		{uint64(CLOCK_RATE), NR50, 0x77},
+	}, writes[len(writes)-2:])
+}
+
+func TestVGMRecorderIgnoresWritesOnceStopped(t *testing.T) {
+	//given
+	a := NewAPU()
+	r := NewVGMRecorder(a)
+	a.RegisterObserver(r)
+	count := len(r.Writes())
+
+	//when
+	r.Stop(a.Clock())
+	a.Write(NR50, 0x77)
+
+	//then
+	assert.Equal(t, count, len(r.Writes()))
+}
+
+func TestWriteVGM(t *testing.T) {
+	//given
+	a := NewAPU()
+	r := NewVGMRecorder(a)
+	a.RegisterObserver(r)
+	a.Step(69906) //just over 735 samples
+	a.Write(NR50, 0x77)
+	a.Step(CLOCK_RATE)
+	r.Stop(a.Clock())
+
+	//when
+	var buf bytes.Buffer
+	assert.Nil(t, r.WriteVGM(&buf))
+	vgm := buf.Bytes()
+
+	//then
+	assert.Equal(t, "Vgm ", string(vgm[0x00:0x04]))
+	assert.Equal(t, uint32(len(vgm)-4), binary.LittleEndian.Uint32(vgm[0x04:]))
+	assert.Equal(t, VGM_VERSION, binary.LittleEndian.Uint32(vgm[0x08:]))
+	assert.Equal(t, uint32(735+44100), binary.LittleEndian.Uint32(vgm[0x18:]))
+	assert.Equal(t, uint32(VGM_HEADER_SIZE-0x34), binary.LittleEndian.Uint32(vgm[0x34:]))
+	assert.Equal(t, uint32(CLOCK_RATE), binary.LittleEndian.Uint32(vgm[0x80:]))
+
+	//the initial state is followed by a 60hz wait, the write, a second of waiting and the end
+	data := vgm[VGM_HEADER_SIZE:]
+	tail := []byte{VGM_WAIT_60HZ, VGM_GB_DMG_WRITE, 0x14, 0x77, VGM_WAIT, 0x44, 0xAC, VGM_END}
+	assert.Equal(t, tail, data[len(data)-len(tail):])
+	assert.Equal(t, []byte{VGM_GB_DMG_WRITE, byte(NR52 - NR10), 0x00}, data[0:3])
+}
+
+func TestWriteVGMMustBeStopped(t *testing.T) {
+	r := NewVGMRecorder(NewAPU())
+	var buf bytes.Buffer
+	assert.NotNil(t, r.WriteVGM(&buf))
+}
+
+func TestVGMWaits(t *testing.T) {
+	assert.Equal(t, []byte{0x70}, appendVGMWait(nil, 1))
+	assert.Equal(t, []byte{0x7F}, appendVGMWait(nil, 16))
+	assert.Equal(t, []byte{VGM_WAIT_50HZ}, appendVGMWait(nil, 882))
+	assert.Equal(t, []byte{VGM_WAIT, 0x11, 0x00}, appendVGMWait(nil, 17))
+	assert.Equal(t, []byte{VGM_WAIT, 0xFF, 0xFF, VGM_WAIT, 0x71, 0x11}, appendVGMWait(nil, 70000))
+	assert.Equal(t, 0, len(appendVGMWait(nil, 0)))
+}
@@ -0,0 +1,155 @@
+package apu
+
+//Right shifts applied to the 4-bit samples for each output level in NR32 (mute, 100%, 50%, 25%)
+var waveVolumeShifts [4]byte = [4]byte{4, 0, 1, 2}
+
+//Wave channel (channel 3), plays back 32 4-bit samples held in wave pattern RAM (0xFF30 -> 0xFF3F)
+type WaveChannel struct {
+	name       string
+	enabled    bool
+	dacEnabled bool
+
+	waveRAM      [16]byte
+	position     int
+	sampleBuffer byte
+	volumeCode   byte
+	frequency    int
+	timer        int
+	length       lengthCounter
+}
+
+func NewWaveChannel(name string) *WaveChannel {
+	var w *WaveChannel = new(WaveChannel)
+	w.name = name
+	w.Reset()
+	return w
+}
+
+func (w *WaveChannel) Name() string {
+	return w.name
+}
+
+//Wave RAM is not cleared on reset, it holds whatever it had before
+func (w *WaveChannel) Reset() {
+	w.enabled = false
+	w.dacEnabled = false
+	w.position = 0
+	w.sampleBuffer = 0
+	w.volumeCode = 0
+	w.frequency = 0
+	w.timer = w.period()
+	w.length = newLengthCounter(256)
+}
+
+func (w *WaveChannel) IsEnabled() bool {
+	return w.enabled
+}
+
+func (w *WaveChannel) IsDACEnabled() bool {
+	return w.dacEnabled
+}
+
+//Returns the current digital output of the channel (0x0 - 0xF)
+func (w *WaveChannel) Output() byte {
+	if !w.enabled || !w.dacEnabled {
+		return 0
+	}
+	return w.sampleBuffer >> waveVolumeShifts[w.volumeCode]
+}
+
+//Advances the frequency timer, each time it expires the next sample is read into the sample buffer
+func (w *WaveChannel) Step(cycles int) {
+	w.timer -= cycles
+	for w.timer <= 0 {
+		w.timer += w.period()
+		if w.enabled {
+			w.position = (w.position + 1) & 0x1F
+			w.sampleBuffer = w.sample(w.position)
+		}
+	}
+}
+
+//cycles until the frequency timer next expires
+func (w *WaveChannel) timerRemaining() int {
+	return w.timer
+}
+
+func (w *WaveChannel) period() int {
+	return (2048 - w.frequency) * 2
+}
+
+//samples are stored high nibble first
+func (w *WaveChannel) sample(position int) byte {
+	b := w.waveRAM[position/2]
+	if position&0x01 == 0x00 {
+		return b >> 4
+	}
+	return b & 0x0F
+}
+
+//reg is relative to NR30 (0 = DAC, 1 = length, 2 = output level, 3 = freq lo, 4 = freq hi/control)
+func (w *WaveChannel) Write(reg int, value byte) {
+	switch reg {
+	case 0:
+		w.dacEnabled = value&0x80 == 0x80
+		if !w.dacEnabled {
+			w.enabled = false
+		}
+	case 1:
+		w.length.load(int(value))
+	case 2:
+		w.volumeCode = (value >> 5) & 0x03
+	case 3:
+		w.frequency = (w.frequency & 0x700) | int(value)
+	case 4:
+		w.frequency = (w.frequency & 0xFF) | (int(value&0x07) << 8)
+		w.length.enabled = value&0x40 == 0x40
+		if value&0x80 == 0x80 {
+			w.trigger()
+		}
+	}
+}
+
+func (w *WaveChannel) trigger() {
+	w.enabled = w.dacEnabled
+	w.length.trigger()
+	//the first sample is not read until the timer first expires, leaving the old value in the buffer
+	w.timer = w.period() + 6
+	w.position = 0
+}
+
+//clocked at 256hz by the frame sequencer
+func (w *WaveChannel) ClockLength() {
+	if w.length.clock() {
+		w.enabled = false
+	}
+}
+
+//While the channel is playing wave RAM accesses go to the byte the channel is currently reading.
+//CGB hardware allows this at any time, DMG hardware only allows it on the cycle the channel
+//reads the byte, otherwise reads return 0xFF and writes are ignored
+func (w *WaveChannel) ReadWaveRAM(index int, isColor bool) byte {
+	if w.enabled {
+		if isColor || w.isReadingWaveRAM() {
+			return w.waveRAM[w.position/2]
+		}
+		return 0xFF
+	}
+	return w.waveRAM[index]
+}
+
+func (w *WaveChannel) WriteWaveRAM(index int, value byte, isColor bool) {
+	if w.enabled {
+		if isColor || w.isReadingWaveRAM() {
+			w.waveRAM[w.position/2] = value
+		}
+		return
+	}
+	w.waveRAM[index] = value
+}
+
+//true when the channel fetched a sample within the last couple of cycles
+func (w *WaveChannel) isReadingWaveRAM() bool {
+	elapsed := w.period() - w.timer
+	return elapsed >= 0 && elapsed < 2
+}
@@ -0,0 +1,538 @@
+package asm
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/djhworld/gomeboycolor/cpu"
+	"github.com/djhworld/gomeboycolor/types"
+)
+
+const CB_PREFIX byte = 0xCB
+
+//An error in a line of source
+type Error struct {
+	//line number within the source, counting from 1
+	Line    int
+	Source  string
+	Message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("line %d: %s: %s", e.Line, strings.TrimSpace(e.Source), e.Message)
+}
+
+//One way of encoding an instruction, taken from the CPU instruction tables
+type encoding struct {
+	opcode []byte
+
+	//what each operand must be, either a register, condition or RST vector that has to be
+	//given as is or one of the immediate placeholders used by the tables (e.g. "d8", "(a16)")
+	operands []string
+
+	//number of immediate bytes that follow the opcode
+	size int
+
+	//set for JR, which is given the address to jump to and encodes the offset from the next
+	//instruction
+	relative bool
+}
+
+//every encoding of each mnemonic
+var encodings map[string][]encoding = buildEncodings()
+
+func buildEncodings() map[string][]encoding {
+	var result map[string][]encoding = make(map[string][]encoding)
+	add := func(opcode []byte, instr *cpu.Instruction) {
+		var fields []string = strings.Fields(instr.Description)
+		var e encoding = encoding{opcode: opcode, size: instr.OperandsSize, relative: fields[0] == "JR"}
+		if len(fields) > 1 {
+			e.operands = strings.Split(strings.Join(fields[1:], ""), ",")
+		}
+		result[fields[0]] = append(result[fields[0]], e)
+	}
+
+	for i, instr := range cpu.Instructions {
+		if instr != cpu.EMPTY_INSTRUCTION && byte(i) != CB_PREFIX {
+			add([]byte{byte(i)}, instr)
+		}
+	}
+	for i, instr := range cpu.InstructionsCB {
+		add([]byte{CB_PREFIX, byte(i)}, instr)
+	}
+	return result
+}
+
+//Names that are always registers or conditions and so can't be used as labels
+var reserved map[string]bool = map[string]bool{
+	"A": true, "B": true, "C": true, "D": true, "E": true, "H": true, "L": true,
+	"AF": true, "BC": true, "DE": true, "HL": true, "SP": true, "NZ": true, "Z": true, "NC": true,
+}
+
+//Other ways of writing the operands used in the instruction tables
+var operandAliases map[string]string = map[string]string{
+	"(HLI)":      "(HL+)",
+	"(HLD)":      "(HL-)",
+	"(0XFF00+C)": "(C)",
+	"($FF00+C)":  "(C)",
+	"(FF00+C)":   "(C)",
+}
+
+//A parsed line, the instruction is picked on the first pass once every label before it is
+//known and its operands are evaluated on the second
+type statement struct {
+	line    int
+	source  string
+	address types.Word
+
+	directive string
+	encoding  *encoding
+	args      []string
+	size      int
+}
+
+//Assembles source into machine code to be loaded at origin.
+//
+//Each line holds one instruction written the way the disassembler shows it (e.g.
+//"LD A,(0xFF44)", "JR NZ,0x0150", "LD HL,SP+0x02"), optionally preceded by a label ("loop:")
+//and followed by a comment starting with ';'. Numbers can be given in hex (0x10 or $10),
+//binary (%1010) or decimal, and labels can be used anywhere an address or value is expected.
+//JR takes the address to jump to rather than the offset, and LD to or from an address in
+//0xFF00-0xFFFF is assembled as the shorter LDH.
+//
+//ORG moves on to a later address (the gap is filled with zeros), DB emits bytes or quoted
+//strings and DW emits little endian words
+func Assemble(source string, origin types.Word) ([]byte, error) {
+	var statements []*statement
+	var labels map[string]int = make(map[string]int)
+	var address int = int(origin)
+
+	for i, text := range strings.Split(source, "\n") {
+		s, label, err := parseLine(text, address)
+		if err != nil {
+			return nil, &Error{i + 1, text, err.Error()}
+		}
+
+		if label != "" {
+			if _, ok := labels[label]; ok {
+				return nil, &Error{i + 1, text, fmt.Sprintf("label %s is already defined", label)}
+			}
+			labels[label] = address
+		}
+
+		if s == nil {
+			continue
+		}
+		s.line, s.source = i+1, text
+
+		if s.directive == "ORG" {
+			target, err := evaluate(s.args[0], labels)
+			if err != nil {
+				return nil, &Error{i + 1, text, err.Error()}
+			}
+			if target < address {
+				return nil, &Error{i + 1, text, fmt.Sprintf("ORG can't move from 0x%04X to 0x%04X", address, target)}
+			}
+			s.size = target - address
+		}
+
+		statements = append(statements, s)
+		address += s.size
+	}
+
+	var out []byte
+	for _, s := range statements {
+		code, err := s.assemble(labels)
+		if err != nil {
+			return nil, &Error{s.line, s.source, err.Error()}
+		}
+		out = append(out, code...)
+	}
+	return out, nil
+}
+
+//Like Assemble but panics on an error, for building programs in tests
+func MustAssemble(source string, origin types.Word) []byte {
+	code, err := Assemble(source, origin)
+	if err != nil {
+		panic(err)
+	}
+	return code
+}
+
+//Splits a line into its label and statement and works out how many bytes it takes, the
+//statement is nil when the line has nothing to assemble
+func parseLine(text string, address int) (*statement, string, error) {
+	text = strings.TrimSpace(stripComment(text))
+
+	var label string
+	if i := strings.Index(text, ":"); i > 0 && isIdentifier(text[:i]) {
+		label, text = text[:i], strings.TrimSpace(text[i+1:])
+		if reserved[strings.ToUpper(label)] {
+			return nil, "", fmt.Errorf("%s is a register and can't be used as a label", label)
+		}
+	}
+	if text == "" {
+		return nil, label, nil
+	}
+
+	var mnemonic, rest string = text, ""
+	if i := strings.IndexAny(text, " \t"); i >= 0 {
+		mnemonic, rest = text[:i], strings.TrimSpace(text[i+1:])
+	}
+	mnemonic = strings.ToUpper(mnemonic)
+	var args []string = splitOperands(rest)
+
+	var s *statement = &statement{address: types.Word(address), args: args}
+	switch mnemonic {
+	case "ORG":
+		if len(args) != 1 {
+			return nil, "", fmt.Errorf("ORG takes an address")
+		}
+		s.directive = mnemonic
+	case "DB", "DW":
+		if len(args) == 0 {
+			return nil, "", fmt.Errorf("%s needs at least one value", mnemonic)
+		}
+		s.directive = mnemonic
+		for _, arg := range args {
+			if mnemonic == "DB" && strings.HasPrefix(arg, "\"") {
+				str, err := strconv.Unquote(arg)
+				if err != nil {
+					return nil, "", fmt.Errorf("bad string %s", arg)
+				}
+				s.size += len(str)
+			} else if mnemonic == "DB" {
+				s.size++
+			} else {
+				s.size += 2
+			}
+		}
+	default:
+		e, err := selectEncoding(mnemonic, args)
+		if err != nil {
+			return nil, "", err
+		}
+		s.encoding = e
+		s.size = len(e.opcode) + e.size
+	}
+	return s, label, nil
+}
+
+//Picks the encoding of the mnemonic that takes operands of the kind given
+func selectEncoding(mnemonic string, args []string) (*encoding, error) {
+	for i, arg := range args {
+		args[i] = normaliseOperand(arg)
+	}
+
+	var candidates []encoding
+	switch mnemonic {
+	case "LD":
+		//the address has to be known up front so labels always get the full 16 bit address
+		for _, arg := range args {
+			if inner, ok := indirect(arg); ok {
+				if value, err := parseNumber(inner); err == nil && value >= 0xFF00 && value <= 0xFFFF {
+					candidates = append(candidates, encodings["LDH"]...)
+				}
+			}
+		}
+		candidates = append(candidates, encodings["LD"]...)
+	case "LDH":
+		candidates = append(candidates, encodings["LDH"]...)
+		for _, e := range encodings["LD"] {
+			if e.operands[0] == "(C)" || e.operands[1] == "(C)" {
+				candidates = append(candidates, e)
+			}
+		}
+	case "LDI", "LDD":
+		//LDI (HL),A is another way of writing LD (HL+),A
+		var replacement string = map[string]string{"LDI": "(HL+)", "LDD": "(HL-)"}[mnemonic]
+		for i, arg := range args {
+			if arg == "(HL)" {
+				args[i] = replacement
+			}
+		}
+		candidates = encodings["LD"]
+	default:
+		var ok bool
+		if candidates, ok = encodings[mnemonic]; !ok {
+			return nil, fmt.Errorf("unknown instruction %s", mnemonic)
+		}
+	}
+
+	for i := range candidates {
+		if matches(candidates[i].operands, args) {
+			return &candidates[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%s can't take operands %s", mnemonic, strings.Join(args, ","))
+}
+
+//Whether each operand is of the kind the encoding takes. Only bit numbers and RST vectors
+//are compared by value, the rest may refer to labels that haven't been seen yet
+func matches(patterns []string, args []string) bool {
+	if len(patterns) != len(args) {
+		return false
+	}
+	for i, pattern := range patterns {
+		var arg string = args[i]
+		switch pattern {
+		case "d16", "a16", "d8", "n", "r8":
+			if !isValue(arg) {
+				return false
+			}
+		case "(a16)", "(a8)":
+			if inner, ok := indirect(arg); !ok || !isValue(inner) {
+				return false
+			}
+		case "SP+r8":
+			if !isSPOffset(arg) {
+				return false
+			}
+		default:
+			if number, err := parseNumber(pattern); err == nil {
+				if value, err := parseNumber(arg); err != nil || value != number {
+					return false
+				}
+			} else if arg != pattern {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+//Whether an operand is a number or label rather than a register, condition or memory operand
+func isValue(arg string) bool {
+	if _, ok := indirect(arg); ok || isSPOffset(arg) {
+		return false
+	}
+	return !reserved[strings.TrimRight(arg, "+-")]
+}
+
+func isSPOffset(arg string) bool {
+	return strings.HasPrefix(arg, "SP+") || strings.HasPrefix(arg, "SP-")
+}
+
+func (s *statement) assemble(labels map[string]int) ([]byte, error) {
+	switch s.directive {
+	case "ORG":
+		return make([]byte, s.size), nil
+	case "DB":
+		var out []byte
+		for _, arg := range s.args {
+			if strings.HasPrefix(arg, "\"") {
+				str, _ := strconv.Unquote(arg)
+				out = append(out, str...)
+				continue
+			}
+			value, err := evaluateRange(arg, labels, -0x80, 0xFF)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, byte(value))
+		}
+		return out, nil
+	case "DW":
+		var out []byte
+		for _, arg := range s.args {
+			value, err := evaluateRange(arg, labels, -0x8000, 0xFFFF)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, byte(value), byte(value>>8))
+		}
+		return out, nil
+	}
+
+	var out []byte = append([]byte(nil), s.encoding.opcode...)
+	var end int = int(s.address) + s.size
+	for i, pattern := range s.encoding.operands {
+		var arg string = s.args[i]
+		switch pattern {
+		case "d16", "a16", "(a16)":
+			if inner, ok := indirect(arg); ok {
+				arg = inner
+			}
+			value, err := evaluateRange(arg, labels, -0x8000, 0xFFFF)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, byte(value), byte(value>>8))
+		case "d8", "n":
+			value, err := evaluateRange(arg, labels, -0x80, 0xFF)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, byte(value))
+		case "(a8)":
+			inner, _ := indirect(arg)
+			value, err := evaluate(inner, labels)
+			if err != nil {
+				return nil, err
+			}
+			if value < 0xFF00 && value > 0xFF || value > 0xFFFF {
+				return nil, fmt.Errorf("0x%X is not in 0xFF00-0xFFFF", value)
+			}
+			out = append(out, byte(value))
+		case "r8":
+			var value int
+			var err error
+			if !s.encoding.relative {
+				//ADD SP,r8 takes a signed offset
+				value, err = evaluateRange(arg, labels, -0x80, 0x7F)
+			} else {
+				var target int
+				if target, err = evaluate(arg, labels); err == nil {
+					value = target - end
+					if value < -0x80 || value > 0x7F {
+						err = fmt.Errorf("0x%04X is too far away to jump to with JR", target)
+					}
+				}
+			}
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, byte(value))
+		case "SP+r8":
+			value, err := evaluateRange(strings.TrimPrefix(arg, "SP"), labels, -0x80, 0x7F)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, byte(value))
+		}
+	}
+
+	//STOP is followed by a padding byte
+	for len(out) < s.size {
+		out = append(out, 0x00)
+	}
+	return out, nil
+}
+
+func evaluateRange(expr string, labels map[string]int, min, max int) (int, error) {
+	value, err := evaluate(expr, labels)
+	if err != nil {
+		return 0, err
+	}
+	if value < min || value > max {
+		return 0, fmt.Errorf("%s is out of range", expr)
+	}
+	return value, nil
+}
+
+//Evaluates a number or label, with an optional sign in front
+func evaluate(expr string, labels map[string]int) (int, error) {
+	var sign int = 1
+	if strings.HasPrefix(expr, "-") {
+		sign, expr = -1, expr[1:]
+	} else if strings.HasPrefix(expr, "+") {
+		expr = expr[1:]
+	}
+
+	if value, err := parseNumber(expr); err == nil {
+		return sign * value, nil
+	}
+	if value, ok := labels[expr]; ok {
+		return sign * value, nil
+	}
+	if isIdentifier(expr) {
+		return 0, fmt.Errorf("undefined label %s", expr)
+	}
+	return 0, fmt.Errorf("bad value %s", expr)
+}
+
+func parseNumber(s string) (int, error) {
+	var base int = 10
+	switch {
+	case strings.HasPrefix(s, "0x"), strings.HasPrefix(s, "0X"):
+		s, base = s[2:], 16
+	case strings.HasPrefix(s, "$"):
+		s, base = s[1:], 16
+	case strings.HasPrefix(s, "%"):
+		s, base = s[1:], 2
+	}
+	value, err := strconv.ParseUint(s, base, 16)
+	return int(value), err
+}
+
+func isIdentifier(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, r := range s {
+		if !(r == '_' || r == '.' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || i > 0 && r >= '0' && r <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
+//Registers and conditions are upper cased and spaces removed so they can be compared with the
+//instruction tables, labels keep their case
+func normaliseOperand(arg string) string {
+	arg = strings.Join(strings.Fields(arg), "")
+	if strings.HasPrefix(arg, "[") && strings.HasSuffix(arg, "]") {
+		arg = "(" + arg[1:len(arg)-1] + ")"
+	}
+
+	var upper string = strings.ToUpper(arg)
+	if alias, ok := operandAliases[upper]; ok {
+		return alias
+	}
+	if isSPOffset(upper) {
+		return "SP" + arg[2:]
+	}
+
+	var register string = upper
+	if inner, ok := indirect(upper); ok {
+		register = inner
+	}
+	if reserved[strings.TrimRight(register, "+-")] {
+		return upper
+	}
+	return arg
+}
+
+//Returns what's inside the brackets of an operand such as (0xFF44)
+func indirect(arg string) (string, bool) {
+	if strings.HasPrefix(arg, "(") && strings.HasSuffix(arg, ")") {
+		return arg[1 : len(arg)-1], true
+	}
+	return "", false
+}
+
+func stripComment(text string) string {
+	var quoted bool
+	for i, r := range text {
+		switch {
+		case r == '"' && (i == 0 || text[i-1] != '\\'):
+			quoted = !quoted
+		case r == ';' && !quoted:
+			return text[:i]
+		}
+	}
+	return text
+}
+
+//Splits operands on commas that aren't inside a string
+func splitOperands(text string) []string {
+	if text == "" {
+		return nil
+	}
+
+	var args []string
+	var quoted bool
+	var start int
+	for i, r := range text {
+		switch {
+		case r == '"' && (i == 0 || text[i-1] != '\\'):
+			quoted = !quoted
+		case r == ',' && !quoted:
+			args = append(args, strings.TrimSpace(text[start:i]))
+			start = i + 1
+		}
+	}
+	return append(args, strings.TrimSpace(text[start:]))
+}
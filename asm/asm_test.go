@@ -0,0 +1,152 @@
+package asm
+
+import (
+	"testing"
+
+	"github.com/djhworld/gomeboycolor/disasm"
+	"github.com/djhworld/gomeboycolor/types"
+	"github.com/stretchrcom/testify/assert"
+)
+
+func TestAssembleInstructions(t *testing.T) {
+	var tests = []struct {
+		source   string
+		expected []byte
+	}{
+		{"NOP", []byte{0x00}},
+		{"ld a, 0x91", []byte{0x3E, 0x91}},
+		{"LD B,10", []byte{0x06, 0x0A}},
+		{"LD A,-1", []byte{0x3E, 0xFF}},
+		{"LD HL,$1234", []byte{0x21, 0x34, 0x12}},
+		{"LD A,(0xC000)", []byte{0xFA, 0x00, 0xC0}},
+		{"LD A,(0xFF44)", []byte{0xF0, 0x44}},
+		{"LDH A,(0x44)", []byte{0xF0, 0x44}},
+		{"LD (0xFF40),A", []byte{0xE0, 0x40}},
+		{"LD [$FF40],A", []byte{0xE0, 0x40}},
+		{"LD (0xFF00+C),A", []byte{0xE2}},
+		{"LD A,(C)", []byte{0xF2}},
+		{"LDH (C),A", []byte{0xE2}},
+		{"LD (0xDFFE),SP", []byte{0x08, 0xFE, 0xDF}},
+		{"LD (HL+),A", []byte{0x22}},
+		{"LDI (HL),A", []byte{0x22}},
+		{"LD A,(HLD)", []byte{0x3A}},
+		{"LD (HL),0x42", []byte{0x36, 0x42}},
+		{"LD HL,SP+0x05", []byte{0xF8, 0x05}},
+		{"LD HL,SP-2", []byte{0xF8, 0xFE}},
+		{"ADD SP,-0x02", []byte{0xE8, 0xFE}},
+		{"JR 0x0100", []byte{0x18, 0xFE}},
+		{"JR NZ,0x0105", []byte{0x20, 0x03}},
+		{"JR C,0x00F0", []byte{0x38, 0xEE}},
+		{"JP (HL)", []byte{0xE9}},
+		{"CALL NC,0x4000", []byte{0xD4, 0x00, 0x40}},
+		{"RST 0x38", []byte{0xFF}},
+		{"RST $08", []byte{0xCF}},
+		{"STOP", []byte{0x10, 0x00}},
+		{"BIT 7,H", []byte{0xCB, 0x7C}},
+		{"SWAP A", []byte{0xCB, 0x37}},
+		{"RES 0,(HL)", []byte{0xCB, 0x86}},
+		{"DB 0xD3", []byte{0xD3}},
+		{"   ; nothing but a comment", nil},
+	}
+
+	for _, test := range tests {
+		code, err := Assemble(test.source, 0x0100)
+		assert.Nil(t, err, test.source)
+		assert.Equal(t, test.expected, code, test.source)
+	}
+}
+
+//Every instruction the disassembler shows should assemble back to the same bytes
+func TestAssembleDisassembly(t *testing.T) {
+	for opcode := 0; opcode <= 0xFF; opcode++ {
+		var data [][]byte = [][]byte{{byte(opcode), 0x34, 0x12}, {CB_PREFIX, byte(opcode)}}
+		if opcode == 0x10 {
+			//the byte after STOP is always assembled as 0
+			data[0][1] = 0x00
+		}
+
+		for _, d := range data {
+			instr, err := disasm.Decode(d, 0x0200)
+			assert.Nil(t, err)
+			if instr.Illegal {
+				continue
+			}
+
+			code, err := Assemble(instr.String(), 0x0200)
+			assert.Nil(t, err, instr.String())
+			assert.Equal(t, instr.Bytes, code, instr.String())
+		}
+	}
+}
+
+func TestAssembleProgram(t *testing.T) {
+	code, err := Assemble(`
+		JP main            ; skip over the data
+	message:
+		DB "Hi", 0
+		DW message, 0xBEEF
+	main:
+		LD HL,message
+	loop: LD A,(HL+)
+		OR A
+		JR NZ,loop
+		ORG 0x0120
+		CALL main
+	`, 0x0100)
+
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{
+		0xC3, 0x0A, 0x01, //JP 0x010A
+		'H', 'i', 0x00,
+		0x03, 0x01, 0xEF, 0xBE,
+		0x21, 0x03, 0x01, //LD HL,0x0103
+		0x2A,       //LD A,(HL+)
+		0xB7,       //OR A
+		0x20, 0xFC, //JR NZ,0x010D
+	}, code[:0x11])
+	assert.Equal(t, make([]byte, 0x0F), code[0x11:0x20])
+	assert.Equal(t, []byte{0xCD, 0x0A, 0x01}, code[0x20:])
+}
+
+func TestMustAssemble(t *testing.T) {
+	assert.Equal(t, []byte{0xC9}, MustAssemble("RET", 0x0000))
+	assert.Panics(t, func() {
+		MustAssemble("RET Q", 0x0000)
+	})
+}
+
+func TestAssembleErrors(t *testing.T) {
+	var tests = []struct {
+		source  string
+		line    int
+		message string
+	}{
+		{"NOP\nFOO A", 2, "unknown instruction FOO"},
+		{"LD Q,A", 1, "LD can't take operands Q,A"},
+		{"LD A,0x100", 1, "0x100 is out of range"},
+		{"JR 0x0200", 1, "0x0200 is too far away to jump to with JR"},
+		{"JP nowhere", 1, "undefined label nowhere"},
+		{"a: NOP", 1, "a is a register and can't be used as a label"},
+		{"x: NOP\nx: NOP", 2, "label x is already defined"},
+		{"ORG 0x0200\nORG 0x0100", 2, "ORG can't move from 0x0200 to 0x0100"},
+		{"LDH A,(0x1234)", 1, "0x1234 is not in 0xFF00-0xFFFF"},
+		{"RST 0x39", 1, "RST can't take operands 0x39"},
+	}
+
+	for _, test := range tests {
+		_, err := Assemble(test.source, 0x0000)
+		if assert.IsType(t, &Error{}, err, test.source) {
+			assert.Equal(t, test.line, err.(*Error).Line, test.source)
+			assert.Equal(t, test.message, err.(*Error).Message, test.source)
+		}
+	}
+	assert.Equal(t, "line 1: LD Q,A: LD can't take operands Q,A", func() string {
+		_, err := Assemble("  LD Q,A", 0x0000)
+		return err.Error()
+	}())
+}
+
+func TestAssembleAtAddress(t *testing.T) {
+	var code []byte = MustAssemble("JR 0xC010", types.Word(0xC000))
+	assert.Equal(t, []byte{0x18, 0x0E}, code)
+}
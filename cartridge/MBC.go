@@ -3,6 +3,7 @@ package cartridge
 import (
 	"io"
 
+	"github.com/djhworld/gomeboycolor/cdl"
 	"github.com/djhworld/gomeboycolor/types"
 )
 
@@ -13,6 +14,13 @@ type MemoryBankController interface {
 	LoadRam(reader io.Reader) error
 	switchROMBank(bank int)
 	switchRAMBank(bank int)
+	setCodeDataLogger(l *cdl.Logger)
+	currentROMBank() int
+}
+
+//Offset within the ROM of an address in the switchable bank area (0x4000-0x7FFF)
+func romOffset(bank int, addr types.Word) int {
+	return bank*0x4000 + int(addr-0x4000)
 }
 
 func populateROMBanks(rom []byte, noOfBanks int) [][]byte {
@@ -6,6 +6,7 @@ import (
 	"log"
 	"strings"
 
+	"github.com/djhworld/gomeboycolor/cdl"
 	"github.com/djhworld/gomeboycolor/types"
 	"github.com/djhworld/gomeboycolor/utils"
 )
@@ -16,6 +17,7 @@ import (
 type MBC0 struct {
 	Name    string
 	romBank []byte
+	cdl     *cdl.Logger
 }
 
 func NewMBC0(rom []byte) *MBC0 {
@@ -43,6 +45,9 @@ func (m *MBC0) Read(addr types.Word) byte {
 		log.Fatalf(m.Name+": Cannot read from MBC for address: %s!", addr)
 	}
 
+	if m.cdl != nil {
+		m.cdl.Log(int(addr))
+	}
 	return m.romBank[addr]
 }
 
@@ -50,10 +55,18 @@ func (m *MBC0) switchROMBank(bank int) {
 	// not needed for MBC0
 }
 
+func (m *MBC0) currentROMBank() int {
+	return 1
+}
+
 func (m *MBC0) switchRAMBank(bank int) {
 	// not needed for MBC0
 }
 
+func (m *MBC0) setCodeDataLogger(l *cdl.Logger) {
+	m.cdl = l
+}
+
 func (m *MBC0) SaveRam(writer io.Writer) error {
 	return nil
 }
@@ -6,6 +6,7 @@ import (
 	"log"
 	"strings"
 
+	"github.com/djhworld/gomeboycolor/cdl"
 	"github.com/djhworld/gomeboycolor/constants"
 	"github.com/djhworld/gomeboycolor/types"
 	"github.com/djhworld/gomeboycolor/utils"
@@ -25,6 +26,7 @@ type MBC1 struct {
 	MaxMemMode      int
 	ROMSize         int
 	RAMSize         int
+	cdl             *cdl.Logger
 }
 
 func NewMBC1(rom []byte, romSize int, ramSize int, hasBattery bool) *MBC1 {
@@ -105,11 +107,17 @@ func (m *MBC1) Write(addr types.Word, value byte) {
 func (m *MBC1) Read(addr types.Word) byte {
 	//ROM Bank 0
 	if addr < 0x4000 {
+		if m.cdl != nil {
+			m.cdl.Log(int(addr))
+		}
 		return m.romBank0[addr]
 	}
 
 	//Switchable ROM BANK
 	if addr >= 0x4000 && addr < 0x8000 {
+		if m.cdl != nil {
+			m.cdl.Log(romOffset(m.currentROMBank(), addr))
+		}
 		return m.romBanks[m.selectedROMBank][addr-0x4000]
 	}
 
@@ -132,10 +140,22 @@ func (m *MBC1) switchROMBank(bank int) {
 	m.selectedROMBank = bank
 }
 
+//bank 0 can't be mapped into the switchable area, selecting it maps bank 1 instead
+func (m *MBC1) currentROMBank() int {
+	if m.selectedROMBank == 0 {
+		return 1
+	}
+	return m.selectedROMBank
+}
+
 func (m *MBC1) switchRAMBank(bank int) {
 	m.selectedRAMBank = bank
 }
 
+func (m *MBC1) setCodeDataLogger(l *cdl.Logger) {
+	m.cdl = l
+}
+
 func (m *MBC1) SaveRam(writer io.Writer) error {
 	if m.hasRAM && m.hasBattery {
 		s := NewSave()
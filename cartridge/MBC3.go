@@ -5,6 +5,7 @@ import (
 	"io"
 	"strings"
 
+	"github.com/djhworld/gomeboycolor/cdl"
 	"github.com/djhworld/gomeboycolor/types"
 	"github.com/djhworld/gomeboycolor/utils"
 )
@@ -22,6 +23,7 @@ type MBC3 struct {
 	ROMSize         int
 	RAMSize         int
 	hasBattery      bool
+	cdl             *cdl.Logger
 }
 
 func NewMBC3(rom []byte, romSize int, ramSize int, hasBattery bool) *MBC3 {
@@ -88,11 +90,17 @@ func (m *MBC3) Write(addr types.Word, value byte) {
 func (m *MBC3) Read(addr types.Word) byte {
 	//ROM Bank 0
 	if addr < 0x4000 {
+		if m.cdl != nil {
+			m.cdl.Log(int(addr))
+		}
 		return m.romBank0[addr]
 	}
 
 	//Switchable ROM BANK
 	if addr >= 0x4000 && addr < 0x8000 {
+		if m.cdl != nil {
+			m.cdl.Log(romOffset(m.currentROMBank(), addr))
+		}
 		return m.romBanks[m.selectedROMBank][addr-0x4000]
 	}
 
@@ -110,10 +118,22 @@ func (m *MBC3) switchROMBank(bank int) {
 	m.selectedROMBank = bank
 }
 
+//bank 0 can't be mapped into the switchable area, selecting it maps bank 1 instead
+func (m *MBC3) currentROMBank() int {
+	if m.selectedROMBank == 0 {
+		return 1
+	}
+	return m.selectedROMBank
+}
+
 func (m *MBC3) switchRAMBank(bank int) {
 	m.selectedRAMBank = bank
 }
 
+func (m *MBC3) setCodeDataLogger(l *cdl.Logger) {
+	m.cdl = l
+}
+
 func (m *MBC3) SaveRam(writer io.Writer) error {
 	if m.hasRAM && m.hasBattery {
 		s := NewSave()
@@ -5,6 +5,7 @@ import (
 	"io"
 	"strings"
 
+	"github.com/djhworld/gomeboycolor/cdl"
 	"github.com/djhworld/gomeboycolor/types"
 	"github.com/djhworld/gomeboycolor/utils"
 )
@@ -24,6 +25,7 @@ type MBC5 struct {
 	hasBattery      bool
 	ROMBHigher      types.Word
 	ROMBLower       types.Word
+	cdl             *cdl.Logger
 }
 
 func NewMBC5(rom []byte, romSize int, ramSize int, hasBattery bool) *MBC5 {
@@ -93,13 +95,19 @@ func (m *MBC5) Write(addr types.Word, value byte) {
 func (m *MBC5) Read(addr types.Word) byte {
 	//ROM Bank 0
 	if addr < 0x4000 {
+		if m.cdl != nil {
+			m.cdl.Log(int(addr))
+		}
 		return m.romBank0[addr]
 	}
 
 	//Switchable ROM BANK
 	if addr >= 0x4000 && addr < 0x8000 {
+		if m.cdl != nil {
+			m.cdl.Log(romOffset(m.currentROMBank(), addr))
+		}
 		if m.selectedROMBank == 0 {
-			return m.romBank0[addr]
+			return m.romBank0[addr-0x4000]
 		}
 		return m.romBanks[m.selectedROMBank][addr-0x4000]
 	}
@@ -118,10 +126,18 @@ func (m *MBC5) switchROMBank(bank int) {
 	m.selectedROMBank = bank
 }
 
+func (m *MBC5) currentROMBank() int {
+	return m.selectedROMBank
+}
+
 func (m *MBC5) switchRAMBank(bank int) {
 	m.selectedRAMBank = bank
 }
 
+func (m *MBC5) setCodeDataLogger(l *cdl.Logger) {
+	m.cdl = l
+}
+
 func (m *MBC5) SaveRam(writer io.Writer) error {
 	if m.hasRAM && m.hasBattery {
 		s := NewSave()
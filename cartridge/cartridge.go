@@ -7,6 +7,8 @@ import (
 	"io"
 	"strings"
 
+	"github.com/djhworld/gomeboycolor/cdl"
+	"github.com/djhworld/gomeboycolor/types"
 	"github.com/djhworld/gomeboycolor/utils"
 )
 
@@ -55,6 +57,9 @@ type Cartridge struct {
 	Name       string
 	MBC        MemoryBankController
 	ID         string
+
+	//contents of the ROM, shared with the memory bank controller
+	rom []byte
 }
 
 func NewCartridge(romName string, romContents []byte) (*Cartridge, error) {
@@ -74,6 +79,7 @@ func (c *Cartridge) Init(rom []byte) error {
 		return errors.New(fmt.Sprintf("ROM size %d is too small", size))
 	}
 
+	c.rom = rom
 	c.Title = strings.TrimSpace(string(rom[0x0134:0x0142]))
 	h := md5.New()
 	io.WriteString(h, c.Title)
@@ -138,6 +144,33 @@ func (c *Cartridge) LoadRam(reader io.Reader) error {
 	return c.MBC.LoadRam(reader)
 }
 
+//Logs every read of the ROM made through the memory bank controller, nil stops logging
+func (c *Cartridge) SetCodeDataLogger(l *cdl.Logger) {
+	c.MBC.setCodeDataLogger(l)
+}
+
+//ROM bank mapped at an address, addresses outside the switchable area (0x4000-0x7FFF) are
+//always in bank 0
+func (c *Cartridge) ROMBank(addr types.Word) int {
+	if addr >= 0x4000 && addr < 0x8000 {
+		return c.MBC.currentROMBank()
+	}
+	return 0
+}
+
+//Overwrites the byte of ROM mapped at an address (in whichever bank is switched in), writes
+//through the memory bank controller only ever change its registers. Addresses outside the
+//ROM are ignored
+func (c *Cartridge) PatchROM(addr types.Word, value byte) {
+	var offset int = int(addr)
+	if addr >= 0x4000 && addr < 0x8000 {
+		offset = romOffset(c.MBC.currentROMBank(), addr)
+	}
+	if addr < 0x8000 && offset < len(c.rom) {
+		c.rom[offset] = value
+	}
+}
+
 func (c *Cartridge) String() string {
 	startingString := "Gameboy"
 	if c.IsColourGB {
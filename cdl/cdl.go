@@ -0,0 +1,138 @@
+package cdl
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/djhworld/gomeboycolor/utils"
+)
+
+//Flags recorded against each byte of the ROM, a byte can collect more than one. Bits 0 and 1
+//mean the same as in the CDL files written by other emulators (e.g. Mesen) so tools built for
+//those can read the code and data split, bits 2-5 are never set
+const (
+	NONE   byte = 0x00
+	CODE   byte = 0x01 //part of an executed instruction, either its opcode or an operand
+	DATA   byte = 0x02 //read as data by an instruction or copied by a DMA transfer
+	VRAM   byte = 0x40 //copied into VRAM or OAM by a DMA transfer, always set along with DATA
+	OPCODE byte = 0x80 //the first byte of an instruction (or the second of a CB prefixed one), always set along with CODE
+)
+
+//Code/data logger, records how every byte of the ROM has been accessed across all banks.
+//
+//The CPU and DMA controllers say what the following reads are for with SetAccess and the
+//memory bank controller logs the ROM offset each read resolves to, so reads made by
+//anything else (e.g. the debugger) are never recorded
+type Logger struct {
+	flags  []byte
+	access byte
+}
+
+func NewLogger(romSize int) *Logger {
+	return &Logger{flags: make([]byte, romSize)}
+}
+
+//Sets what subsequent reads are for, returning the previous access so it can be restored
+func (l *Logger) SetAccess(access byte) byte {
+	var previous byte = l.access
+	l.access = access
+	return previous
+}
+
+//Records a read of the byte at offset within the ROM
+func (l *Logger) Log(offset int) {
+	if l.access != NONE && offset >= 0 && offset < len(l.flags) {
+		l.flags[offset] |= l.access
+	}
+}
+
+//Flags recorded for the byte at offset within the ROM
+func (l *Logger) Flags(offset int) byte {
+	if offset < 0 || offset >= len(l.flags) {
+		return NONE
+	}
+	return l.flags[offset]
+}
+
+func (l *Logger) Size() int {
+	return len(l.flags)
+}
+
+func (l *Logger) Reset() {
+	for i := range l.flags {
+		l.flags[i] = NONE
+	}
+	l.access = NONE
+}
+
+//Writes the log as a CDL file. There is no header, just one byte of flags for every byte of the
+//ROM in file order
+func (l *Logger) Export(w io.Writer) error {
+	_, err := w.Write(l.flags)
+	return err
+}
+
+//Merges a previously exported CDL file into the log so coverage can be built up over several
+//sessions
+func (l *Logger) Import(r io.Reader) error {
+	var data []byte = make([]byte, len(l.flags))
+	if _, err := io.ReadFull(r, data); err != nil {
+		return fmt.Errorf("CDL file does not match ROM size of %d bytes: %v", len(l.flags), err)
+	}
+
+	for i, f := range data {
+		l.flags[i] |= f
+	}
+	return nil
+}
+
+//Counts of ROM bytes carrying each flag
+type Coverage struct {
+	Size     int
+	Code     int
+	Opcode   int
+	Data     int
+	VRAM     int
+	Unlogged int
+}
+
+func (l *Logger) Coverage() Coverage {
+	var c Coverage = Coverage{Size: len(l.flags)}
+	for _, f := range l.flags {
+		if f&CODE != 0 {
+			c.Code++
+		}
+		if f&OPCODE != 0 {
+			c.Opcode++
+		}
+		if f&DATA != 0 {
+			c.Data++
+		}
+		if f&VRAM != 0 {
+			c.VRAM++
+		}
+		if f == NONE {
+			c.Unlogged++
+		}
+	}
+	return c
+}
+
+func (c Coverage) String() string {
+	percent := func(n int) string {
+		if c.Size == 0 {
+			return fmt.Sprintf("%d", n)
+		}
+		return fmt.Sprintf("%d (%.2f%%)", n, float64(n)*100/float64(c.Size))
+	}
+
+	return fmt.Sprintln("Code/Data Log") +
+		fmt.Sprintln(strings.Repeat("-", 50)) +
+		fmt.Sprintln(utils.PadRight("ROM Size:", 18, " "), c.Size) +
+		fmt.Sprintln(utils.PadRight("Code:", 18, " "), percent(c.Code)) +
+		fmt.Sprintln(utils.PadRight("Opcodes:", 18, " "), percent(c.Opcode)) +
+		fmt.Sprintln(utils.PadRight("Data:", 18, " "), percent(c.Data)) +
+		fmt.Sprintln(utils.PadRight("VRAM:", 18, " "), percent(c.VRAM)) +
+		fmt.Sprintln(utils.PadRight("Unlogged:", 18, " "), percent(c.Unlogged))
+}
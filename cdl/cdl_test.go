@@ -0,0 +1,76 @@
+package cdl
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchrcom/testify/assert"
+)
+
+func TestLogRecordsCurrentAccess(t *testing.T) {
+	l := NewLogger(16)
+
+	l.Log(0)
+	assert.Equal(t, NONE, l.Flags(0), "reads made with no access set should be ignored")
+
+	l.SetAccess(CODE | OPCODE)
+	l.Log(0)
+	l.SetAccess(CODE)
+	l.Log(1)
+	l.SetAccess(DATA)
+	l.Log(1)
+	l.Log(16)
+
+	assert.Equal(t, CODE|OPCODE, l.Flags(0))
+	assert.Equal(t, CODE|DATA, l.Flags(1))
+	assert.Equal(t, NONE, l.Flags(16))
+}
+
+func TestSetAccessReturnsPrevious(t *testing.T) {
+	l := NewLogger(16)
+	l.SetAccess(DATA)
+
+	previous := l.SetAccess(VRAM)
+	l.Log(4)
+	l.SetAccess(previous)
+	l.Log(5)
+
+	assert.Equal(t, DATA, previous)
+	assert.Equal(t, VRAM, l.Flags(4))
+	assert.Equal(t, DATA, l.Flags(5))
+}
+
+func TestExportAndImport(t *testing.T) {
+	l := NewLogger(4)
+	l.SetAccess(CODE)
+	l.Log(0)
+
+	var b bytes.Buffer
+	assert.Nil(t, l.Export(&b))
+	assert.Equal(t, []byte{CODE, NONE, NONE, NONE}, b.Bytes())
+
+	merged := NewLogger(4)
+	merged.SetAccess(DATA)
+	merged.Log(0)
+	merged.Log(3)
+	assert.Nil(t, merged.Import(&b))
+	assert.Equal(t, CODE|DATA, merged.Flags(0))
+	assert.Equal(t, DATA, merged.Flags(3))
+
+	assert.NotNil(t, NewLogger(8).Import(bytes.NewReader([]byte{CODE})))
+}
+
+func TestCoverage(t *testing.T) {
+	l := NewLogger(8)
+	l.SetAccess(CODE | OPCODE)
+	l.Log(0)
+	l.SetAccess(CODE)
+	l.Log(1)
+	l.SetAccess(DATA)
+	l.Log(1)
+	l.Log(2)
+	l.SetAccess(DATA | VRAM)
+	l.Log(3)
+
+	assert.Equal(t, Coverage{Size: 8, Code: 2, Opcode: 1, Data: 3, VRAM: 1, Unlogged: 4}, l.Coverage())
+}
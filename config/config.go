@@ -22,6 +22,21 @@ type Config struct {
 	Debug     bool
 	BreakOn   string
 	DumpState bool
+
+	//when set, all audio output is recorded to this WAV file
+	AudioRecordFile string
+
+	//when set, the CPU state before every instruction is written to this file in
+	//gameboy-doctor format
+	TraceFile string
+
+	//when set, how every byte of the ROM is accessed is logged and written to this CDL file
+	//when the emulator closes. An existing file is merged in first
+	CodeDataLogFile string
+
+	//when set, the cycles spent in each instruction and function are profiled and written to
+	//this file as a pprof profile when the emulator closes
+	ProfileFile string
 }
 
 func (c *Config) String() string {
@@ -37,6 +52,10 @@ func (c *Config) String() string {
 		fmt.Sprintln(utils.PadRight("CPU Dump?: ", 19, " "), c.DumpState) +
 		fmt.Sprintln(utils.PadRight("Headless: ", 19, " "), c.Headless) +
 		fmt.Sprintln(utils.PadRight("FrameRateLock: ", 19, " "), c.FrameRateLock) +
+		fmt.Sprintln(utils.PadRight("Record Audio To: ", 19, " "), c.AudioRecordFile) +
+		fmt.Sprintln(utils.PadRight("Trace CPU To: ", 19, " "), c.TraceFile) +
+		fmt.Sprintln(utils.PadRight("Code/Data Log: ", 19, " "), c.CodeDataLogFile) +
+		fmt.Sprintln(utils.PadRight("Profile To: ", 19, " "), c.ProfileFile) +
 		fmt.Sprint(strings.Repeat("-", 50))
 }
 
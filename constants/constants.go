@@ -7,6 +7,7 @@ const (
 	V_BLANK_IR_ADDR        byte = 0x40
 	LCD_IR_ADDR                 = 0x48
 	TIMER_OVERFLOW_IR_ADDR      = 0x50
+	SERIAL_IR_ADDR              = 0x58
 	JOYP_HILO_IR_ADDR           = 0x60
 )
 
@@ -14,6 +15,7 @@ const (
 	V_BLANK_IRQ        byte = 0x01 //bit 0
 	LCD_IRQ                 = 0x02 //bit 1
 	TIMER_OVERFLOW_IRQ      = 0x04 // bit 2
+	SERIAL_IRQ              = 0x08 //bit 3
 	JOYP_HILO_IRQ           = 0x10 //bit 4
 )
 
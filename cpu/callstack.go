@@ -0,0 +1,118 @@
+package cpu
+
+import (
+	"fmt"
+
+	"github.com/djhworld/gomeboycolor/profiler"
+	"github.com/djhworld/gomeboycolor/types"
+)
+
+//frames deeper than this push the oldest off the bottom of the call stack, this stops it growing
+//forever when a game never returns from its subroutines (e.g. when it resets SP)
+const MAX_CALL_STACK_DEPTH = 256
+
+//How a frame on the call stack was entered
+const (
+	CALL_FRAME byte = iota
+	RST_FRAME
+	INTERRUPT_FRAME
+)
+
+//Maps an address to the ROM bank currently switched in there
+type BankMapper func(address types.Word) int
+
+//One subroutine or interrupt handler that is being run
+type CallFrame struct {
+	Kind byte
+
+	//address of the CALL or RST, or the instruction that was about to run when the interrupt
+	//was serviced
+	CallSite     types.Word
+	CallSiteBank int
+
+	//entry point of the subroutine or interrupt handler
+	Target     types.Word
+	TargetBank int
+
+	//SP once the frame has returned, a return that leaves SP at or above this has left the
+	//frame even if it skipped a level by discarding a return address
+	SP types.Word
+
+	//function in the profiler's call tree that the frame's instructions are charged to
+	function *profiler.Node
+}
+
+func (f CallFrame) String() string {
+	switch f.Kind {
+	case RST_FRAME:
+		return fmt.Sprintf("RST %02X:%04X", f.TargetBank, uint16(f.Target))
+	case INTERRUPT_FRAME:
+		return fmt.Sprintf("interrupt %02X:%04X", f.TargetBank, uint16(f.Target))
+	default:
+		return fmt.Sprintf("CALL %02X:%04X", f.TargetBank, uint16(f.Target))
+	}
+}
+
+//Links the function used to find which ROM bank the addresses on the call stack are in
+func (cpu *GbcCPU) LinkBankMapper(m BankMapper) {
+	cpu.bankOf = m
+}
+
+//Starts or stops keeping the call stack returned by CallStack. Finding the banks for each frame
+//costs time on every call so it is only kept when asked for, or while a profiler is set
+func (cpu *GbcCPU) TrackCallStack(on bool) {
+	cpu.callStackWanted = on
+	cpu.updateInstrumentation()
+}
+
+//Frames on the shadow call stack, outermost first
+func (cpu *GbcCPU) CallStack() []CallFrame {
+	var stack []CallFrame = make([]CallFrame, len(cpu.callStack))
+	copy(stack, cpu.callStack)
+	return stack
+}
+
+func (cpu *GbcCPU) bank(address types.Word) int {
+	if cpu.bankOf == nil {
+		return 0
+	}
+	return cpu.bankOf(address)
+}
+
+//the profiler function that instructions run now are charged to, nil is its root
+func (cpu *GbcCPU) currentFunction() *profiler.Node {
+	if len(cpu.callStack) == 0 {
+		return nil
+	}
+	return cpu.callStack[len(cpu.callStack)-1].function
+}
+
+func (cpu *GbcCPU) pushCallFrame(kind byte, callSite types.Word, target types.Word) {
+	var frame CallFrame = CallFrame{
+		Kind:         kind,
+		CallSite:     callSite,
+		CallSiteBank: cpu.bank(callSite),
+		Target:       target,
+		TargetBank:   cpu.bank(target),
+		SP:           cpu.SP,
+		function:     cpu.currentFunction(),
+	}
+
+	if len(cpu.callStack) >= MAX_CALL_STACK_DEPTH {
+		//calls this deep stay charged to the deepest function so the call tree stops growing too
+		copy(cpu.callStack, cpu.callStack[1:])
+		cpu.callStack = cpu.callStack[:len(cpu.callStack)-1]
+	} else if cpu.profiler != nil {
+		frame.function = cpu.profiler.Enter(frame.function,
+			profiler.Location{Bank: frame.CallSiteBank, PC: callSite},
+			profiler.Location{Bank: frame.TargetBank, PC: target})
+	}
+
+	cpu.callStack = append(cpu.callStack, frame)
+}
+
+func (cpu *GbcCPU) popCallFrames() {
+	for n := len(cpu.callStack); n > 0 && cpu.callStack[n-1].SP <= cpu.SP; n-- {
+		cpu.callStack = cpu.callStack[:n-1]
+	}
+}
@@ -4,8 +4,10 @@ import (
 	"fmt"
 	"log"
 
+	"github.com/djhworld/gomeboycolor/cdl"
 	"github.com/djhworld/gomeboycolor/constants"
 	"github.com/djhworld/gomeboycolor/mmu"
+	"github.com/djhworld/gomeboycolor/profiler"
 	"github.com/djhworld/gomeboycolor/timer"
 	"github.com/djhworld/gomeboycolor/types"
 	"github.com/djhworld/gomeboycolor/utils"
@@ -14,6 +16,17 @@ import (
 const NAME = "CPU"
 const PREFIX = NAME + ":"
 
+//M-cycles the CPU is paused for while the CGB switches speed
+const SPEED_SWITCH_CYCLES int = 2050
+
+//Called for every M-cycle the CPU spends so that the rest of the system can be advanced in step
+//with each memory access rather than after a whole instruction
+type ClockHandler func(cycles int)
+
+//Called when a speed switch changes the CPU speed, so anything clocked separately from the CPU
+//can follow it
+type SpeedChangeHandler func(speed int)
+
 //flags
 const (
 	_ = iota
@@ -29,15 +42,15 @@ type CurrentInstruction struct {
 }
 
 type CPUFrame struct {
-	PC                      types.Word // Program Counter
-	SP                      types.Word // Stack Pointer
-	R                       Registers
-	InterruptsEnabled       bool
-	CurrentInstruction      *CurrentInstruction
-	LastInstrCycle          Clock
-	PCJumped                bool
-	Halted                  bool
-	InterruptFlagBeforeHalt byte
+	PC                 types.Word // Program Counter
+	SP                 types.Word // Stack Pointer
+	R                  Registers
+	InterruptsEnabled  bool
+	CurrentInstruction *CurrentInstruction
+	LastInstrCycle     Clock
+	PCJumped           bool
+	Halted             bool
+	Stopped            bool
 }
 
 func (cpu *GbcCPU) GetFrame() *CPUFrame {
@@ -46,11 +59,11 @@ func (cpu *GbcCPU) GetFrame() *CPUFrame {
 	frame.SP = cpu.SP
 	frame.R = cpu.R
 	frame.InterruptsEnabled = cpu.InterruptsEnabled
-	frame.CurrentInstruction = cpu.CurrentInstruction
+	frame.CurrentInstruction = cpu.CurrentInstruction()
 	frame.LastInstrCycle = cpu.LastInstrCycle
 	frame.PCJumped = cpu.PCJumped
 	frame.Halted = cpu.Halted
-	frame.InterruptFlagBeforeHalt = cpu.InterruptFlagBeforeHalt
+	frame.Stopped = cpu.Stopped
 	return frame
 }
 
@@ -95,22 +108,71 @@ func (c *Clock) String() string {
 }
 
 type GbcCPU struct {
-	PC                      types.Word // Program Counter
-	SP                      types.Word // Stack Pointer
-	R                       Registers
-	InterruptsEnabled       bool
-	CurrentInstruction      *CurrentInstruction
-	LastInstrCycle          Clock
-	mmu                     mmu.MemoryMappedUnit
-	timer                   *timer.Timer
-	PCJumped                bool
-	Halted                  bool
-	InterruptFlagBeforeHalt byte
-	Speed                   int
+	PC                types.Word // Program Counter
+	SP                types.Word // Stack Pointer
+	R                 Registers
+	InterruptsEnabled bool
+	LastInstrCycle    Clock
+	mmu               mmu.MemoryMappedUnit
+	timer             *timer.Timer
+	PCJumped          bool
+	Halted            bool
+	Stopped           bool
+	Speed             int
+
+	//set once an undefined opcode has been executed, nothing else runs until the CPU is reset
+	Locked        bool
+	lockupErr     *IllegalOpcodeError
+	lockupHandler LockupHandler
+
+	//opcode and address of the instruction being executed
+	opcode        byte
+	opcodeAddress types.Word
+
+	//the speed switch and the registers it uses are only available on CGB hardware
+	RunningColorGBHardware bool
+
+	//KEY1, connect it to the MMU so games can arm a speed switch
+	SpeedSwitch *SpeedSwitchRegister
+
+	//M-cycles left until a speed switch completes
+	speedSwitchCycles int
+
+	//EI enables interrupts after the instruction that follows it
+	enableInterruptsPending bool
+
+	//set when HALT is executed with interrupts disabled and an interrupt pending, the PC
+	//fails to increment past the next opcode
+	haltBug bool
+
+	//when set the state of the CPU is written out before every instruction
+	tracer *Tracer
+
+	//when set every memory read is tagged with what it is for so ROM accesses can be logged
+	cdl *cdl.Logger
+
+	//when set the cycles spent on each instruction are charged to the function on top of the
+	//call stack
+	profiler *profiler.Profiler
+
+	//subroutines and interrupt handlers that have been entered but not returned from, only kept
+	//while trackingCalls is set
+	callStack       []CallFrame
+	bankOf          BankMapper
+	callStackWanted bool
+	trackingCalls   bool
+
+	//set while any of the code/data logger, profiler or call stack is in use, Step skips all of
+	//their bookkeeping otherwise
+	instrumented bool
+
+	clockHandler       ClockHandler
+	speedChangeHandler SpeedChangeHandler
 }
 
 func NewCPU(m mmu.MemoryMappedUnit, timer *timer.Timer) *GbcCPU {
 	cpu := new(GbcCPU)
+	cpu.SpeedSwitch = &SpeedSwitchRegister{cpu: cpu}
 	cpu.Reset()
 	cpu.mmu = m
 	cpu.timer = timer
@@ -131,11 +193,20 @@ func (cpu *GbcCPU) Reset() {
 	cpu.R.H = 0
 	cpu.R.L = 0
 	cpu.Speed = 1
-	cpu.CurrentInstruction = &CurrentInstruction{Instruction: Instructions[0x00], Operands: [2]byte{}}
+	cpu.SpeedSwitch.Reset()
+	cpu.opcode, cpu.opcodeAddress = 0x00, 0
 	cpu.InterruptsEnabled = true
 	cpu.LastInstrCycle.Reset()
 	cpu.PCJumped = false
 	cpu.Halted = false
+	cpu.enableInterruptsPending = false
+	cpu.haltBug = false
+	cpu.Stopped = false
+	cpu.Locked = false
+	cpu.lockupErr = nil
+	cpu.speedSwitchCycles = 0
+	cpu.RunningColorGBHardware = false
+	cpu.callStack = cpu.callStack[:0]
 }
 
 func (cpu *GbcCPU) FlagsString() string {
@@ -169,7 +240,7 @@ func (cpu *GbcCPU) FlagsString() string {
 }
 
 func (cpu *GbcCPU) String() string {
-	return fmt.Sprint("PC: ", cpu.PC, "  SP: ", cpu.SP, "  ", cpu.R, "  ", cpu.FlagsString(), "  ", cpu.CurrentInstruction)
+	return fmt.Sprint("PC: ", cpu.PC, "  SP: ", cpu.SP, "  ", cpu.R, "  ", cpu.FlagsString(), "  ", cpu.CurrentInstruction())
 }
 
 func (cpu *GbcCPU) ResetFlag(flag int) {
@@ -220,6 +291,26 @@ func (cpu *GbcCPU) IsFlagSet(flag int) bool {
 	return false
 }
 
+//Opcode of the instruction being run or last run, 0xCB for the CB prefixed instructions
+func (cpu *GbcCPU) Opcode() byte {
+	return cpu.opcode
+}
+
+//Decodes the instruction being run or last run from the memory it was fetched from, Step doesn't
+//keep it up to date itself
+func (cpu *GbcCPU) CurrentInstruction() *CurrentInstruction {
+	var next types.Word = cpu.opcodeAddress + 1
+	if cpu.opcode == 0xCB {
+		return &CurrentInstruction{Instruction: InstructionsCB[cpu.mmu.ReadByte(next)]}
+	}
+
+	var instr *CurrentInstruction = &CurrentInstruction{Instruction: Instructions[cpu.opcode]}
+	for i := 0; i < instr.OperandsSize; i++ {
+		instr.Operands[i] = cpu.mmu.ReadByte(next + types.Word(i))
+	}
+	return instr
+}
+
 func (cpu *GbcCPU) IncrementPC(by int) {
 	cpu.PC += types.Word(by)
 }
@@ -228,111 +319,278 @@ func (cpu *GbcCPU) Step() int {
 	cpu.LastInstrCycle.Reset()
 	var opcode byte
 
-	if !cpu.Halted {
-		cpu.CheckForInterrupts()
-		opcode = cpu.ReadByte(cpu.PC)
-
-		if opcode == 0xCB {
-			cpu.IncrementPC(1)
-			opcode = cpu.ReadByte(cpu.PC)
-			cpu.Compile(InstructionsCB[opcode])
-		} else {
-			cpu.Compile(Instructions[opcode])
-		}
+	if cpu.Locked {
+		cpu.tick(1)
+		cpu.profileIdle()
+		return cpu.LastInstrCycle.M
+	}
 
-		cpu.CurrentInstruction.Execute(cpu)
+	//the timer is not ticked in either of these states as the system clock is stopped
+	if cpu.speedSwitchCycles > 0 {
+		cpu.speedSwitchCycles--
+		cpu.tickSystem(1)
+		cpu.profileIdle()
+		return cpu.LastInstrCycle.M
+	}
 
-		//this is put in place to check whether the PC has been altered by an instruction. If it has then don't
-		//do any incrementing
-		if cpu.PCJumped == false {
-			cpu.IncrementPC(cpu.CurrentInstruction.OperandsSize + 1)
+	if cpu.Stopped {
+		if cpu.mmu.ReadByte(constants.INTERRUPT_FLAG_ADDR)&constants.JOYP_HILO_IRQ != 0x00 {
+			log.Println(PREFIX, "Joypad input received, leaving STOP mode")
+			cpu.Stopped = false
 		}
+		cpu.tickSystem(1)
+		cpu.profileIdle()
+		return cpu.LastInstrCycle.M
+	}
 
-		cpu.PCJumped = false
-	} else {
-		iflagnow := cpu.mmu.ReadByte(constants.INTERRUPT_FLAG_ADDR)
-
-		//if IF flag has changed then the cpu should resume...
-		if cpu.InterruptFlagBeforeHalt != iflagnow {
+	if cpu.Halted {
+		//HALT is left as soon as an enabled interrupt is pending, even when interrupts are disabled
+		if cpu.pendingInterrupts() != 0x00 {
 			cpu.Halted = false
 		}
 
 		//Halt consumes 1 cpu cycle
 		cpu.tick(1)
+		cpu.profileIdle()
+		return cpu.LastInstrCycle.M
+	}
+
+	var enableInterrupts bool = cpu.enableInterruptsPending
+	cpu.CheckForInterrupts()
+
+	if cpu.tracer != nil {
+		cpu.tracer.Trace(cpu)
 	}
 
+	//an interrupt handler is charged for its dispatch, a call or return is charged to the
+	//function it was made from
+	var function *profiler.Node
+	if cpu.instrumented {
+		function = cpu.currentFunction()
+		cpu.logAccess(cdl.CODE | cdl.OPCODE)
+	}
+
+	var pc types.Word = cpu.PC
+	opcode = cpu.ReadByte(cpu.PC)
+	cpu.opcode, cpu.opcodeAddress = opcode, pc
+
+	if cpu.haltBug {
+		//the opcode is read but the PC is not moved past it, so it is read again as the next byte
+		cpu.haltBug = false
+		cpu.PC--
+	}
+
+	var length int = 1
+	if opcode == 0xCB {
+		cpu.IncrementPC(1)
+		opcode = cpu.ReadByte(cpu.PC)
+		if cpu.instrumented {
+			cpu.logAccess(cdl.DATA)
+		}
+		cpu.executeCB(opcode)
+	} else {
+		if cpu.instrumented {
+			cpu.logAccess(cdl.CODE)
+		}
+		var operands [2]byte
+		switch operandsSizes[opcode] {
+		case 1:
+			operands[0] = cpu.ReadByte(cpu.PC + 1)
+			length = 2
+		case 2:
+			operands[0] = cpu.ReadByte(cpu.PC + 1)
+			operands[1] = cpu.ReadByte(cpu.PC + 2)
+			length = 3
+		}
+		if cpu.instrumented {
+			cpu.logAccess(cdl.DATA)
+		}
+		cpu.execute(opcode, operands)
+	}
+
+	//this is put in place to check whether the PC has been altered by an instruction. If it has then don't
+	//do any incrementing
+	if cpu.PCJumped == false {
+		cpu.IncrementPC(length)
+	}
+
+	cpu.PCJumped = false
+
+	//a DI straight after EI cancels it
+	if enableInterrupts && cpu.enableInterruptsPending {
+		cpu.enableInterruptsPending = false
+		cpu.InterruptsEnabled = true
+	}
+
+	if cpu.instrumented {
+		cpu.logAccess(cdl.NONE)
+		cpu.profile(pc, function)
+	}
 	return cpu.LastInstrCycle.M
 }
 
+//Spends a number of M-cycles without executing anything, used while the CPU is paused by a DMA
+//transfer. Returns the cycles spent like Step
+func (cpu *GbcCPU) Idle(cycles int) int {
+	cpu.LastInstrCycle.Reset()
+	cpu.tick(cycles)
+	cpu.profileIdle()
+	return cpu.LastInstrCycle.M
+}
+
+//Links the handler that advances the rest of the system as the CPU spends each M-cycle
+func (cpu *GbcCPU) LinkClockHandler(h ClockHandler) {
+	cpu.clockHandler = h
+}
+
+//Links the handler that is told when the CPU speed changes
+func (cpu *GbcCPU) LinkSpeedChangeHandler(h SpeedChangeHandler) {
+	cpu.speedChangeHandler = h
+}
+
+//Starts writing a trace of every executed instruction, nil stops tracing
+func (cpu *GbcCPU) SetTracer(tracer *Tracer) {
+	cpu.tracer = tracer
+}
+
+//Starts tagging memory reads for a code/data logger, nil stops logging
+func (cpu *GbcCPU) SetCodeDataLogger(l *cdl.Logger) {
+	cpu.cdl = l
+	cpu.updateInstrumentation()
+}
+
+func (cpu *GbcCPU) updateInstrumentation() {
+	cpu.trackingCalls = cpu.callStackWanted || cpu.profiler != nil
+	if !cpu.trackingCalls {
+		cpu.callStack = cpu.callStack[:0]
+	}
+	cpu.instrumented = cpu.trackingCalls || cpu.cdl != nil
+}
+
+//tells the code/data logger what the following memory reads are for
+func (cpu *GbcCPU) logAccess(access byte) {
+	if cpu.cdl != nil {
+		cpu.cdl.SetAccess(access)
+	}
+}
+
+//Starts profiling the cycles spent in each instruction and function, nil stops profiling
+func (cpu *GbcCPU) SetProfiler(p *profiler.Profiler) {
+	cpu.profiler = p
+	//frames entered before now belong to no function in the new profiler's call tree
+	for i := range cpu.callStack {
+		cpu.callStack[i].function = nil
+	}
+	cpu.updateInstrumentation()
+}
+
+//charges the cycles spent by the last step to the instruction at pc, in system clock cycles
+func (cpu *GbcCPU) profile(pc types.Word, function *profiler.Node) {
+	if cpu.profiler != nil {
+		cpu.profiler.Instruction(function, profiler.Location{Bank: cpu.bank(pc), PC: pc}, cpu.LastInstrCycle.M*4/cpu.Speed)
+	}
+}
+
+//charges cycles spent without running an instruction (e.g. halted) to the instruction at the PC
+func (cpu *GbcCPU) profileIdle() {
+	if cpu.instrumented {
+		cpu.profile(cpu.PC, cpu.currentFunction())
+	}
+}
+
+//called before the return address is pushed by a CALL or RST
+func (cpu *GbcCPU) onCall(kind byte, target types.Word) {
+	if cpu.trackingCalls {
+		cpu.pushCallFrame(kind, cpu.PC, target)
+	}
+}
+
+//called before the return address is pushed when an interrupt is serviced
+func (cpu *GbcCPU) onInterrupt(handler types.Word) {
+	if cpu.trackingCalls {
+		cpu.pushCallFrame(INTERRUPT_FRAME, cpu.PC, handler)
+	}
+}
+
+//called once the return address has been popped by RET or RETI
+func (cpu *GbcCPU) onReturn() {
+	if cpu.trackingCalls {
+		cpu.popCallFrames()
+	}
+}
+
+//interrupts that are both requested (IF) and enabled (IE)
+func (cpu *GbcCPU) pendingInterrupts() byte {
+	var ie byte = cpu.mmu.ReadByte(constants.INTERRUPT_ENABLED_FLAG_ADDR)
+	var iflag byte = cpu.mmu.ReadByte(constants.INTERRUPT_FLAG_ADDR)
+	return ie & iflag & 0x1F
+}
+
 func (cpu *GbcCPU) CheckForInterrupts() bool {
 	if cpu.InterruptsEnabled {
 		var ie byte = cpu.mmu.ReadByte(constants.INTERRUPT_ENABLED_FLAG_ADDR)
 		var iflag byte = cpu.mmu.ReadByte(constants.INTERRUPT_FLAG_ADDR)
 		var interrupt byte = iflag & ie
 		if interrupt != 0x00 {
+			//an interrupt straight after a bugged HALT returns to the HALT
+			if cpu.haltBug {
+				cpu.haltBug = false
+				cpu.PC--
+			}
+
 			switch {
 			case interrupt&constants.V_BLANK_IRQ == constants.V_BLANK_IRQ:
-				cpu.mmu.WriteByte(constants.INTERRUPT_FLAG_ADDR, iflag&0xFE)
-				cpu.pushWordToStack(cpu.PC)
-				cpu.PC = types.Word(constants.V_BLANK_IR_ADDR)
-				cpu.InterruptsEnabled = false
-				return true
+				cpu.serviceInterrupt(iflag&0xFE, constants.V_BLANK_IR_ADDR)
 			case interrupt&constants.LCD_IRQ == constants.LCD_IRQ:
-				cpu.mmu.WriteByte(constants.INTERRUPT_FLAG_ADDR, iflag&0xFD)
-				cpu.pushWordToStack(cpu.PC)
-				cpu.PC = types.Word(constants.LCD_IR_ADDR)
-				cpu.InterruptsEnabled = false
-				return true
+				cpu.serviceInterrupt(iflag&0xFD, constants.LCD_IR_ADDR)
 			case interrupt&constants.TIMER_OVERFLOW_IRQ == constants.TIMER_OVERFLOW_IRQ:
-				cpu.mmu.WriteByte(constants.INTERRUPT_FLAG_ADDR, iflag&0xFB)
-				cpu.pushWordToStack(cpu.PC)
-				cpu.PC = types.Word(constants.TIMER_OVERFLOW_IR_ADDR)
-				cpu.InterruptsEnabled = false
-				return true
+				cpu.serviceInterrupt(iflag&0xFB, constants.TIMER_OVERFLOW_IR_ADDR)
+			case interrupt&constants.SERIAL_IRQ == constants.SERIAL_IRQ:
+				cpu.serviceInterrupt(iflag&0xF7, constants.SERIAL_IR_ADDR)
 			case interrupt&constants.JOYP_HILO_IRQ == constants.JOYP_HILO_IRQ:
 				log.Println("JOYP!")
-				cpu.mmu.WriteByte(constants.INTERRUPT_FLAG_ADDR, iflag&0xEF)
-				cpu.pushWordToStack(cpu.PC)
-				cpu.PC = types.Word(constants.JOYP_HILO_IR_ADDR)
-				cpu.InterruptsEnabled = false
-				return true
+				cpu.serviceInterrupt(iflag&0xEF, constants.JOYP_HILO_IR_ADDR)
 			default:
 				log.Fatalf("Unknown interrupt = %d", interrupt)
 			}
-
-			cpu.tick(1)
+			return true
 		}
 	}
 
 	return false
 }
 
-//Checks to see if the CPU speed should change to double (CGB only)
+//Jumps to an interrupt handler, which takes 5 M-cycles: two internal cycles, two to push the PC
+//onto the stack and one to load the handler address into the PC
+func (cpu *GbcCPU) serviceInterrupt(iflag byte, handler byte) {
+	cpu.InterruptsEnabled = false
+	cpu.mmu.WriteByte(constants.INTERRUPT_FLAG_ADDR, iflag)
+	cpu.tick(2)
+	cpu.onInterrupt(types.Word(handler))
+	cpu.pushWordToStack(cpu.PC)
+	cpu.PC = types.Word(handler)
+	cpu.tick(1)
+}
+
+//Performs a speed switch if one has been armed through KEY1 (CGB only)
 func (cpu *GbcCPU) SetCPUSpeed() {
-	var speedPrepRegister byte = cpu.mmu.ReadByte(mmu.CGB_DOUBLE_SPEED_PREP_REG)
-	if speedPrepRegister&0x01 == 0x01 {
-		switch cpu.Speed {
-		case 2:
-			cpu.Speed = 1
-			cpu.mmu.WriteByte(mmu.CGB_DOUBLE_SPEED_PREP_REG, 0x00)
-		case 1:
-			cpu.Speed = 2
-			cpu.mmu.WriteByte(mmu.CGB_DOUBLE_SPEED_PREP_REG, 0x80)
-		default:
-			panic(fmt.Sprint("Unsupported CPU speed ", cpu.Speed, " this should not happen!"))
-		}
-		log.Printf("CPU: Setting CPU speed to %dx speed", cpu.Speed)
+	if !cpu.SpeedSwitch.armed {
+		return
 	}
-}
 
-func (cpu *GbcCPU) Compile(instruction *Instruction) {
-	cpu.CurrentInstruction.Instruction = instruction
-	switch instruction.OperandsSize {
-	case 1:
-		cpu.CurrentInstruction.Operands[0] = cpu.ReadByte(cpu.PC + 1)
+	switch cpu.Speed {
 	case 2:
-		cpu.CurrentInstruction.Operands[0] = cpu.ReadByte(cpu.PC + 1)
-		cpu.CurrentInstruction.Operands[1] = cpu.ReadByte(cpu.PC + 2)
+		cpu.Speed = 1
+	case 1:
+		cpu.Speed = 2
+	default:
+		panic(fmt.Sprint("Unsupported CPU speed ", cpu.Speed, " this should not happen!"))
+	}
+	cpu.SpeedSwitch.armed = false
+	log.Printf("CPU: Setting CPU speed to %dx speed", cpu.Speed)
+
+	if cpu.speedChangeHandler != nil {
+		cpu.speedChangeHandler(cpu.Speed)
 	}
 }
 
@@ -604,34 +862,47 @@ func (cpu *GbcCPU) NOP() {
 //HALT
 //Halt CPU
 func (cpu *GbcCPU) HALT() {
-	//get and store the state of the IF FLAG now so we know when it changes
-	cpu.InterruptFlagBeforeHalt = cpu.mmu.ReadByte(constants.INTERRUPT_FLAG_ADDR)
+	//with interrupts disabled and one already pending the CPU doesn't halt, instead the HALT bug is triggered
+	if !cpu.InterruptsEnabled && cpu.pendingInterrupts() != 0x00 {
+		cpu.haltBug = true
+		return
+	}
 	cpu.Halted = true
 }
 
 //STOP
+//Enters low power mode until there is joypad input, unless a CGB speed switch has been armed in which
+//case the switch is performed instead. Either way DIV is reset
 func (cpu *GbcCPU) Stop() {
-	log.Println("CPU: Stopping...")
-	//After a stop instruction is executed, CGB hardware should check to see if the CPU speed should change
-	cpu.SetCPUSpeed()
+	cpu.timer.Write(timer.DIV_REGISTER, 0x00)
+
+	if cpu.RunningColorGBHardware && cpu.SpeedSwitch.armed {
+		cpu.SetCPUSpeed()
+		cpu.speedSwitchCycles = SPEED_SWITCH_CYCLES
+		return
+	}
+
+	log.Println(PREFIX, "Stopping until joypad input...")
+	cpu.Stopped = true
 }
 
 //DI
 //Disable interrupts
 func (cpu *GbcCPU) DI() {
 	cpu.InterruptsEnabled = false
+	cpu.enableInterruptsPending = false
 }
 
 //EI
 //Enable interrupts
 func (cpu *GbcCPU) EI() {
-	cpu.InterruptsEnabled = true
+	cpu.enableInterruptsPending = true
 }
 
 //LD r,n
 //Load value (n) from memory address in the PC into register (r) and increment PC by 1
-func (cpu *GbcCPU) LDrn(r *byte) {
-	*r = cpu.CurrentInstruction.Operands[0]
+func (cpu *GbcCPU) LDrn(r *byte, n byte) {
+	*r = n
 }
 
 //LD r,r
@@ -656,27 +927,22 @@ func (cpu *GbcCPU) LDr_rr(hs *byte, ls *byte, r *byte) {
 
 //LD nn,r
 //Load value from register (r) and put it in memory address (nn) taken from the next 2 bytes of memory from the PC. Increment the PC by 2
-func (cpu *GbcCPU) LDnn_r(r *byte) {
-	var ls byte = cpu.CurrentInstruction.Operands[0]
-	var hs byte = cpu.CurrentInstruction.Operands[1]
+func (cpu *GbcCPU) LDnn_r(r *byte, ls, hs byte) {
 	var resultAddr types.Word = types.Word(utils.JoinBytes(hs, ls))
 	cpu.WriteByte(resultAddr, *r)
 }
 
 //LD r, nn
 //Load the value in memory address defined from the next two bytes relative to the PC and store it in register (r). Increment the PC by 2
-func (cpu *GbcCPU) LDr_nn(r *byte) {
-	var ls byte = cpu.CurrentInstruction.Operands[0]
-	var hs byte = cpu.CurrentInstruction.Operands[1]
+func (cpu *GbcCPU) LDr_nn(r *byte, ls, hs byte) {
 	var nn types.Word = types.Word(utils.JoinBytes(hs, ls))
 	*r = cpu.ReadByte(nn)
 }
 
 //LD (HL),n
 //Load the value (n) from the memory address in the PC and put it in the memory address designated by register pair (HL)
-func (cpu *GbcCPU) LDhl_n() {
+func (cpu *GbcCPU) LDhl_n(value byte) {
 	var HL types.Word = types.Word(utils.JoinBytes(cpu.R.H, cpu.R.L))
-	var value byte = cpu.CurrentInstruction.Operands[0]
 	cpu.WriteByte(HL, value)
 }
 
@@ -739,40 +1005,30 @@ func (cpu *GbcCPU) LDIhl_r(r *byte) {
 }
 
 //LDH n, r
-func (cpu *GbcCPU) LDHn_r(r *byte) {
-	var n byte = cpu.CurrentInstruction.Operands[0]
+func (cpu *GbcCPU) LDHn_r(r *byte, n byte) {
 	cpu.WriteByte(types.Word(0xFF00)+types.Word(n), *r)
 }
 
 //LDH r, n
 //Load value (n) in register (r) and store it in memory address FF00+PC. Increment PC by 1
-func (cpu *GbcCPU) LDHr_n(r *byte) {
-	var n byte = cpu.CurrentInstruction.Operands[0]
+func (cpu *GbcCPU) LDHr_n(r *byte, n byte) {
 	*r = cpu.ReadByte((types.Word(0xFF00) + types.Word(n)))
 }
 
 //LD n, nn
-func (cpu *GbcCPU) LDn_nn(r1, r2 *byte) {
-	var ls byte = cpu.CurrentInstruction.Operands[0]
-	var hs byte = cpu.CurrentInstruction.Operands[1]
-
+func (cpu *GbcCPU) LDn_nn(r1, r2 *byte, ls, hs byte) {
 	//LS nibble first
 	*r1 = hs
 	*r2 = ls
 }
 
 //LD SP, nn
-func (cpu *GbcCPU) LDSP_nn() {
-	var ls byte = cpu.CurrentInstruction.Operands[0]
-	var hs byte = cpu.CurrentInstruction.Operands[1]
-
+func (cpu *GbcCPU) LDSP_nn(ls, hs byte) {
 	cpu.SP = types.Word(utils.JoinBytes(hs, ls))
 }
 
 //LD nn, SP
-func (cpu *GbcCPU) LDnn_SP() {
-	var ls byte = cpu.CurrentInstruction.Operands[0]
-	var hs byte = cpu.CurrentInstruction.Operands[1]
+func (cpu *GbcCPU) LDnn_SP(ls, hs byte) {
 	var addr types.Word = types.Word(utils.JoinBytes(hs, ls))
 
 	cpu.WriteByte(addr+1, byte(cpu.SP&0xFF00>>8))
@@ -786,9 +1042,7 @@ func (cpu *GbcCPU) LDSP_hl() {
 }
 
 //LDHL SP, n
-func (cpu *GbcCPU) LDHLSP_n() {
-	var n byte = cpu.CurrentInstruction.Operands[0]
-
+func (cpu *GbcCPU) LDHLSP_n(n byte) {
 	var HL types.Word
 
 	if n > 127 {
@@ -824,8 +1078,8 @@ func (cpu *GbcCPU) LDHLSP_n() {
 //Push register pair nn onto the stack and decrement the SP twice
 func (cpu *GbcCPU) Push_nn(r1, r2 *byte) {
 	word := types.Word(utils.JoinBytes(*r1, *r2))
-	cpu.pushWordToStack(word)
 	cpu.tick(1)
+	cpu.pushWordToStack(word)
 }
 
 //POP nn
@@ -858,8 +1112,7 @@ func (cpu *GbcCPU) AddA_hl() {
 
 //ADD A,n
 //Add the value in memory addressed PC to register A. Increment the PC by 1
-func (cpu *GbcCPU) AddA_n() {
-	var value byte = cpu.CurrentInstruction.Operands[0]
+func (cpu *GbcCPU) AddA_n(value byte) {
 	cpu.R.A = cpu.addBytes(cpu.R.A, value)
 }
 
@@ -927,8 +1180,7 @@ func (cpu *GbcCPU) AddCA_hl() {
 }
 
 //ADDC A,n
-func (cpu *GbcCPU) AddCA_n() {
-	var value byte = cpu.CurrentInstruction.Operands[0]
+func (cpu *GbcCPU) AddCA_n(value byte) {
 	var carry int = 0
 	if cpu.IsFlagSet(C) {
 		carry = 1
@@ -971,8 +1223,7 @@ func (cpu *GbcCPU) SubA_hl() {
 }
 
 //SUB A,n
-func (cpu *GbcCPU) SubA_n() {
-	var value byte = cpu.CurrentInstruction.Operands[0]
+func (cpu *GbcCPU) SubA_n(value byte) {
 	cpu.R.A = cpu.subBytes(cpu.R.A, value)
 }
 
@@ -1045,8 +1296,7 @@ func (cpu *GbcCPU) SubAC_hl() {
 }
 
 //SBC A, n
-func (cpu *GbcCPU) SubAC_n() {
-	var value byte = cpu.CurrentInstruction.Operands[0]
+func (cpu *GbcCPU) SubAC_n(value byte) {
 	var un int = int(value) & 0xff
 	var tmpa int = int(cpu.R.A) & 0xff
 	var ua int = int(cpu.R.A) & 0xff
@@ -1092,8 +1342,7 @@ func (cpu *GbcCPU) AndA_hl() {
 }
 
 //AND A, n
-func (cpu *GbcCPU) AndA_n() {
-	var value byte = cpu.CurrentInstruction.Operands[0]
+func (cpu *GbcCPU) AndA_n(value byte) {
 	cpu.R.A = cpu.andBytes(cpu.R.A, value)
 }
 
@@ -1110,13 +1359,20 @@ func (cpu *GbcCPU) OrA_hl() {
 }
 
 func (cpu *GbcCPU) tick(cycles int) {
-	cpu.LastInstrCycle.M += cycles
 	cpu.timer.Step(cycles)
+	cpu.tickSystem(cycles)
+}
+
+//advances everything but the timer, which is stopped while the CPU is in STOP mode or switching speed
+func (cpu *GbcCPU) tickSystem(cycles int) {
+	cpu.LastInstrCycle.M += cycles
+	if cpu.clockHandler != nil {
+		cpu.clockHandler(cycles)
+	}
 }
 
 //OR A, n
-func (cpu *GbcCPU) OrA_n() {
-	var value byte = cpu.CurrentInstruction.Operands[0]
+func (cpu *GbcCPU) OrA_n(value byte) {
 	cpu.R.A = cpu.orBytes(cpu.R.A, value)
 }
 
@@ -1133,8 +1389,7 @@ func (cpu *GbcCPU) XorA_hl() {
 }
 
 //XOR A, n
-func (cpu *GbcCPU) XorA_n() {
-	var value byte = cpu.CurrentInstruction.Operands[0]
+func (cpu *GbcCPU) XorA_n(value byte) {
 	cpu.R.A = cpu.xorBytes(cpu.R.A, value)
 }
 
@@ -1151,8 +1406,7 @@ func (cpu *GbcCPU) CPA_hl() {
 }
 
 //CP A, n
-func (cpu *GbcCPU) CPA_n() {
-	var value byte = cpu.CurrentInstruction.Operands[0]
+func (cpu *GbcCPU) CPA_n(value byte) {
 	cpu.subBytes(cpu.R.A, value)
 }
 
@@ -1201,10 +1455,8 @@ func (cpu *GbcCPU) Addhl_sp() {
 }
 
 //ADD SP,n
-func (cpu *GbcCPU) Addsp_n() {
+func (cpu *GbcCPU) Addsp_n(n byte) {
 	cpu.tick(1)
-	var n byte = cpu.CurrentInstruction.Operands[0]
-
 	var calculation types.Word
 
 	// immediate value is signed
@@ -1941,9 +2193,7 @@ func (cpu *GbcCPU) Resb_hl(b byte) {
 }
 
 //JP nn
-func (cpu *GbcCPU) JP_nn() {
-	var ls byte = cpu.CurrentInstruction.Operands[0]
-	var hs byte = cpu.CurrentInstruction.Operands[1]
+func (cpu *GbcCPU) JP_nn(ls, hs byte) {
 	cpu.PC = types.Word(utils.JoinBytes(hs, ls))
 	cpu.PCJumped = true
 	// cycles = 4
@@ -1958,10 +2208,7 @@ func (cpu *GbcCPU) JP_hl() {
 }
 
 //JP cc, nn
-func (cpu *GbcCPU) JPcc_nn(flag int, jumpWhen bool) {
-	var ls byte = cpu.CurrentInstruction.Operands[0]
-	var hs byte = cpu.CurrentInstruction.Operands[1]
-
+func (cpu *GbcCPU) JPcc_nn(flag int, jumpWhen bool, ls, hs byte) {
 	if cpu.IsFlagSet(flag) == jumpWhen {
 		cpu.PCJumped = true
 		cpu.PC = types.Word(utils.JoinBytes(hs, ls))
@@ -1971,11 +2218,9 @@ func (cpu *GbcCPU) JPcc_nn(flag int, jumpWhen bool) {
 }
 
 //JR n
-func (cpu *GbcCPU) JR_n() {
-	var n byte = cpu.CurrentInstruction.Operands[0]
-
+func (cpu *GbcCPU) JR_n(n byte) {
 	if n != 0x00 {
-		cpu.PC += types.Word(cpu.CurrentInstruction.OperandsSize + 1)
+		cpu.PC += 2
 
 		if n > 127 {
 			cpu.PC -= types.Word(-n)
@@ -1990,12 +2235,10 @@ func (cpu *GbcCPU) JR_n() {
 }
 
 //JR cc, nn
-func (cpu *GbcCPU) JRcc_nn(flag int, jumpWhen bool) {
-	var n byte = cpu.CurrentInstruction.Operands[0]
-
+func (cpu *GbcCPU) JRcc_nn(flag int, jumpWhen bool, n byte) {
 	if cpu.IsFlagSet(flag) == jumpWhen {
 		if n != 0x00 {
-			cpu.PC += types.Word(cpu.CurrentInstruction.OperandsSize + 1)
+			cpu.PC += 2
 
 			if n > 127 {
 				cpu.PC -= types.Word(-n)
@@ -2006,55 +2249,49 @@ func (cpu *GbcCPU) JRcc_nn(flag int, jumpWhen bool) {
 			cpu.PCJumped = true
 		}
 		cpu.tick(1)
-	} else {
-		cpu.CurrentInstruction.Cycles = 2
 	}
 }
 
 // CALL nn
 //Push address of next instruction onto stack and then jump to address nn
-func (cpu *GbcCPU) Call_nn() {
-	var ls byte = cpu.CurrentInstruction.Operands[0]
-	var hs byte = cpu.CurrentInstruction.Operands[1]
+func (cpu *GbcCPU) Call_nn(ls, hs byte) {
 	var nextInstr types.Word = cpu.PC + 3
+	cpu.tick(1)
+	cpu.onCall(CALL_FRAME, types.Word(utils.JoinBytes(hs, ls)))
 	cpu.pushWordToStack(nextInstr)
 	cpu.PC = types.Word(utils.JoinBytes(hs, ls))
 	cpu.PCJumped = true
-	cpu.tick(1)
 }
 
 // CALL cc,nn
-func (cpu *GbcCPU) Callcc_nn(flag int, callWhen bool) {
-	var ls byte = cpu.CurrentInstruction.Operands[0]
-	var hs byte = cpu.CurrentInstruction.Operands[1]
+func (cpu *GbcCPU) Callcc_nn(flag int, callWhen bool, ls, hs byte) {
 	var nextInstr types.Word = cpu.PC + 3
 
 	if cpu.IsFlagSet(flag) == callWhen {
+		cpu.tick(1)
+		cpu.onCall(CALL_FRAME, types.Word(utils.JoinBytes(hs, ls)))
 		cpu.pushWordToStack(nextInstr)
 		cpu.PC = types.Word(utils.JoinBytes(hs, ls))
 		cpu.PCJumped = true
-
-		cpu.CurrentInstruction.Cycles = 6
-		cpu.tick(1)
-	} else {
-		cpu.CurrentInstruction.Cycles = 3
 	}
 }
 
 // RET
 func (cpu *GbcCPU) Ret() {
 	cpu.PC = cpu.popWordFromStack()
+	cpu.onReturn()
 	cpu.PCJumped = true
 	cpu.tick(1)
 }
 
 // RET cc
 func (cpu *GbcCPU) Retcc(flag int, returnWhen bool) {
+	//the condition is checked in an internal cycle before the stack is popped
+	cpu.tick(1)
 	if cpu.IsFlagSet(flag) == returnWhen {
 		cpu.PC = cpu.popWordFromStack()
+		cpu.onReturn()
 		cpu.PCJumped = true
-		cpu.tick(2)
-	} else {
 		cpu.tick(1)
 	}
 }
@@ -2062,6 +2299,7 @@ func (cpu *GbcCPU) Retcc(flag int, returnWhen bool) {
 // RETI
 func (cpu *GbcCPU) Ret_i() {
 	cpu.PC = cpu.popWordFromStack()
+	cpu.onReturn()
 	cpu.InterruptsEnabled = true
 	cpu.PCJumped = true
 	cpu.tick(1)
@@ -2069,10 +2307,11 @@ func (cpu *GbcCPU) Ret_i() {
 
 // RST n
 func (cpu *GbcCPU) Rst(n byte) {
+	cpu.tick(1)
+	cpu.onCall(RST_FRAME, types.Word(n))
 	cpu.pushWordToStack(cpu.PC + 1)
 	cpu.PC = types.Word(n)
 	cpu.PCJumped = true
-	cpu.tick(1)
 }
 
 //-----------------------------------------------------------------------
@@ -3,9 +3,14 @@ package cpu
 // Timing tests inspired by http://www.devrs.com/gb/files/opcodes.html
 
 import (
+	"bytes"
+	"fmt"
 	"testing"
 
 	"github.com/djhworld/gomeboycolor/cartridge"
+	"github.com/djhworld/gomeboycolor/constants"
+	"github.com/djhworld/gomeboycolor/mmu"
+	"github.com/djhworld/gomeboycolor/profiler"
 	"github.com/djhworld/gomeboycolor/timer"
 	"github.com/djhworld/gomeboycolor/types"
 	"github.com/djhworld/gomeboycolor/utils"
@@ -20,11 +25,14 @@ type FlagState struct {
 
 func TestEmptyInstruction(t *testing.T) {
 	c := setupCPU(nil)
-	EMPTY_INSTRUCTION.Execute(c)
+	EMPTY_INSTRUCTION.Execute(c, [2]byte{})
+	if !c.Locked {
+		t.Fatal("Expected the CPU to lock up")
+	}
 }
 
 func TestInstructionStringer(t *testing.T) {
-	i := &Instruction{0xF2, "foo", 1, 2, func(c *GbcCPU) {}}
+	i := &Instruction{0xF2, "foo", 1, 2, func(c *GbcCPU, operands [2]byte) {}}
 	s := i.String()
 	if s != "0xF2 foo" {
 		t.Log("Got result:", s)
@@ -174,7 +182,7 @@ func TestMiscTimings(t *testing.T) {
 	RunInstrAndAssertTimings(0xF3, nil, 1, t) // DI
 	RunInstrAndAssertTimings(0xFB, nil, 1, t) // EI
 
-	//TODO: this is supposed to be 1 cycle but we do the CGB speed check here....
+	//TODO: this is supposed to be 1 cycle but the second byte of STOP is read as an operand
 	RunInstrAndAssertTimings(0x10, nil, 2, t) // STOP
 }
 
@@ -342,16 +350,16 @@ func assertTimings(c *GbcCPU, t *testing.T, instr byte, expectedTiming int, isCB
 	cputicks := c.Step()
 
 	if isCB {
-		t.Log("0xCB "+utils.ByteToString(instr)+" ("+c.CurrentInstruction.Description+")", "testing that instruction runs for", expectedTiming, "cycles")
+		t.Log("0xCB "+utils.ByteToString(instr)+" ("+c.CurrentInstruction().Description+")", "testing that instruction runs for", expectedTiming, "cycles")
 	} else {
-		t.Log(utils.ByteToString(instr)+" ("+c.CurrentInstruction.Description+")", "testing that instruction runs for", expectedTiming, "cycles")
+		t.Log(utils.ByteToString(instr)+" ("+c.CurrentInstruction().Description+")", "testing that instruction runs for", expectedTiming, "cycles")
 	}
 
 	if cputicks != expectedTiming {
 		if isCB {
-			t.Log("FAILED -----> instruction 0xCB", utils.ByteToString(instr)+" ("+c.CurrentInstruction.Description+")", "Expected", expectedTiming, "but got", cputicks)
+			t.Log("FAILED -----> instruction 0xCB", utils.ByteToString(instr)+" ("+c.CurrentInstruction().Description+")", "Expected", expectedTiming, "but got", cputicks)
 		} else {
-			t.Log("FAILED -----> instruction", utils.ByteToString(instr)+" ("+c.CurrentInstruction.Description+")", "Expected", expectedTiming, "but got", cputicks)
+			t.Log("FAILED -----> instruction", utils.ByteToString(instr)+" ("+c.CurrentInstruction().Description+")", "Expected", expectedTiming, "but got", cputicks)
 
 		}
 		t.Fail()
@@ -439,3 +447,483 @@ func (m *MockMMU) LoadBIOS(data []byte) (bool, error) {
 
 func (m *MockMMU) LoadCartridge(cart *cartridge.Cartridge) {
 }
+
+func setupInterruptCPU(program []byte, ie, iflag byte, ime bool) *GbcCPU {
+	c := setupCPU(nil)
+	for i, b := range program {
+		c.mmu.WriteByte(types.Word(i), b)
+	}
+	c.mmu.WriteByte(constants.INTERRUPT_ENABLED_FLAG_ADDR, ie)
+	c.mmu.WriteByte(constants.INTERRUPT_FLAG_ADDR, iflag)
+	c.InterruptsEnabled = ime
+	c.SP = 0xFFFE
+	return c
+}
+
+func TestEIIsDelayedByOneInstruction(t *testing.T) {
+	c := setupInterruptCPU([]byte{0xFB, 0x00, 0x00}, 0x01, 0x01, false) // EI, NOP, NOP
+
+	c.Step()
+	if c.InterruptsEnabled {
+		t.Fatal("Interrupts should not be enabled straight after EI")
+	}
+
+	c.Step()
+	if !c.InterruptsEnabled || c.PC != 0x0002 {
+		t.Fatal("Interrupts should be enabled after the instruction following EI, PC:", c.PC)
+	}
+
+	c.Step()
+	if c.PC != types.Word(constants.V_BLANK_IR_ADDR)+1 || c.mmu.ReadByte(0xFFFC) != 0x02 {
+		t.Fatal("Expected vblank interrupt to be serviced returning to 0x0002, PC:", c.PC)
+	}
+}
+
+func TestDICancelsEI(t *testing.T) {
+	c := setupInterruptCPU([]byte{0xFB, 0xF3, 0x00, 0x00}, 0x01, 0x01, false) // EI, DI, NOP, NOP
+
+	for i := 0; i < 3; i++ {
+		c.Step()
+	}
+
+	if c.InterruptsEnabled || c.PC != 0x0003 {
+		t.Fatal("Expected interrupts to stay disabled, PC:", c.PC)
+	}
+}
+
+func TestHaltWakesOnPendingInterruptWithInterruptsDisabled(t *testing.T) {
+	c := setupInterruptCPU([]byte{0x76, 0x00, 0x00}, 0x04, 0x00, false) // HALT, NOP, NOP
+
+	c.Step()
+	c.Step()
+	if !c.Halted {
+		t.Fatal("Expected CPU to be halted")
+	}
+
+	c.mmu.WriteByte(constants.INTERRUPT_FLAG_ADDR, 0x04)
+	c.Step()
+	if c.Halted {
+		t.Fatal("Expected CPU to leave HALT when IE & IF is non zero")
+	}
+
+	c.Step()
+	if c.PC != 0x0002 {
+		t.Fatal("Expected execution to continue after HALT without servicing the interrupt, PC:", c.PC)
+	}
+}
+
+func TestHaltDoesNotWakeOnDisabledInterrupt(t *testing.T) {
+	c := setupInterruptCPU([]byte{0x76}, 0x01, 0x00, true)
+
+	c.Step()
+	c.mmu.WriteByte(constants.INTERRUPT_FLAG_ADDR, 0x04)
+	c.Step()
+
+	if !c.Halted {
+		t.Fatal("Expected CPU to stay halted when the requested interrupt is not enabled")
+	}
+}
+
+func TestHaltWakesAndServicesInterrupt(t *testing.T) {
+	c := setupInterruptCPU([]byte{0x76, 0x00}, 0x01, 0x00, true)
+
+	c.Step()
+	c.mmu.WriteByte(constants.INTERRUPT_FLAG_ADDR, 0x01)
+	c.Step()
+	c.Step()
+
+	if c.PC != types.Word(constants.V_BLANK_IR_ADDR)+1 || c.mmu.ReadByte(0xFFFC) != 0x01 {
+		t.Fatal("Expected vblank interrupt to be serviced returning to 0x0001, PC:", c.PC)
+	}
+}
+
+func TestHaltBug(t *testing.T) {
+	c := setupInterruptCPU([]byte{0x76, 0x3E, 0x14}, 0x01, 0x01, false) // HALT, LD A,0x14
+
+	c.Step()
+	if c.Halted {
+		t.Fatal("HALT with interrupts disabled and an interrupt pending should not halt")
+	}
+
+	c.Step()
+	if c.R.A != 0x3E || c.PC != 0x0002 {
+		t.Fatal("Expected the byte after HALT to be read twice, A:", c.R.A, "PC:", c.PC)
+	}
+
+	c.Step() // INC D
+	if c.R.D != 0x01 || c.PC != 0x0003 {
+		t.Fatal("Expected INC D to run after the bugged instruction, D:", c.R.D, "PC:", c.PC)
+	}
+}
+
+func TestStopWaitsForJoypadInput(t *testing.T) {
+	c := setupInterruptCPU([]byte{0x10, 0x00, 0x00}, 0x00, 0x00, false) // STOP, NOP
+
+	c.Step()
+	c.Step()
+	if !c.Stopped || c.PC != 0x0002 {
+		t.Fatal("Expected CPU to be stopped, PC:", c.PC)
+	}
+
+	c.mmu.WriteByte(constants.INTERRUPT_FLAG_ADDR, constants.JOYP_HILO_IRQ)
+	c.Step()
+	if c.Stopped {
+		t.Fatal("Expected joypad input to leave STOP mode")
+	}
+
+	c.Step()
+	if c.PC != 0x0003 {
+		t.Fatal("Expected execution to continue after STOP, PC:", c.PC)
+	}
+}
+
+func TestStopResetsDIV(t *testing.T) {
+	c := setupInterruptCPU([]byte{0x10, 0x00}, 0x00, 0x00, false)
+	c.timer.Step(1000)
+
+	c.Step()
+
+	if div := c.timer.Read(timer.DIV_REGISTER); div != 0x00 {
+		t.Fatal("Expected DIV to be reset, got", div)
+	}
+}
+
+func TestStopPerformsArmedSpeedSwitch(t *testing.T) {
+	c := setupInterruptCPU([]byte{0x10, 0x00, 0x00}, 0x00, 0x00, false)
+	c.RunningColorGBHardware = true
+	c.SpeedSwitch.Write(mmu.CGB_DOUBLE_SPEED_PREP_REG, 0x01)
+	var changedTo int
+	c.LinkSpeedChangeHandler(func(speed int) {
+		changedTo = speed
+	})
+
+	c.Step()
+	if c.Stopped || c.Speed != 2 || c.SpeedSwitch.Read(mmu.CGB_DOUBLE_SPEED_PREP_REG) != 0x80 {
+		t.Fatal("Expected the CPU to switch to double speed, speed:", c.Speed)
+	}
+	if changedTo != 2 {
+		t.Fatal("Expected the speed change handler to be told about the switch, got", changedTo)
+	}
+
+	for i := 0; i < SPEED_SWITCH_CYCLES; i++ {
+		if cycles := c.Step(); cycles != 1 || c.PC != 0x0002 {
+			t.Fatal("Expected the CPU to be paused during the speed switch, PC:", c.PC)
+		}
+	}
+
+	c.Step()
+	if c.PC != 0x0003 {
+		t.Fatal("Expected execution to continue after the speed switch, PC:", c.PC)
+	}
+}
+
+//Arming a switch back to normal speed mustn't clear the bit that says the CPU is in double speed
+func TestSpeedSwitchRegisterReadsBackSpeed(t *testing.T) {
+	c := setupInterruptCPU([]byte{0x10, 0x00}, 0x00, 0x00, false)
+	c.RunningColorGBHardware = true
+	c.Speed = 2
+
+	c.SpeedSwitch.Write(mmu.CGB_DOUBLE_SPEED_PREP_REG, 0x01)
+	if value := c.SpeedSwitch.Read(mmu.CGB_DOUBLE_SPEED_PREP_REG); value != 0x81 {
+		t.Fatalf("Expected KEY1 to read 0x81 once armed in double speed, got 0x%02X", value)
+	}
+
+	c.SpeedSwitch.Write(mmu.CGB_DOUBLE_SPEED_PREP_REG, 0xFE)
+	if value := c.SpeedSwitch.Read(mmu.CGB_DOUBLE_SPEED_PREP_REG); value != 0x80 {
+		t.Fatalf("Expected only bit 0 of KEY1 to be writable, got 0x%02X", value)
+	}
+
+	c.SpeedSwitch.Write(mmu.CGB_DOUBLE_SPEED_PREP_REG, 0x01)
+	c.Step()
+	if c.Speed != 1 || c.SpeedSwitch.Read(mmu.CGB_DOUBLE_SPEED_PREP_REG) != 0x00 {
+		t.Fatal("Expected the CPU to switch back to normal speed, speed:", c.Speed)
+	}
+}
+
+func TestStopIgnoresSpeedSwitchOnDMG(t *testing.T) {
+	c := setupInterruptCPU([]byte{0x10, 0x00}, 0x00, 0x00, false)
+	c.SpeedSwitch.Write(mmu.CGB_DOUBLE_SPEED_PREP_REG, 0x01)
+
+	c.Step()
+
+	if !c.Stopped || c.Speed != 1 {
+		t.Fatal("Expected DMG to enter STOP mode rather than switch speed")
+	}
+}
+
+func TestTraceMatchesGameboyDoctorFormat(t *testing.T) {
+	c := setupInterruptCPU([]byte{0x00, 0xC3, 0x13, 0x02}, 0x00, 0x00, false)
+	c.R = Registers{A: 0x01, F: 0xB0, B: 0x00, C: 0x13, D: 0x00, E: 0xD8, H: 0x01, L: 0x4D}
+
+	var out bytes.Buffer
+	tracer := NewTracer(&out)
+	c.SetTracer(tracer)
+	c.Step()
+	c.Step()
+	c.SetTracer(nil)
+	c.Step()
+
+	if out.Len() != 0 {
+		t.Fatal("Expected trace output to be buffered until flushed")
+	}
+
+	if err := tracer.Flush(); err != nil {
+		t.Fatal("Unexpected error flushing trace:", err)
+	}
+
+	var expected string = "A:01 F:B0 B:00 C:13 D:00 E:D8 H:01 L:4D SP:FFFE PC:0000 PCMEM:00,C3,13,02\n" +
+		"A:01 F:B0 B:00 C:13 D:00 E:D8 H:01 L:4D SP:FFFE PC:0001 PCMEM:C3,13,02,00\n"
+	if out.String() != expected {
+		t.Fatalf("Expected trace:\n%s\nbut got:\n%s", expected, out.String())
+	}
+}
+
+func TestInterruptDispatchTakesFiveCycles(t *testing.T) {
+	c := setupInterruptCPU([]byte{0x00}, 0x01, 0x01, true)
+
+	//dispatch plus the NOP at the vector
+	if cycles := c.Step(); cycles != 6 {
+		t.Fatal("Expected servicing an interrupt to take 5 M-cycles plus 1 for the NOP, got", cycles)
+	}
+}
+
+func TestClockHandlerSeesEveryCycle(t *testing.T) {
+	c := setupInterruptCPU([]byte{0xCD, 0x00, 0x40}, 0x00, 0x00, false) // CALL 0x4000
+
+	var ticked int
+	c.LinkClockHandler(func(cycles int) {
+		ticked += cycles
+	})
+
+	cycles := c.Step()
+	if cycles != 6 || ticked != cycles {
+		t.Fatal("Expected the clock handler to be told about all", cycles, "M-cycles, got", ticked)
+	}
+
+	if idle := c.Idle(8); idle != 8 || ticked != 14 {
+		t.Fatal("Expected idling to tick the clock handler, got", ticked)
+	}
+}
+
+//Records the M-cycle, as counted by the clock handler, that each memory access is made on
+type cycleRecordingMMU struct {
+	*FlatMMU
+	cycle    int
+	accesses []string
+}
+
+func (m *cycleRecordingMMU) ReadByte(address types.Word) byte {
+	m.accesses = append(m.accesses, fmt.Sprintf("%d: read 0x%04X", m.cycle, uint16(address)))
+	return m.FlatMMU.ReadByte(address)
+}
+
+func (m *cycleRecordingMMU) WriteByte(address types.Word, value byte) {
+	m.accesses = append(m.accesses, fmt.Sprintf("%d: write 0x%04X", m.cycle, uint16(address)))
+	m.FlatMMU.WriteByte(address, value)
+}
+
+//Every access an instruction makes has to land on its own M-cycle, after the cycles before it
+//have been seen by the rest of the system
+func TestMemoryAccessesLandOnTheirMCycle(t *testing.T) {
+	var tests = []struct {
+		name     string
+		program  []byte
+		expected []string
+	}{
+		{"LD (a16),A", []byte{0xEA, 0x00, 0xC0}, []string{
+			"1: read 0x0100", "2: read 0x0101", "3: read 0x0102", "4: write 0xC000",
+		}},
+		{"PUSH BC", []byte{0xC5}, []string{
+			"1: read 0x0100", "3: write 0xCFFF", "4: write 0xCFFE",
+		}},
+		{"CALL a16", []byte{0xCD, 0x00, 0x40}, []string{
+			"1: read 0x0100", "2: read 0x0101", "3: read 0x0102", "5: write 0xCFFF", "6: write 0xCFFE",
+		}},
+		{"RET", []byte{0xC9}, []string{
+			"1: read 0x0100", "2: read 0xD000", "3: read 0xD001",
+		}},
+	}
+
+	for _, test := range tests {
+		m := &cycleRecordingMMU{FlatMMU: new(FlatMMU)}
+		copy(m.memory[0x0100:], test.program)
+		c := NewCPU(m, timer.NewTimer())
+		c.PC = 0x0100
+		c.SP = 0xD000
+		c.InterruptsEnabled = false
+		c.LinkClockHandler(func(cycles int) {
+			m.cycle += cycles
+		})
+
+		cycles := c.Step()
+		if fmt.Sprint(m.accesses) != fmt.Sprint(test.expected) {
+			t.Errorf("%s made accesses %v, expected %v", test.name, m.accesses, test.expected)
+		}
+		if cycles != m.cycle {
+			t.Errorf("%s took %d M-cycles but ticked the clock handler %d times", test.name, cycles, m.cycle)
+		}
+	}
+}
+
+func TestCallStackFollowsCallsAndReturns(t *testing.T) {
+	var program []byte = make([]byte, 0x30)
+	copy(program, []byte{0xCD, 0x10, 0x00})  // CALL 0x0010
+	copy(program[0x10:], []byte{0xE7, 0xC9}) // RST 0x20, RET
+	program[0x20] = 0xC9                     // RET
+	c := setupInterruptCPU(program, 0x00, 0x00, false)
+	c.TrackCallStack(true)
+
+	c.Step()
+	c.Step()
+	stack := c.CallStack()
+	if len(stack) != 2 {
+		t.Fatal("Expected CALL and RST frames on the call stack, got", stack)
+	}
+	if stack[0].Kind != CALL_FRAME || stack[0].CallSite != 0x0000 || stack[0].Target != 0x0010 || stack[0].SP != 0xFFFE {
+		t.Fatal("Unexpected CALL frame", stack[0])
+	}
+	if stack[1].Kind != RST_FRAME || stack[1].CallSite != 0x0010 || stack[1].Target != 0x0020 || stack[1].SP != 0xFFFC {
+		t.Fatal("Unexpected RST frame", stack[1])
+	}
+
+	c.Step()
+	if stack := c.CallStack(); len(stack) != 1 || c.PC != 0x0011 {
+		t.Fatal("Expected RET to pop the RST frame, PC:", c.PC, "stack:", stack)
+	}
+
+	c.Step()
+	if stack := c.CallStack(); len(stack) != 0 || c.PC != 0x0003 {
+		t.Fatal("Expected the call stack to be empty, PC:", c.PC, "stack:", stack)
+	}
+}
+
+func TestCallStackRecordsInterrupts(t *testing.T) {
+	c := setupInterruptCPU([]byte{0x00, 0x00}, 0x01, 0x00, true)
+	c.TrackCallStack(true)
+	c.LinkBankMapper(func(address types.Word) int {
+		return 3
+	})
+	c.mmu.WriteByte(types.Word(constants.V_BLANK_IR_ADDR), 0x00)   // NOP
+	c.mmu.WriteByte(types.Word(constants.V_BLANK_IR_ADDR)+1, 0xD9) // RETI
+
+	c.Step()
+	c.mmu.WriteByte(constants.INTERRUPT_FLAG_ADDR, 0x01)
+	c.Step()
+	stack := c.CallStack()
+	if len(stack) != 1 || stack[0].Kind != INTERRUPT_FRAME || stack[0].CallSite != 0x0001 || stack[0].Target != types.Word(constants.V_BLANK_IR_ADDR) || stack[0].TargetBank != 3 {
+		t.Fatal("Expected an interrupt frame returning to 0x0001, got", stack)
+	}
+
+	c.Step()
+	if stack := c.CallStack(); len(stack) != 0 {
+		t.Fatal("Expected RETI to pop the interrupt frame, got", stack)
+	}
+}
+
+func TestCallStackDropsFramesSkippedByReturn(t *testing.T) {
+	var program []byte = make([]byte, 0x30)
+	copy(program, []byte{0xCD, 0x10, 0x00})        // CALL 0x0010
+	copy(program[0x10:], []byte{0xCD, 0x20, 0x00}) // CALL 0x0020
+	copy(program[0x20:], []byte{0xE1, 0xC9})       // POP HL, RET
+	c := setupInterruptCPU(program, 0x00, 0x00, false)
+	c.TrackCallStack(true)
+
+	for i := 0; i < 4; i++ {
+		c.Step()
+	}
+
+	if stack := c.CallStack(); len(stack) != 0 || c.PC != 0x0003 {
+		t.Fatal("Expected returning past the outer call to empty the call stack, PC:", c.PC, "stack:", stack)
+	}
+}
+
+func TestCallStackIsOnlyKeptWhenTracked(t *testing.T) {
+	var program []byte = make([]byte, 0x20)
+	copy(program, []byte{0xCD, 0x10, 0x00}) // CALL 0x0010
+	c := setupInterruptCPU(program, 0x00, 0x00, false)
+	c.LinkBankMapper(func(address types.Word) int {
+		t.Fatal("Expected no bank lookups while the call stack is not tracked")
+		return 0
+	})
+
+	c.Step()
+	if stack := c.CallStack(); len(stack) != 0 {
+		t.Fatal("Expected no frames while the call stack is not tracked, got", stack)
+	}
+}
+
+func TestProfilerChargesInstructionsToTheFunctionOnTheCallStack(t *testing.T) {
+	var program []byte = make([]byte, 0x50)
+	copy(program, []byte{0xCD, 0x10, 0x00, 0x00}) // CALL 0x0010, NOP
+	program[0x10] = 0xC9                          // RET
+	program[0x40] = 0xD9                          // RETI
+	c := setupInterruptCPU(program, 0x01, 0x00, true)
+	p := profiler.NewProfiler()
+	c.SetProfiler(p)
+
+	for i := 0; i < 3; i++ {
+		c.Step()
+	}
+	c.mmu.WriteByte(constants.INTERRUPT_FLAG_ADDR, 0x01)
+	c.Step()
+	c.Step()
+
+	functions := make(map[profiler.Location]profiler.FunctionProfile)
+	for _, f := range p.Functions() {
+		functions[f.Function] = f
+	}
+
+	//the CALL is charged to the caller and the RET to the callee
+	if f := functions[profiler.Location{PC: 0x0010}]; f.Calls != 1 || f.Self != 16 {
+		t.Error("Expected the subroutine to be charged for its RET, got", f)
+	}
+	//the handler is charged for its dispatch along with its RETI
+	if f := functions[profiler.Location{PC: 0x0040}]; f.Calls != 1 || f.Self != 20+16 {
+		t.Error("Expected the interrupt handler to be charged for its dispatch and RETI, got", f)
+	}
+	if f := functions[profiler.ROOT]; f.Self != 24+4+4 || f.Total != p.Total() {
+		t.Error("Expected the CALL and NOPs to be charged to the root, got", f)
+	}
+	if stack := c.CallStack(); len(stack) != 0 {
+		t.Error("Expected the call stack to be empty, got", stack)
+	}
+}
+
+func TestIllegalOpcodeLocksUpCPU(t *testing.T) {
+	c := setupInterruptCPU([]byte{0x00, 0xD3, 0x00}, 0x01, 0x00, true) // NOP, illegal, NOP
+
+	var lockups []*IllegalOpcodeError
+	c.LinkLockupHandler(func(err *IllegalOpcodeError) {
+		lockups = append(lockups, err)
+	})
+
+	c.Step()
+	c.Step()
+	if !c.Locked || c.PC != 0x0001 {
+		t.Fatal("Expected the CPU to lock up at the illegal opcode, PC:", c.PC)
+	}
+
+	expected := IllegalOpcodeError{Opcode: 0xD3, Address: 0x0001}
+	if len(lockups) != 1 || *lockups[0] != expected || *c.LockupError() != expected {
+		t.Fatal("Expected the lockup handler to be called once with", expected, "got", lockups)
+	}
+	if msg := c.LockupError().Error(); msg != "CPU locked up executing illegal opcode 0xD3 at 00:0001" {
+		t.Error("Unexpected error message:", msg)
+	}
+
+	//interrupts are ignored and nothing else runs
+	c.mmu.WriteByte(constants.INTERRUPT_FLAG_ADDR, 0x01)
+	for i := 0; i < 10; i++ {
+		if cycles := c.Step(); cycles != 1 || c.PC != 0x0001 {
+			t.Fatal("Expected a locked CPU to do nothing, PC:", c.PC)
+		}
+	}
+	if len(lockups) != 1 {
+		t.Fatal("Expected the lockup handler to be called once, got", len(lockups))
+	}
+
+	c.Reset()
+	if c.Locked || c.LockupError() != nil {
+		t.Fatal("Expected reset to clear the lockup")
+	}
+}
@@ -0,0 +1,1060 @@
+// Code generated by gen_dispatch.go from instructions.go; DO NOT EDIT.
+
+package cpu
+
+// Number of operand bytes that follow each opcode in Instructions, CB prefixed instructions
+// don't take any
+var operandsSizes [256]int = [256]int{
+	0, 2, 0, 0, 0, 0, 1, 0, 2, 0, 0, 0, 0, 0, 1, 0, //0x00-0x0F
+	1, 2, 0, 0, 0, 0, 1, 0, 1, 0, 0, 0, 0, 0, 1, 0, //0x10-0x1F
+	1, 2, 0, 0, 0, 0, 1, 0, 1, 0, 0, 0, 0, 0, 1, 0, //0x20-0x2F
+	1, 2, 0, 0, 0, 0, 1, 0, 1, 0, 0, 0, 0, 0, 1, 0, //0x30-0x3F
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, //0x40-0x4F
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, //0x50-0x5F
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, //0x60-0x6F
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, //0x70-0x7F
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, //0x80-0x8F
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, //0x90-0x9F
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, //0xA0-0xAF
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, //0xB0-0xBF
+	0, 0, 2, 2, 2, 0, 1, 0, 0, 0, 2, 0, 2, 2, 1, 0, //0xC0-0xCF
+	0, 0, 2, 0, 2, 0, 1, 0, 0, 0, 2, 0, 2, 0, 1, 0, //0xD0-0xDF
+	1, 0, 0, 0, 0, 0, 1, 0, 1, 0, 2, 0, 0, 0, 1, 0, //0xE0-0xEF
+	1, 0, 0, 0, 0, 0, 1, 0, 1, 0, 2, 0, 0, 0, 1, 0, //0xF0-0xFF
+}
+
+// Runs the instruction for opcode from Instructions once it and its operands have been fetched
+func (cpu *GbcCPU) execute(opcode byte, operands [2]byte) {
+	switch opcode {
+	case 0x00: //NOP
+		cpu.NOP()
+	case 0x01: //LD BC,d16
+		cpu.LDn_nn(&cpu.R.B, &cpu.R.C, operands[0], operands[1])
+	case 0x02: //LD (BC),A
+		cpu.LDrr_r(&cpu.R.B, &cpu.R.C, &cpu.R.A)
+	case 0x03: //INC BC
+		cpu.Inc_rr(&cpu.R.B, &cpu.R.C)
+	case 0x04: //INC B
+		cpu.Inc_r(&cpu.R.B)
+	case 0x05: //DEC B
+		cpu.Dec_r(&cpu.R.B)
+	case 0x06: //LD B,n
+		cpu.LDrn(&cpu.R.B, operands[0])
+	case 0x07: //RLCA
+		cpu.RLCA()
+	case 0x08: //LD (a16),SP
+		cpu.LDnn_SP(operands[0], operands[1])
+	case 0x09: //ADD HL,BC
+		cpu.Addhl_rr(&cpu.R.B, &cpu.R.C)
+	case 0x0A: //LD A,(BC)
+		cpu.LDr_rr(&cpu.R.B, &cpu.R.C, &cpu.R.A)
+	case 0x0B: //DEC BC
+		cpu.Dec_rr(&cpu.R.B, &cpu.R.C)
+	case 0x0C: //INC C
+		cpu.Inc_r(&cpu.R.C)
+	case 0x0D: //DEC C
+		cpu.Dec_r(&cpu.R.C)
+	case 0x0E: //LD C,n
+		cpu.LDrn(&cpu.R.C, operands[0])
+	case 0x0F: //RRCA
+		cpu.RRCA()
+	case 0x10: //STOP
+		cpu.Stop()
+	case 0x11: //LD DE,d16
+		cpu.LDn_nn(&cpu.R.D, &cpu.R.E, operands[0], operands[1])
+	case 0x12: //LD (DE),A
+		cpu.LDrr_r(&cpu.R.D, &cpu.R.E, &cpu.R.A)
+	case 0x13: //INC DE
+		cpu.Inc_rr(&cpu.R.D, &cpu.R.E)
+	case 0x14: //INC D
+		cpu.Inc_r(&cpu.R.D)
+	case 0x15: //DEC D
+		cpu.Dec_r(&cpu.R.D)
+	case 0x16: //LD D,n
+		cpu.LDrn(&cpu.R.D, operands[0])
+	case 0x17: //RLA
+		cpu.RLA()
+	case 0x18: //JR r8
+		cpu.JR_n(operands[0])
+	case 0x19: //ADD HL,DE
+		cpu.Addhl_rr(&cpu.R.D, &cpu.R.E)
+	case 0x1A: //LD A,(DE)
+		cpu.LDr_rr(&cpu.R.D, &cpu.R.E, &cpu.R.A)
+	case 0x1B: //DEC DE
+		cpu.Dec_rr(&cpu.R.D, &cpu.R.E)
+	case 0x1C: //INC E
+		cpu.Inc_r(&cpu.R.E)
+	case 0x1D: //DEC E
+		cpu.Dec_r(&cpu.R.E)
+	case 0x1E: //LD E,n
+		cpu.LDrn(&cpu.R.E, operands[0])
+	case 0x1F: //RRA
+		cpu.RRA()
+	case 0x20: //JR NZ,r8
+		cpu.JRcc_nn(Z, false, operands[0])
+	case 0x21: //LD HL,d16
+		cpu.LDn_nn(&cpu.R.H, &cpu.R.L, operands[0], operands[1])
+	case 0x22: //LD (HL+),A
+		cpu.LDIhl_r(&cpu.R.A)
+	case 0x23: //INC HL
+		cpu.Inc_rr(&cpu.R.H, &cpu.R.L)
+	case 0x24: //INC H
+		cpu.Inc_r(&cpu.R.H)
+	case 0x25: //DEC H
+		cpu.Dec_r(&cpu.R.H)
+	case 0x26: //LD H,n
+		cpu.LDrn(&cpu.R.H, operands[0])
+	case 0x27: //DAA
+		cpu.Daa()
+	case 0x28: //JR Z,r8
+		cpu.JRcc_nn(Z, true, operands[0])
+	case 0x29: //ADD HL,HL
+		cpu.Addhl_rr(&cpu.R.H, &cpu.R.L)
+	case 0x2A: //LD A,(HL+)
+		cpu.LDIr_hl(&cpu.R.A)
+	case 0x2B: //DEC HL
+		cpu.Dec_rr(&cpu.R.H, &cpu.R.L)
+	case 0x2C: //INC L
+		cpu.Inc_r(&cpu.R.L)
+	case 0x2D: //DEC L
+		cpu.Dec_r(&cpu.R.L)
+	case 0x2E: //LD L,n
+		cpu.LDrn(&cpu.R.L, operands[0])
+	case 0x2F: //CPL
+		cpu.CPL()
+	case 0x30: //JR NC,r8
+		cpu.JRcc_nn(C, false, operands[0])
+	case 0x31: //LD SP,d16
+		cpu.LDSP_nn(operands[0], operands[1])
+	case 0x32: //LD (HL-),A
+		cpu.LDDhl_r(&cpu.R.A)
+	case 0x33: //INC SP
+		cpu.Inc_sp()
+	case 0x34: //INC (HL)
+		cpu.Inc_hl()
+	case 0x35: //DEC (HL)
+		cpu.Dec_hl()
+	case 0x36: //LD (HL),d8
+		cpu.LDhl_n(operands[0])
+	case 0x37: //SCF
+		cpu.SCF()
+	case 0x38: //JR C,r8
+		cpu.JRcc_nn(C, true, operands[0])
+	case 0x39: //ADD HL,SP
+		cpu.Addhl_sp()
+	case 0x3A: //LD A,(HL-)
+		cpu.LDDr_hl(&cpu.R.A)
+	case 0x3B: //DEC SP
+		cpu.Dec_sp()
+	case 0x3C: //INC A
+		cpu.Inc_r(&cpu.R.A)
+	case 0x3D: //DEC A
+		cpu.Dec_r(&cpu.R.A)
+	case 0x3E: //LD A,d8
+		cpu.LDrn(&cpu.R.A, operands[0])
+	case 0x3F: //CCF
+		cpu.CCF()
+	case 0x40: //LD B,B
+		cpu.LDrr(&cpu.R.B, &cpu.R.B)
+	case 0x41: //LD B,C
+		cpu.LDrr(&cpu.R.B, &cpu.R.C)
+	case 0x42: //LD B,D
+		cpu.LDrr(&cpu.R.B, &cpu.R.D)
+	case 0x43: //LD B,E
+		cpu.LDrr(&cpu.R.B, &cpu.R.E)
+	case 0x44: //LD B,H
+		cpu.LDrr(&cpu.R.B, &cpu.R.H)
+	case 0x45: //LD B,L
+		cpu.LDrr(&cpu.R.B, &cpu.R.L)
+	case 0x46: //LD B,(HL)
+		cpu.LDr_rr(&cpu.R.H, &cpu.R.L, &cpu.R.B)
+	case 0x47: //LD B,A
+		cpu.LDrr(&cpu.R.B, &cpu.R.A)
+	case 0x48: //LD C,B
+		cpu.LDrr(&cpu.R.C, &cpu.R.B)
+	case 0x49: //LD C,C
+		cpu.LDrr(&cpu.R.C, &cpu.R.C)
+	case 0x4A: //LD C,D
+		cpu.LDrr(&cpu.R.C, &cpu.R.D)
+	case 0x4B: //LD C,E
+		cpu.LDrr(&cpu.R.C, &cpu.R.E)
+	case 0x4C: //LD C,H
+		cpu.LDrr(&cpu.R.C, &cpu.R.H)
+	case 0x4D: //LD C,L
+		cpu.LDrr(&cpu.R.C, &cpu.R.L)
+	case 0x4E: //LD C,(HL)
+		cpu.LDr_rr(&cpu.R.H, &cpu.R.L, &cpu.R.C)
+	case 0x4F: //LD C,A
+		cpu.LDrr(&cpu.R.C, &cpu.R.A)
+	case 0x50: //LD D,B
+		cpu.LDrr(&cpu.R.D, &cpu.R.B)
+	case 0x51: //LD D,C
+		cpu.LDrr(&cpu.R.D, &cpu.R.C)
+	case 0x52: //LD D,D
+		cpu.LDrr(&cpu.R.D, &cpu.R.D)
+	case 0x53: //LD D,E
+		cpu.LDrr(&cpu.R.D, &cpu.R.E)
+	case 0x54: //LD D,H
+		cpu.LDrr(&cpu.R.D, &cpu.R.H)
+	case 0x55: //LD D,L
+		cpu.LDrr(&cpu.R.D, &cpu.R.L)
+	case 0x56: //LD D,(HL)
+		cpu.LDr_rr(&cpu.R.H, &cpu.R.L, &cpu.R.D)
+	case 0x57: //LD D,A
+		cpu.LDrr(&cpu.R.D, &cpu.R.A)
+	case 0x58: //LD E,B
+		cpu.LDrr(&cpu.R.E, &cpu.R.B)
+	case 0x59: //LD E,C
+		cpu.LDrr(&cpu.R.E, &cpu.R.C)
+	case 0x5A: //LD E,D
+		cpu.LDrr(&cpu.R.E, &cpu.R.D)
+	case 0x5B: //LD E,E
+		cpu.LDrr(&cpu.R.E, &cpu.R.E)
+	case 0x5C: //LD E,H
+		cpu.LDrr(&cpu.R.E, &cpu.R.H)
+	case 0x5D: //LD E,L
+		cpu.LDrr(&cpu.R.E, &cpu.R.L)
+	case 0x5E: //LD E,(HL)
+		cpu.LDr_rr(&cpu.R.H, &cpu.R.L, &cpu.R.E)
+	case 0x5F: //LD E,A
+		cpu.LDrr(&cpu.R.E, &cpu.R.A)
+	case 0x60: //LD H,B
+		cpu.LDrr(&cpu.R.H, &cpu.R.B)
+	case 0x61: //LD H,C
+		cpu.LDrr(&cpu.R.H, &cpu.R.C)
+	case 0x62: //LD H,D
+		cpu.LDrr(&cpu.R.H, &cpu.R.D)
+	case 0x63: //LD H,E
+		cpu.LDrr(&cpu.R.H, &cpu.R.E)
+	case 0x64: //LD H,H
+		cpu.LDrr(&cpu.R.H, &cpu.R.H)
+	case 0x65: //LD H,L
+		cpu.LDrr(&cpu.R.H, &cpu.R.L)
+	case 0x66: //LD H,(HL)
+		cpu.LDr_rr(&cpu.R.H, &cpu.R.L, &cpu.R.H)
+	case 0x67: //LD H,A
+		cpu.LDrr(&cpu.R.H, &cpu.R.A)
+	case 0x68: //LD L,B
+		cpu.LDrr(&cpu.R.L, &cpu.R.B)
+	case 0x69: //LD L,C
+		cpu.LDrr(&cpu.R.L, &cpu.R.C)
+	case 0x6A: //LD L,D
+		cpu.LDrr(&cpu.R.L, &cpu.R.D)
+	case 0x6B: //LD L,E
+		cpu.LDrr(&cpu.R.L, &cpu.R.E)
+	case 0x6C: //LD L,H
+		cpu.LDrr(&cpu.R.L, &cpu.R.H)
+	case 0x6D: //LD L,L
+		cpu.LDrr(&cpu.R.L, &cpu.R.L)
+	case 0x6E: //LD L,(HL)
+		cpu.LDr_rr(&cpu.R.H, &cpu.R.L, &cpu.R.L)
+	case 0x6F: //LD L,A
+		cpu.LDrr(&cpu.R.L, &cpu.R.A)
+	case 0x70: //LD (HL),B
+		cpu.LDrr_r(&cpu.R.H, &cpu.R.L, &cpu.R.B)
+	case 0x71: //LD (HL),C
+		cpu.LDrr_r(&cpu.R.H, &cpu.R.L, &cpu.R.C)
+	case 0x72: //LD (HL),D
+		cpu.LDrr_r(&cpu.R.H, &cpu.R.L, &cpu.R.D)
+	case 0x73: //LD (HL),E
+		cpu.LDrr_r(&cpu.R.H, &cpu.R.L, &cpu.R.E)
+	case 0x74: //LD (HL),H
+		cpu.LDrr_r(&cpu.R.H, &cpu.R.L, &cpu.R.H)
+	case 0x75: //LD (HL),L
+		cpu.LDrr_r(&cpu.R.H, &cpu.R.L, &cpu.R.L)
+	case 0x76: //HALT
+		cpu.HALT()
+	case 0x77: //LD (HL),A
+		cpu.LDrr_r(&cpu.R.H, &cpu.R.L, &cpu.R.A)
+	case 0x78: //LD A,B
+		cpu.LDrr(&cpu.R.A, &cpu.R.B)
+	case 0x79: //LD A,C
+		cpu.LDrr(&cpu.R.A, &cpu.R.C)
+	case 0x7A: //LD A,D
+		cpu.LDrr(&cpu.R.A, &cpu.R.D)
+	case 0x7B: //LD A,E
+		cpu.LDrr(&cpu.R.A, &cpu.R.E)
+	case 0x7C: //LD A,H
+		cpu.LDrr(&cpu.R.A, &cpu.R.H)
+	case 0x7D: //LD A,L
+		cpu.LDrr(&cpu.R.A, &cpu.R.L)
+	case 0x7E: //LD A,(HL)
+		cpu.LDr_rr(&cpu.R.H, &cpu.R.L, &cpu.R.A)
+	case 0x7F: //LD A,A
+		cpu.LDrr(&cpu.R.A, &cpu.R.A)
+	case 0x80: //ADD A,B
+		cpu.AddA_r(&cpu.R.B)
+	case 0x81: //ADD A,C
+		cpu.AddA_r(&cpu.R.C)
+	case 0x82: //ADD A,D
+		cpu.AddA_r(&cpu.R.D)
+	case 0x83: //ADD A,E
+		cpu.AddA_r(&cpu.R.E)
+	case 0x84: //ADD A,H
+		cpu.AddA_r(&cpu.R.H)
+	case 0x85: //ADD A,L
+		cpu.AddA_r(&cpu.R.L)
+	case 0x86: //ADD A,(HL)
+		cpu.AddA_hl()
+	case 0x87: //ADD A,A
+		cpu.AddA_r(&cpu.R.A)
+	case 0x88: //ADC A,B
+		cpu.AddCA_r(&cpu.R.B)
+	case 0x89: //ADC A,C
+		cpu.AddCA_r(&cpu.R.C)
+	case 0x8A: //ADC A,D
+		cpu.AddCA_r(&cpu.R.D)
+	case 0x8B: //ADC A,E
+		cpu.AddCA_r(&cpu.R.E)
+	case 0x8C: //ADC A,H
+		cpu.AddCA_r(&cpu.R.H)
+	case 0x8D: //ADC A,L
+		cpu.AddCA_r(&cpu.R.L)
+	case 0x8E: //ADC A,(HL)
+		cpu.AddCA_hl()
+	case 0x8F: //ADC A,A
+		cpu.AddCA_r(&cpu.R.A)
+	case 0x90: //SUB B
+		cpu.SubA_r(&cpu.R.B)
+	case 0x91: //SUB C
+		cpu.SubA_r(&cpu.R.C)
+	case 0x92: //SUB D
+		cpu.SubA_r(&cpu.R.D)
+	case 0x93: //SUB E
+		cpu.SubA_r(&cpu.R.E)
+	case 0x94: //SUB H
+		cpu.SubA_r(&cpu.R.H)
+	case 0x95: //SUB L
+		cpu.SubA_r(&cpu.R.L)
+	case 0x96: //SUB (HL)
+		cpu.SubA_hl()
+	case 0x97: //SUB A
+		cpu.SubA_r(&cpu.R.A)
+	case 0x98: //SBC A,B
+		cpu.SubAC_r(&cpu.R.B)
+	case 0x99: //SBC A,C
+		cpu.SubAC_r(&cpu.R.C)
+	case 0x9A: //SBC A,D
+		cpu.SubAC_r(&cpu.R.D)
+	case 0x9B: //SBC A,E
+		cpu.SubAC_r(&cpu.R.E)
+	case 0x9C: //SBC A,H
+		cpu.SubAC_r(&cpu.R.H)
+	case 0x9D: //SBC A,L
+		cpu.SubAC_r(&cpu.R.L)
+	case 0x9E: //SBC A,(HL)
+		cpu.SubAC_hl()
+	case 0x9F: //SBC A,A
+		cpu.SubAC_r(&cpu.R.A)
+	case 0xA0: //AND B
+		cpu.AndA_r(&cpu.R.B)
+	case 0xA1: //AND C
+		cpu.AndA_r(&cpu.R.C)
+	case 0xA2: //AND D
+		cpu.AndA_r(&cpu.R.D)
+	case 0xA3: //AND E
+		cpu.AndA_r(&cpu.R.E)
+	case 0xA4: //AND H
+		cpu.AndA_r(&cpu.R.H)
+	case 0xA5: //AND L
+		cpu.AndA_r(&cpu.R.L)
+	case 0xA6: //AND (HL)
+		cpu.AndA_hl()
+	case 0xA7: //AND A
+		cpu.AndA_r(&cpu.R.A)
+	case 0xA8: //XOR B
+		cpu.XorA_r(&cpu.R.B)
+	case 0xA9: //XOR C
+		cpu.XorA_r(&cpu.R.C)
+	case 0xAA: //XOR D
+		cpu.XorA_r(&cpu.R.D)
+	case 0xAB: //XOR E
+		cpu.XorA_r(&cpu.R.E)
+	case 0xAC: //XOR H
+		cpu.XorA_r(&cpu.R.H)
+	case 0xAD: //XOR L
+		cpu.XorA_r(&cpu.R.L)
+	case 0xAE: //XOR (HL)
+		cpu.XorA_hl()
+	case 0xAF: //XOR A
+		cpu.XorA_r(&cpu.R.A)
+	case 0xB0: //OR B
+		cpu.OrA_r(&cpu.R.B)
+	case 0xB1: //OR C
+		cpu.OrA_r(&cpu.R.C)
+	case 0xB2: //OR D
+		cpu.OrA_r(&cpu.R.D)
+	case 0xB3: //OR E
+		cpu.OrA_r(&cpu.R.E)
+	case 0xB4: //OR H
+		cpu.OrA_r(&cpu.R.H)
+	case 0xB5: //OR L
+		cpu.OrA_r(&cpu.R.L)
+	case 0xB6: //OR (HL)
+		cpu.OrA_hl()
+	case 0xB7: //OR A
+		cpu.OrA_r(&cpu.R.A)
+	case 0xB8: //CP B
+		cpu.CPA_r(&cpu.R.B)
+	case 0xB9: //CP C
+		cpu.CPA_r(&cpu.R.C)
+	case 0xBA: //CP D
+		cpu.CPA_r(&cpu.R.D)
+	case 0xBB: //CP E
+		cpu.CPA_r(&cpu.R.E)
+	case 0xBC: //CP H
+		cpu.CPA_r(&cpu.R.H)
+	case 0xBD: //CP L
+		cpu.CPA_r(&cpu.R.L)
+	case 0xBE: //CP (HL)
+		cpu.CPA_hl()
+	case 0xBF: //CP A
+		cpu.CPA_r(&cpu.R.A)
+	case 0xC0: //RET NZ
+		cpu.Retcc(Z, false)
+	case 0xC1: //POP BC
+		cpu.Pop_nn(&cpu.R.B, &cpu.R.C)
+	case 0xC2: //JP NZ,a16
+		cpu.JPcc_nn(Z, false, operands[0], operands[1])
+	case 0xC3: //JP a16
+		cpu.JP_nn(operands[0], operands[1])
+	case 0xC4: //CALL NZ,a16
+		cpu.Callcc_nn(Z, false, operands[0], operands[1])
+	case 0xC5: //PUSH BC
+		cpu.Push_nn(&cpu.R.B, &cpu.R.C)
+	case 0xC6: //ADD A,d8
+		cpu.AddA_n(operands[0])
+	case 0xC7: //RST 0x00
+		cpu.Rst(0x00)
+	case 0xC8: //RET Z
+		cpu.Retcc(Z, true)
+	case 0xC9: //RET
+		cpu.Ret()
+	case 0xCA: //JP Z,a16
+		cpu.JPcc_nn(Z, true, operands[0], operands[1])
+	case 0xCB: //illegal
+		cpu.lockup()
+	case 0xCC: //CALL Z,a16
+		cpu.Callcc_nn(Z, true, operands[0], operands[1])
+	case 0xCD: //CALL a16
+		cpu.Call_nn(operands[0], operands[1])
+	case 0xCE: //ADC A,d8
+		cpu.AddCA_n(operands[0])
+	case 0xCF: //RST 0x08
+		cpu.Rst(0x08)
+	case 0xD0: //RET NC
+		cpu.Retcc(C, false)
+	case 0xD1: //POP DE
+		cpu.Pop_nn(&cpu.R.D, &cpu.R.E)
+	case 0xD2: //JP NC,a16
+		cpu.JPcc_nn(C, false, operands[0], operands[1])
+	case 0xD3: //illegal
+		cpu.lockup()
+	case 0xD4: //CALL NC,a16
+		cpu.Callcc_nn(C, false, operands[0], operands[1])
+	case 0xD5: //PUSH DE
+		cpu.Push_nn(&cpu.R.D, &cpu.R.E)
+	case 0xD6: //SUB d8
+		cpu.SubA_n(operands[0])
+	case 0xD7: //RST 0x10
+		cpu.Rst(0x10)
+	case 0xD8: //RET C
+		cpu.Retcc(C, true)
+	case 0xD9: //RETI
+		cpu.Ret_i()
+	case 0xDA: //JP C,a16
+		cpu.JPcc_nn(C, true, operands[0], operands[1])
+	case 0xDB: //illegal
+		cpu.lockup()
+	case 0xDC: //CALL C,a16
+		cpu.Callcc_nn(C, true, operands[0], operands[1])
+	case 0xDD: //illegal
+		cpu.lockup()
+	case 0xDE: //SBC A,d8
+		cpu.SubAC_n(operands[0])
+	case 0xDF: //RST 0x18
+		cpu.Rst(0x18)
+	case 0xE0: //LDH (a8),A
+		cpu.LDHn_r(&cpu.R.A, operands[0])
+	case 0xE1: //POP HL
+		cpu.Pop_nn(&cpu.R.H, &cpu.R.L)
+	case 0xE2: //LD (C),A
+		cpu.LDffplusc_r(&cpu.R.A)
+	case 0xE3: //illegal
+		cpu.lockup()
+	case 0xE4: //illegal
+		cpu.lockup()
+	case 0xE5: //PUSH HL
+		cpu.Push_nn(&cpu.R.H, &cpu.R.L)
+	case 0xE6: //AND d8
+		cpu.AndA_n(operands[0])
+	case 0xE7: //RST 0x20
+		cpu.Rst(0x20)
+	case 0xE8: //ADD SP,r8
+		cpu.Addsp_n(operands[0])
+	case 0xE9: //JP (HL)
+		cpu.JP_hl()
+	case 0xEA: //LD (a16),A
+		cpu.LDnn_r(&cpu.R.A, operands[0], operands[1])
+	case 0xEB: //illegal
+		cpu.lockup()
+	case 0xEC: //illegal
+		cpu.lockup()
+	case 0xED: //illegal
+		cpu.lockup()
+	case 0xEE: //XOR d8
+		cpu.XorA_n(operands[0])
+	case 0xEF: //RST 0x28
+		cpu.Rst(0x28)
+	case 0xF0: //LDH A,(a8)
+		cpu.LDHr_n(&cpu.R.A, operands[0])
+	case 0xF1: //POP AF
+		cpu.Pop_AF()
+	case 0xF2: //LD A,(C)
+		cpu.LDr_ffplusc(&cpu.R.A)
+	case 0xF3: //DI
+		cpu.DI()
+	case 0xF4: //illegal
+		cpu.lockup()
+	case 0xF5: //PUSH AF
+		cpu.Push_nn(&cpu.R.A, &cpu.R.F)
+	case 0xF6: //OR d8
+		cpu.OrA_n(operands[0])
+	case 0xF7: //RST 0x30
+		cpu.Rst(0x30)
+	case 0xF8: //LD HL,SP+r8
+		cpu.LDHLSP_n(operands[0])
+	case 0xF9: //LD SP,HL
+		cpu.LDSP_hl()
+	case 0xFA: //LD A,(a16)
+		cpu.LDr_nn(&cpu.R.A, operands[0], operands[1])
+	case 0xFB: //EI
+		cpu.EI()
+	case 0xFC: //illegal
+		cpu.lockup()
+	case 0xFD: //illegal
+		cpu.lockup()
+	case 0xFE: //CP d8
+		cpu.CPA_n(operands[0])
+	case 0xFF: //RST 0x38
+		cpu.Rst(0x38)
+	}
+}
+
+// Runs the instruction for opcode from InstructionsCB once it and its operands have been fetched
+func (cpu *GbcCPU) executeCB(opcode byte) {
+	switch opcode {
+	case 0x00: //RLC B
+		cpu.Rlc_r(&cpu.R.B)
+	case 0x01: //RLC C
+		cpu.Rlc_r(&cpu.R.C)
+	case 0x02: //RLC D
+		cpu.Rlc_r(&cpu.R.D)
+	case 0x03: //RLC E
+		cpu.Rlc_r(&cpu.R.E)
+	case 0x04: //RLC H
+		cpu.Rlc_r(&cpu.R.H)
+	case 0x05: //RLC L
+		cpu.Rlc_r(&cpu.R.L)
+	case 0x06: //RLC (HL)
+		cpu.Rlc_hl()
+	case 0x07: //RLC A
+		cpu.Rlc_r(&cpu.R.A)
+	case 0x08: //RRC B
+		cpu.Rrc_r(&cpu.R.B)
+	case 0x09: //RRC C
+		cpu.Rrc_r(&cpu.R.C)
+	case 0x0A: //RRC D
+		cpu.Rrc_r(&cpu.R.D)
+	case 0x0B: //RRC E
+		cpu.Rrc_r(&cpu.R.E)
+	case 0x0C: //RRC H
+		cpu.Rrc_r(&cpu.R.H)
+	case 0x0D: //RRC L
+		cpu.Rrc_r(&cpu.R.L)
+	case 0x0E: //RRC (HL)
+		cpu.Rrc_hl()
+	case 0x0F: //RRC A
+		cpu.Rrc_r(&cpu.R.A)
+	case 0x10: //RL B
+		cpu.Rl_r(&cpu.R.B)
+	case 0x11: //RL C
+		cpu.Rl_r(&cpu.R.C)
+	case 0x12: //RL D
+		cpu.Rl_r(&cpu.R.D)
+	case 0x13: //RL E
+		cpu.Rl_r(&cpu.R.E)
+	case 0x14: //RL H
+		cpu.Rl_r(&cpu.R.H)
+	case 0x15: //RL L
+		cpu.Rl_r(&cpu.R.L)
+	case 0x16: //RL (HL)
+		cpu.Rl_hl()
+	case 0x17: //RL A
+		cpu.Rl_r(&cpu.R.A)
+	case 0x18: //RR B
+		cpu.Rr_r(&cpu.R.B)
+	case 0x19: //RR C
+		cpu.Rr_r(&cpu.R.C)
+	case 0x1A: //RR D
+		cpu.Rr_r(&cpu.R.D)
+	case 0x1B: //RR E
+		cpu.Rr_r(&cpu.R.E)
+	case 0x1C: //RR H
+		cpu.Rr_r(&cpu.R.H)
+	case 0x1D: //RR L
+		cpu.Rr_r(&cpu.R.L)
+	case 0x1E: //RR (HL)
+		cpu.Rr_hl()
+	case 0x1F: //RR A
+		cpu.Rr_r(&cpu.R.A)
+	case 0x20: //SLA B
+		cpu.Sla_r(&cpu.R.B)
+	case 0x21: //SLA C
+		cpu.Sla_r(&cpu.R.C)
+	case 0x22: //SLA D
+		cpu.Sla_r(&cpu.R.D)
+	case 0x23: //SLA E
+		cpu.Sla_r(&cpu.R.E)
+	case 0x24: //SLA H
+		cpu.Sla_r(&cpu.R.H)
+	case 0x25: //SLA L
+		cpu.Sla_r(&cpu.R.L)
+	case 0x26: //SLA (HL)
+		cpu.Sla_hl()
+	case 0x27: //SLA A
+		cpu.Sla_r(&cpu.R.A)
+	case 0x28: //SRA B
+		cpu.Sra_r(&cpu.R.B)
+	case 0x29: //SRA C
+		cpu.Sra_r(&cpu.R.C)
+	case 0x2A: //SRA D
+		cpu.Sra_r(&cpu.R.D)
+	case 0x2B: //SRA E
+		cpu.Sra_r(&cpu.R.E)
+	case 0x2C: //SRA H
+		cpu.Sra_r(&cpu.R.H)
+	case 0x2D: //SRA L
+		cpu.Sra_r(&cpu.R.L)
+	case 0x2E: //SRA (HL)
+		cpu.Sra_hl()
+	case 0x2F: //SRA A
+		cpu.Sra_r(&cpu.R.A)
+	case 0x30: //SWAP B
+		cpu.Swap_r(&cpu.R.B)
+	case 0x31: //SWAP C
+		cpu.Swap_r(&cpu.R.C)
+	case 0x32: //SWAP D
+		cpu.Swap_r(&cpu.R.D)
+	case 0x33: //SWAP E
+		cpu.Swap_r(&cpu.R.E)
+	case 0x34: //SWAP H
+		cpu.Swap_r(&cpu.R.H)
+	case 0x35: //SWAP L
+		cpu.Swap_r(&cpu.R.L)
+	case 0x36: //SWAP (HL)
+		cpu.Swap_hl()
+	case 0x37: //SWAP A
+		cpu.Swap_r(&cpu.R.A)
+	case 0x38: //SRL B
+		cpu.Srl_r(&cpu.R.B)
+	case 0x39: //SRL C
+		cpu.Srl_r(&cpu.R.C)
+	case 0x3A: //SRL D
+		cpu.Srl_r(&cpu.R.D)
+	case 0x3B: //SRL E
+		cpu.Srl_r(&cpu.R.E)
+	case 0x3C: //SRL H
+		cpu.Srl_r(&cpu.R.H)
+	case 0x3D: //SRL L
+		cpu.Srl_r(&cpu.R.L)
+	case 0x3E: //SRL (HL)
+		cpu.Srl_hl()
+	case 0x3F: //SRL A
+		cpu.Srl_r(&cpu.R.A)
+	case 0x40: //BIT 0,B
+		cpu.Bitb_r(0x00, &cpu.R.B)
+	case 0x41: //BIT 0,C
+		cpu.Bitb_r(0x00, &cpu.R.C)
+	case 0x42: //BIT 0,D
+		cpu.Bitb_r(0x00, &cpu.R.D)
+	case 0x43: //BIT 0,E
+		cpu.Bitb_r(0x00, &cpu.R.E)
+	case 0x44: //BIT 0,H
+		cpu.Bitb_r(0x00, &cpu.R.H)
+	case 0x45: //BIT 0,L
+		cpu.Bitb_r(0x00, &cpu.R.L)
+	case 0x46: //BIT 0,(HL)
+		cpu.Bitb_hl(0x00)
+	case 0x47: //BIT 0,A
+		cpu.Bitb_r(0x00, &cpu.R.A)
+	case 0x48: //BIT 1,B
+		cpu.Bitb_r(0x01, &cpu.R.B)
+	case 0x49: //BIT 1,C
+		cpu.Bitb_r(0x01, &cpu.R.C)
+	case 0x4A: //BIT 1,D
+		cpu.Bitb_r(0x01, &cpu.R.D)
+	case 0x4B: //BIT 1,E
+		cpu.Bitb_r(0x01, &cpu.R.E)
+	case 0x4C: //BIT 1,H
+		cpu.Bitb_r(0x01, &cpu.R.H)
+	case 0x4D: //BIT 1,L
+		cpu.Bitb_r(0x01, &cpu.R.L)
+	case 0x4E: //BIT 1,(HL)
+		cpu.Bitb_hl(0x01)
+	case 0x4F: //BIT 1,A
+		cpu.Bitb_r(0x01, &cpu.R.A)
+	case 0x50: //BIT 2,B
+		cpu.Bitb_r(0x02, &cpu.R.B)
+	case 0x51: //BIT 2,C
+		cpu.Bitb_r(0x02, &cpu.R.C)
+	case 0x52: //BIT 2,D
+		cpu.Bitb_r(0x02, &cpu.R.D)
+	case 0x53: //BIT 2,E
+		cpu.Bitb_r(0x02, &cpu.R.E)
+	case 0x54: //BIT 2,H
+		cpu.Bitb_r(0x02, &cpu.R.H)
+	case 0x55: //BIT 2,L
+		cpu.Bitb_r(0x02, &cpu.R.L)
+	case 0x56: //BIT 2,(HL)
+		cpu.Bitb_hl(0x02)
+	case 0x57: //BIT 2,A
+		cpu.Bitb_r(0x02, &cpu.R.A)
+	case 0x58: //BIT 3,B
+		cpu.Bitb_r(0x03, &cpu.R.B)
+	case 0x59: //BIT 3,C
+		cpu.Bitb_r(0x03, &cpu.R.C)
+	case 0x5A: //BIT 3,D
+		cpu.Bitb_r(0x03, &cpu.R.D)
+	case 0x5B: //BIT 3,E
+		cpu.Bitb_r(0x03, &cpu.R.E)
+	case 0x5C: //BIT 3,H
+		cpu.Bitb_r(0x03, &cpu.R.H)
+	case 0x5D: //BIT 3,L
+		cpu.Bitb_r(0x03, &cpu.R.L)
+	case 0x5E: //BIT 3,(HL)
+		cpu.Bitb_hl(0x03)
+	case 0x5F: //BIT 3,A
+		cpu.Bitb_r(0x03, &cpu.R.A)
+	case 0x60: //BIT 4,B
+		cpu.Bitb_r(0x04, &cpu.R.B)
+	case 0x61: //BIT 4,C
+		cpu.Bitb_r(0x04, &cpu.R.C)
+	case 0x62: //BIT 4,D
+		cpu.Bitb_r(0x04, &cpu.R.D)
+	case 0x63: //BIT 4,E
+		cpu.Bitb_r(0x04, &cpu.R.E)
+	case 0x64: //BIT 4,H
+		cpu.Bitb_r(0x04, &cpu.R.H)
+	case 0x65: //BIT 4,L
+		cpu.Bitb_r(0x04, &cpu.R.L)
+	case 0x66: //BIT 4,(HL)
+		cpu.Bitb_hl(0x04)
+	case 0x67: //BIT 4,A
+		cpu.Bitb_r(0x04, &cpu.R.A)
+	case 0x68: //BIT 5,B
+		cpu.Bitb_r(0x05, &cpu.R.B)
+	case 0x69: //BIT 5,C
+		cpu.Bitb_r(0x05, &cpu.R.C)
+	case 0x6A: //BIT 5,D
+		cpu.Bitb_r(0x05, &cpu.R.D)
+	case 0x6B: //BIT 5,E
+		cpu.Bitb_r(0x05, &cpu.R.E)
+	case 0x6C: //BIT 5,H
+		cpu.Bitb_r(0x05, &cpu.R.H)
+	case 0x6D: //BIT 5,L
+		cpu.Bitb_r(0x05, &cpu.R.L)
+	case 0x6E: //BIT 5,(HL)
+		cpu.Bitb_hl(0x05)
+	case 0x6F: //BIT 5,A
+		cpu.Bitb_r(0x05, &cpu.R.A)
+	case 0x70: //BIT 6,B
+		cpu.Bitb_r(0x06, &cpu.R.B)
+	case 0x71: //BIT 6,C
+		cpu.Bitb_r(0x06, &cpu.R.C)
+	case 0x72: //BIT 6,D
+		cpu.Bitb_r(0x06, &cpu.R.D)
+	case 0x73: //BIT 6,E
+		cpu.Bitb_r(0x06, &cpu.R.E)
+	case 0x74: //BIT 6,H
+		cpu.Bitb_r(0x06, &cpu.R.H)
+	case 0x75: //BIT 6,L
+		cpu.Bitb_r(0x06, &cpu.R.L)
+	case 0x76: //BIT 6,(HL)
+		cpu.Bitb_hl(0x06)
+	case 0x77: //BIT 6,A
+		cpu.Bitb_r(0x06, &cpu.R.A)
+	case 0x78: //BIT 7,B
+		cpu.Bitb_r(0x07, &cpu.R.B)
+	case 0x79: //BIT 7,C
+		cpu.Bitb_r(0x07, &cpu.R.C)
+	case 0x7A: //BIT 7,D
+		cpu.Bitb_r(0x07, &cpu.R.D)
+	case 0x7B: //BIT 7,E
+		cpu.Bitb_r(0x07, &cpu.R.E)
+	case 0x7C: //BIT 7,H
+		cpu.Bitb_r(0x07, &cpu.R.H)
+	case 0x7D: //BIT 7,L
+		cpu.Bitb_r(0x07, &cpu.R.L)
+	case 0x7E: //BIT 7,(HL)
+		cpu.Bitb_hl(0x07)
+	case 0x7F: //BIT 7,A
+		cpu.Bitb_r(0x07, &cpu.R.A)
+	case 0x80: //RES 0,B
+		cpu.Resb_r(0x00, &cpu.R.B)
+	case 0x81: //RES 0,C
+		cpu.Resb_r(0x00, &cpu.R.C)
+	case 0x82: //RES 0,D
+		cpu.Resb_r(0x00, &cpu.R.D)
+	case 0x83: //RES 0,E
+		cpu.Resb_r(0x00, &cpu.R.E)
+	case 0x84: //RES 0,H
+		cpu.Resb_r(0x00, &cpu.R.H)
+	case 0x85: //RES 0,L
+		cpu.Resb_r(0x00, &cpu.R.L)
+	case 0x86: //RES 0,(HL)
+		cpu.Resb_hl(0x00)
+	case 0x87: //RES 0,A
+		cpu.Resb_r(0x00, &cpu.R.A)
+	case 0x88: //RES 1,B
+		cpu.Resb_r(0x01, &cpu.R.B)
+	case 0x89: //RES 1,C
+		cpu.Resb_r(0x01, &cpu.R.C)
+	case 0x8A: //RES 1,D
+		cpu.Resb_r(0x01, &cpu.R.D)
+	case 0x8B: //RES 1,E
+		cpu.Resb_r(0x01, &cpu.R.E)
+	case 0x8C: //RES 1,H
+		cpu.Resb_r(0x01, &cpu.R.H)
+	case 0x8D: //RES 1,L
+		cpu.Resb_r(0x01, &cpu.R.L)
+	case 0x8E: //RES 1,(HL)
+		cpu.Resb_hl(0x01)
+	case 0x8F: //RES 1,A
+		cpu.Resb_r(0x01, &cpu.R.A)
+	case 0x90: //RES 2,B
+		cpu.Resb_r(0x02, &cpu.R.B)
+	case 0x91: //RES 2,C
+		cpu.Resb_r(0x02, &cpu.R.C)
+	case 0x92: //RES 2,D
+		cpu.Resb_r(0x02, &cpu.R.D)
+	case 0x93: //RES 2,E
+		cpu.Resb_r(0x02, &cpu.R.E)
+	case 0x94: //RES 2,H
+		cpu.Resb_r(0x02, &cpu.R.H)
+	case 0x95: //RES 2,L
+		cpu.Resb_r(0x02, &cpu.R.L)
+	case 0x96: //RES 2,(HL)
+		cpu.Resb_hl(0x02)
+	case 0x97: //RES 2,A
+		cpu.Resb_r(0x02, &cpu.R.A)
+	case 0x98: //RES 3,B
+		cpu.Resb_r(0x03, &cpu.R.B)
+	case 0x99: //RES 3,C
+		cpu.Resb_r(0x03, &cpu.R.C)
+	case 0x9A: //RES 3,D
+		cpu.Resb_r(0x03, &cpu.R.D)
+	case 0x9B: //RES 3,E
+		cpu.Resb_r(0x03, &cpu.R.E)
+	case 0x9C: //RES 3,H
+		cpu.Resb_r(0x03, &cpu.R.H)
+	case 0x9D: //RES 3,L
+		cpu.Resb_r(0x03, &cpu.R.L)
+	case 0x9E: //RES 3,(HL)
+		cpu.Resb_hl(0x03)
+	case 0x9F: //RES 3,A
+		cpu.Resb_r(0x03, &cpu.R.A)
+	case 0xA0: //RES 4,B
+		cpu.Resb_r(0x04, &cpu.R.B)
+	case 0xA1: //RES 4,C
+		cpu.Resb_r(0x04, &cpu.R.C)
+	case 0xA2: //RES 4,D
+		cpu.Resb_r(0x04, &cpu.R.D)
+	case 0xA3: //RES 4,E
+		cpu.Resb_r(0x04, &cpu.R.E)
+	case 0xA4: //RES 4,H
+		cpu.Resb_r(0x04, &cpu.R.H)
+	case 0xA5: //RES 4,L
+		cpu.Resb_r(0x04, &cpu.R.L)
+	case 0xA6: //RES 4,(HL)
+		cpu.Resb_hl(0x04)
+	case 0xA7: //RES 4,A
+		cpu.Resb_r(0x04, &cpu.R.A)
+	case 0xA8: //RES 5,B
+		cpu.Resb_r(0x05, &cpu.R.B)
+	case 0xA9: //RES 5,C
+		cpu.Resb_r(0x05, &cpu.R.C)
+	case 0xAA: //RES 5,D
+		cpu.Resb_r(0x05, &cpu.R.D)
+	case 0xAB: //RES 5,E
+		cpu.Resb_r(0x05, &cpu.R.E)
+	case 0xAC: //RES 5,H
+		cpu.Resb_r(0x05, &cpu.R.H)
+	case 0xAD: //RES 5,L
+		cpu.Resb_r(0x05, &cpu.R.L)
+	case 0xAE: //RES 5,(HL)
+		cpu.Resb_hl(0x05)
+	case 0xAF: //RES 5,A
+		cpu.Resb_r(0x05, &cpu.R.A)
+	case 0xB0: //RES 6,B
+		cpu.Resb_r(0x06, &cpu.R.B)
+	case 0xB1: //RES 6,C
+		cpu.Resb_r(0x06, &cpu.R.C)
+	case 0xB2: //RES 6,D
+		cpu.Resb_r(0x06, &cpu.R.D)
+	case 0xB3: //RES 6,E
+		cpu.Resb_r(0x06, &cpu.R.E)
+	case 0xB4: //RES 6,H
+		cpu.Resb_r(0x06, &cpu.R.H)
+	case 0xB5: //RES 6,L
+		cpu.Resb_r(0x06, &cpu.R.L)
+	case 0xB6: //RES 6,(HL)
+		cpu.Resb_hl(0x06)
+	case 0xB7: //RES 6,A
+		cpu.Resb_r(0x06, &cpu.R.A)
+	case 0xB8: //RES 7,B
+		cpu.Resb_r(0x07, &cpu.R.B)
+	case 0xB9: //RES 7,C
+		cpu.Resb_r(0x07, &cpu.R.C)
+	case 0xBA: //RES 7,D
+		cpu.Resb_r(0x07, &cpu.R.D)
+	case 0xBB: //RES 7,E
+		cpu.Resb_r(0x07, &cpu.R.E)
+	case 0xBC: //RES 7,H
+		cpu.Resb_r(0x07, &cpu.R.H)
+	case 0xBD: //RES 7,L
+		cpu.Resb_r(0x07, &cpu.R.L)
+	case 0xBE: //RES 7,(HL)
+		cpu.Resb_hl(0x07)
+	case 0xBF: //RES 7,A
+		cpu.Resb_r(0x07, &cpu.R.A)
+	case 0xC0: //SET 0,B
+		cpu.Setb_r(0x00, &cpu.R.B)
+	case 0xC1: //SET 0,C
+		cpu.Setb_r(0x00, &cpu.R.C)
+	case 0xC2: //SET 0,D
+		cpu.Setb_r(0x00, &cpu.R.D)
+	case 0xC3: //SET 0,E
+		cpu.Setb_r(0x00, &cpu.R.E)
+	case 0xC4: //SET 0,H
+		cpu.Setb_r(0x00, &cpu.R.H)
+	case 0xC5: //SET 0,L
+		cpu.Setb_r(0x00, &cpu.R.L)
+	case 0xC6: //SET 0,(HL)
+		cpu.Setb_hl(0x00)
+	case 0xC7: //SET 0,A
+		cpu.Setb_r(0x00, &cpu.R.A)
+	case 0xC8: //SET 1,B
+		cpu.Setb_r(0x01, &cpu.R.B)
+	case 0xC9: //SET 1,C
+		cpu.Setb_r(0x01, &cpu.R.C)
+	case 0xCA: //SET 1,D
+		cpu.Setb_r(0x01, &cpu.R.D)
+	case 0xCB: //SET 1,E
+		cpu.Setb_r(0x01, &cpu.R.E)
+	case 0xCC: //SET 1,H
+		cpu.Setb_r(0x01, &cpu.R.H)
+	case 0xCD: //SET 1,L
+		cpu.Setb_r(0x01, &cpu.R.L)
+	case 0xCE: //SET 1,(HL)
+		cpu.Setb_hl(0x01)
+	case 0xCF: //SET 1,A
+		cpu.Setb_r(0x01, &cpu.R.A)
+	case 0xD0: //SET 2,B
+		cpu.Setb_r(0x02, &cpu.R.B)
+	case 0xD1: //SET 2,C
+		cpu.Setb_r(0x02, &cpu.R.C)
+	case 0xD2: //SET 2,D
+		cpu.Setb_r(0x02, &cpu.R.D)
+	case 0xD3: //SET 2,E
+		cpu.Setb_r(0x02, &cpu.R.E)
+	case 0xD4: //SET 2,H
+		cpu.Setb_r(0x02, &cpu.R.H)
+	case 0xD5: //SET 2,L
+		cpu.Setb_r(0x02, &cpu.R.L)
+	case 0xD6: //SET 2,(HL)
+		cpu.Setb_hl(0x02)
+	case 0xD7: //SET 2,A
+		cpu.Setb_r(0x02, &cpu.R.A)
+	case 0xD8: //SET 3,B
+		cpu.Setb_r(0x03, &cpu.R.B)
+	case 0xD9: //SET 3,C
+		cpu.Setb_r(0x03, &cpu.R.C)
+	case 0xDA: //SET 3,D
+		cpu.Setb_r(0x03, &cpu.R.D)
+	case 0xDB: //SET 3,E
+		cpu.Setb_r(0x03, &cpu.R.E)
+	case 0xDC: //SET 3,H
+		cpu.Setb_r(0x03, &cpu.R.H)
+	case 0xDD: //SET 3,L
+		cpu.Setb_r(0x03, &cpu.R.L)
+	case 0xDE: //SET 3,(HL)
+		cpu.Setb_hl(0x03)
+	case 0xDF: //SET 3,A
+		cpu.Setb_r(0x03, &cpu.R.A)
+	case 0xE0: //SET 4,B
+		cpu.Setb_r(0x04, &cpu.R.B)
+	case 0xE1: //SET 4,C
+		cpu.Setb_r(0x04, &cpu.R.C)
+	case 0xE2: //SET 4,D
+		cpu.Setb_r(0x04, &cpu.R.D)
+	case 0xE3: //SET 4,E
+		cpu.Setb_r(0x04, &cpu.R.E)
+	case 0xE4: //SET 4,H
+		cpu.Setb_r(0x04, &cpu.R.H)
+	case 0xE5: //SET 4,L
+		cpu.Setb_r(0x04, &cpu.R.L)
+	case 0xE6: //SET 4,(HL)
+		cpu.Setb_hl(0x04)
+	case 0xE7: //SET 4,A
+		cpu.Setb_r(0x04, &cpu.R.A)
+	case 0xE8: //SET 5,B
+		cpu.Setb_r(0x05, &cpu.R.B)
+	case 0xE9: //SET 5,C
+		cpu.Setb_r(0x05, &cpu.R.C)
+	case 0xEA: //SET 5,D
+		cpu.Setb_r(0x05, &cpu.R.D)
+	case 0xEB: //SET 5,E
+		cpu.Setb_r(0x05, &cpu.R.E)
+	case 0xEC: //SET 5,H
+		cpu.Setb_r(0x05, &cpu.R.H)
+	case 0xED: //SET 5,L
+		cpu.Setb_r(0x05, &cpu.R.L)
+	case 0xEE: //SET 5,(HL)
+		cpu.Setb_hl(0x05)
+	case 0xEF: //SET 5,A
+		cpu.Setb_r(0x05, &cpu.R.A)
+	case 0xF0: //SET 6,B
+		cpu.Setb_r(0x06, &cpu.R.B)
+	case 0xF1: //SET 6,C
+		cpu.Setb_r(0x06, &cpu.R.C)
+	case 0xF2: //SET 6,D
+		cpu.Setb_r(0x06, &cpu.R.D)
+	case 0xF3: //SET 6,E
+		cpu.Setb_r(0x06, &cpu.R.E)
+	case 0xF4: //SET 6,H
+		cpu.Setb_r(0x06, &cpu.R.H)
+	case 0xF5: //SET 6,L
+		cpu.Setb_r(0x06, &cpu.R.L)
+	case 0xF6: //SET 6,(HL)
+		cpu.Setb_hl(0x06)
+	case 0xF7: //SET 6,A
+		cpu.Setb_r(0x06, &cpu.R.A)
+	case 0xF8: //SET 7,B
+		cpu.Setb_r(0x07, &cpu.R.B)
+	case 0xF9: //SET 7,C
+		cpu.Setb_r(0x07, &cpu.R.C)
+	case 0xFA: //SET 7,D
+		cpu.Setb_r(0x07, &cpu.R.D)
+	case 0xFB: //SET 7,E
+		cpu.Setb_r(0x07, &cpu.R.E)
+	case 0xFC: //SET 7,H
+		cpu.Setb_r(0x07, &cpu.R.H)
+	case 0xFD: //SET 7,L
+		cpu.Setb_r(0x07, &cpu.R.L)
+	case 0xFE: //SET 7,(HL)
+		cpu.Setb_hl(0x07)
+	case 0xFF: //SET 7,A
+		cpu.Setb_r(0x07, &cpu.R.A)
+	}
+}
@@ -0,0 +1,102 @@
+package cpu
+
+import (
+	"testing"
+
+	"github.com/djhworld/gomeboycolor/timer"
+	"github.com/djhworld/gomeboycolor/types"
+)
+
+// A CPU with every register, flag and byte of memory set to something different so that an
+// instruction reading the wrong operand or register gives a different result
+func dispatchTestCPU() *GbcCPU {
+	var m *FlatMMU = new(FlatMMU)
+	for addr := range m.memory {
+		m.memory[addr] = byte(addr*7 + addr>>8)
+	}
+	var c *GbcCPU = NewCPU(m, timer.NewTimer())
+	c.PC = 0x1234
+	c.SP = 0xD000
+	c.R = Registers{A: 0x81, B: 0x12, C: 0xFE, D: 0x47, E: 0x0F, H: 0xC1, L: 0x23, F: 0xB0}
+	return c
+}
+
+// The generated switch must do exactly what the Execute functions in the instruction tables do
+func TestDispatchMatchesInstructionTables(t *testing.T) {
+	tables := []struct {
+		name          string
+		instructions  []*Instruction
+		operandsSizes [256]int
+		execute       func(c *GbcCPU, opcode byte, operands [2]byte)
+	}{
+		{"Instructions", Instructions, operandsSizes, (*GbcCPU).execute},
+		{"InstructionsCB", InstructionsCB, [256]int{}, func(c *GbcCPU, opcode byte, operands [2]byte) {
+			c.executeCB(opcode)
+		}},
+	}
+
+	for _, table := range tables {
+		for opcode := 0; opcode <= 0xFF; opcode++ {
+			var instr *Instruction = table.instructions[opcode]
+
+			want := dispatchTestCPU()
+			want.opcode, want.opcodeAddress = byte(opcode), want.PC
+			var operands [2]byte
+			for i := 0; i < instr.OperandsSize; i++ {
+				operands[i] = want.ReadByte(want.PC + types.Word(i+1))
+			}
+			instr.Execute(want, operands)
+
+			got := dispatchTestCPU()
+			got.opcode, got.opcodeAddress = byte(opcode), got.PC
+			var length int = table.operandsSizes[opcode] + 1
+			for i := 0; i < table.operandsSizes[opcode]; i++ {
+				operands[i] = got.ReadByte(got.PC + types.Word(i+1))
+			}
+			table.execute(got, byte(opcode), operands)
+
+			if length != instr.OperandsSize+1 {
+				t.Errorf("%s[0x%02X] (%s) has length %d, expected %d", table.name, opcode, instr.Description, length, instr.OperandsSize+1)
+			}
+
+			var wantState, gotState CPUFrame = *want.GetFrame(), *got.GetFrame()
+			wantState.CurrentInstruction, gotState.CurrentInstruction = nil, nil
+			if wantState != gotState || want.Locked != got.Locked {
+				t.Errorf("%s[0x%02X] (%s) gave %+v, expected %+v", table.name, opcode, instr.Description, gotState, wantState)
+			}
+			if want.mmu.(*FlatMMU).memory != got.mmu.(*FlatMMU).memory {
+				t.Errorf("%s[0x%02X] (%s) wrote to memory differently", table.name, opcode, instr.Description)
+			}
+		}
+	}
+}
+
+func BenchmarkStep(b *testing.B) {
+	var m *FlatMMU = new(FlatMMU)
+	copy(m.memory[0x0100:], []byte{
+		0x21, 0x00, 0xC0, //LD HL,0xC000
+		0x06, 0x00, //LD B,0x00
+		0x78,       //LD A,B
+		0xEE, 0x5A, //XOR 0x5A
+		0x22,             //LD (HL+),A
+		0xCD, 0x80, 0x01, //CALL 0x0180
+		0x05,       //DEC B
+		0x20, 0xF6, //JR NZ,0x0105
+		0x18, 0xEF, //JR 0x0100
+	})
+	copy(m.memory[0x0180:], []byte{
+		0xCB, 0x37, //SWAP A
+		0xC9, //RET
+	})
+
+	var c *GbcCPU = NewCPU(m, timer.NewTimer())
+	c.PC = 0x0100
+	c.SP = 0xFFFE
+	c.InterruptsEnabled = false
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.Step()
+	}
+}
@@ -0,0 +1,152 @@
+//go:build ignore
+
+//Generates dispatch.go from the instruction tables in instructions.go.
+//
+//Every opcode becomes a case of a switch that runs the body of its Execute function inline, so
+//Step doesn't need to look anything up or make an indirect call. Step reads the operands itself
+//using the size of each instruction, which is generated alongside. The tables stay the one place
+//instructions are defined, run `go generate` after changing them
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"io/ioutil"
+	"log"
+	"strconv"
+	"strings"
+)
+
+const OUTPUT = "dispatch.go"
+
+type instruction struct {
+	opcode       int
+	description  string
+	operandsSize int
+	body         []string
+}
+
+func main() {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "instructions.go", nil, 0)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var empty instruction
+	tables := make(map[string][]instruction)
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.VAR {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			value := spec.(*ast.ValueSpec)
+			name := value.Names[0].Name
+			switch name {
+			case "EMPTY_INSTRUCTION":
+				empty = parseInstruction(fset, value.Values[0].(*ast.UnaryExpr).X.(*ast.CompositeLit))
+			case "Instructions", "InstructionsCB":
+				var table []instruction
+				for i, elt := range value.Values[0].(*ast.CompositeLit).Elts {
+					if id, ok := elt.(*ast.Ident); ok && id.Name == "EMPTY_INSTRUCTION" {
+						table = append(table, instruction{opcode: i, description: "illegal", body: empty.body})
+						continue
+					}
+					instr := parseInstruction(fset, elt.(*ast.UnaryExpr).X.(*ast.CompositeLit))
+					if instr.opcode != i {
+						log.Fatalf("%s[0x%02X] has opcode 0x%02X", name, i, instr.opcode)
+					}
+					table = append(table, instr)
+				}
+				if len(table) != 256 {
+					log.Fatalf("%s has %d instructions", name, len(table))
+				}
+				tables[name] = table
+			}
+		}
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintln(&b, "// Code generated by gen_dispatch.go from instructions.go; DO NOT EDIT.")
+	fmt.Fprintln(&b)
+	fmt.Fprintln(&b, "package cpu")
+	fmt.Fprintln(&b)
+	writeOperandsSizes(&b, tables["Instructions"])
+	writeSwitch(&b, "execute(opcode byte, operands [2]byte)", "Instructions", tables["Instructions"])
+	writeSwitch(&b, "executeCB(opcode byte)", "InstructionsCB", tables["InstructionsCB"])
+
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := ioutil.WriteFile(OUTPUT, src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func writeOperandsSizes(b *bytes.Buffer, instructions []instruction) {
+	fmt.Fprintln(b)
+	fmt.Fprintln(b, "//Number of operand bytes that follow each opcode in Instructions, CB prefixed instructions")
+	fmt.Fprintln(b, "//don't take any")
+	fmt.Fprintln(b, "var operandsSizes [256]int = [256]int{")
+	for i, instr := range instructions {
+		fmt.Fprintf(b, "%d,", instr.operandsSize)
+		if i%16 == 15 {
+			fmt.Fprintf(b, " //0x%02X-0x%02X\n", i-15, i)
+		}
+	}
+	fmt.Fprintln(b, "}")
+}
+
+func writeSwitch(b *bytes.Buffer, function string, table string, instructions []instruction) {
+	fmt.Fprintln(b)
+	fmt.Fprintf(b, "//Runs the instruction for opcode from %s once it and its operands have been fetched\n", table)
+	fmt.Fprintf(b, "func (cpu *GbcCPU) %s {\n", function)
+	fmt.Fprintln(b, "switch opcode {")
+	for _, instr := range instructions {
+		fmt.Fprintf(b, "case 0x%02X: //%s\n", instr.opcode, instr.description)
+		for _, stmt := range instr.body {
+			fmt.Fprintln(b, stmt)
+		}
+	}
+	fmt.Fprintln(b, "}")
+	fmt.Fprintln(b, "}")
+}
+
+//reads an &Instruction{Opcode, Description, OperandsSize, Cycles, Execute} literal
+func parseInstruction(fset *token.FileSet, lit *ast.CompositeLit) instruction {
+	var instr instruction
+	opcode, err := strconv.ParseInt(lit.Elts[0].(*ast.BasicLit).Value, 0, 32)
+	if err != nil {
+		log.Fatal(err)
+	}
+	instr.opcode = int(opcode)
+
+	instr.description, err = strconv.Unquote(lit.Elts[1].(*ast.BasicLit).Value)
+	if err != nil {
+		log.Fatal(err)
+	}
+	instr.description = strings.Join(strings.Fields(instr.description), " ")
+
+	size, err := strconv.Atoi(lit.Elts[2].(*ast.BasicLit).Value)
+	if err != nil {
+		log.Fatal(err)
+	}
+	instr.operandsSize = size
+
+	execute := lit.Elts[4].(*ast.FuncLit)
+	for _, stmt := range execute.Body.List {
+		var s bytes.Buffer
+		if err := printer.Fprint(&s, fset, stmt); err != nil {
+			log.Fatal(err)
+		}
+		instr.body = append(instr.body, s.String())
+	}
+	return instr
+}
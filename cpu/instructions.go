@@ -2,539 +2,545 @@ package cpu
 
 import (
 	"fmt"
-	"log"
 
 	"github.com/djhworld/gomeboycolor/utils"
 )
 
+//go:generate go run gen_dispatch.go
+
+//The instruction set, Step doesn't use these tables directly but runs the switch in dispatch.go
+//that is generated from them
 type Instruction struct {
 	Opcode       byte
 	Description  string
 	OperandsSize int
 	Cycles       int
-	Execute      func(cpu *GbcCPU)
+
+	//runs the instruction once the operand bytes that follow the opcode have been read
+	Execute func(cpu *GbcCPU, operands [2]byte)
 }
 
-var EMPTY_INSTRUCTION *Instruction = &Instruction{0xFF, "EMPTY", 0, 1, func(cpu *GbcCPU) {
-	log.Println("WARNING: Calling unknown/invalid opcode")
+//Undefined opcodes lock the CPU up
+var EMPTY_INSTRUCTION *Instruction = &Instruction{0xFF, "EMPTY", 0, 1, func(cpu *GbcCPU, operands [2]byte) {
+	cpu.lockup()
 }}
 
 var InstructionsCB []*Instruction = []*Instruction{
-	&Instruction{0x0, "RLC B", 0, 2, func(cpu *GbcCPU) { cpu.Rlc_r(&cpu.R.B) }},
-	&Instruction{0x1, "RLC C", 0, 2, func(cpu *GbcCPU) { cpu.Rlc_r(&cpu.R.C) }},
-	&Instruction{0x2, "RLC D", 0, 2, func(cpu *GbcCPU) { cpu.Rlc_r(&cpu.R.D) }},
-	&Instruction{0x3, "RLC E", 0, 2, func(cpu *GbcCPU) { cpu.Rlc_r(&cpu.R.E) }},
-	&Instruction{0x4, "RLC H", 0, 2, func(cpu *GbcCPU) { cpu.Rlc_r(&cpu.R.H) }},
-	&Instruction{0x5, "RLC L", 0, 2, func(cpu *GbcCPU) { cpu.Rlc_r(&cpu.R.L) }},
-	&Instruction{0x6, "RLC (HL)", 0, 4, func(cpu *GbcCPU) { cpu.Rlc_hl() }},
-	&Instruction{0x7, "RLC A", 0, 2, func(cpu *GbcCPU) { cpu.Rlc_r(&cpu.R.A) }},
-	&Instruction{0x8, "RRC B", 0, 2, func(cpu *GbcCPU) { cpu.Rrc_r(&cpu.R.B) }},
-	&Instruction{0x9, "RRC C", 0, 2, func(cpu *GbcCPU) { cpu.Rrc_r(&cpu.R.C) }},
-	&Instruction{0xA, "RRC D", 0, 2, func(cpu *GbcCPU) { cpu.Rrc_r(&cpu.R.D) }},
-	&Instruction{0xB, "RRC E", 0, 2, func(cpu *GbcCPU) { cpu.Rrc_r(&cpu.R.E) }},
-	&Instruction{0xC, "RRC H", 0, 2, func(cpu *GbcCPU) { cpu.Rrc_r(&cpu.R.H) }},
-	&Instruction{0xD, "RRC L", 0, 2, func(cpu *GbcCPU) { cpu.Rrc_r(&cpu.R.L) }},
-	&Instruction{0xE, "RRC (HL)", 0, 4, func(cpu *GbcCPU) { cpu.Rrc_hl() }},
-	&Instruction{0xF, "RRC A", 0, 2, func(cpu *GbcCPU) { cpu.Rrc_r(&cpu.R.A) }},
-	&Instruction{0x10, "RL B", 0, 2, func(cpu *GbcCPU) { cpu.Rl_r(&cpu.R.B) }},
-	&Instruction{0x11, "RL C", 0, 2, func(cpu *GbcCPU) { cpu.Rl_r(&cpu.R.C) }},
-	&Instruction{0x12, "RL D", 0, 2, func(cpu *GbcCPU) { cpu.Rl_r(&cpu.R.D) }},
-	&Instruction{0x13, "RL E", 0, 2, func(cpu *GbcCPU) { cpu.Rl_r(&cpu.R.E) }},
-	&Instruction{0x14, "RL H", 0, 2, func(cpu *GbcCPU) { cpu.Rl_r(&cpu.R.H) }},
-	&Instruction{0x15, "RL L", 0, 2, func(cpu *GbcCPU) { cpu.Rl_r(&cpu.R.L) }},
-	&Instruction{0x16, "RL (HL)", 0, 4, func(cpu *GbcCPU) { cpu.Rl_hl() }},
-	&Instruction{0x17, "RL A", 0, 2, func(cpu *GbcCPU) { cpu.Rl_r(&cpu.R.A) }},
-	&Instruction{0x18, "RR B", 0, 2, func(cpu *GbcCPU) { cpu.Rr_r(&cpu.R.B) }},
-	&Instruction{0x19, "RR C", 0, 2, func(cpu *GbcCPU) { cpu.Rr_r(&cpu.R.C) }},
-	&Instruction{0x1A, "RR D", 0, 2, func(cpu *GbcCPU) { cpu.Rr_r(&cpu.R.D) }},
-	&Instruction{0x1B, "RR E", 0, 2, func(cpu *GbcCPU) { cpu.Rr_r(&cpu.R.E) }},
-	&Instruction{0x1C, "RR H", 0, 2, func(cpu *GbcCPU) { cpu.Rr_r(&cpu.R.H) }},
-	&Instruction{0x1D, "RR L", 0, 2, func(cpu *GbcCPU) { cpu.Rr_r(&cpu.R.L) }},
-	&Instruction{0x1E, "RR (HL)", 0, 4, func(cpu *GbcCPU) { cpu.Rr_hl() }},
-	&Instruction{0x1F, "RR A", 0, 2, func(cpu *GbcCPU) { cpu.Rr_r(&cpu.R.A) }},
-	&Instruction{0x20, "SLA B", 0, 2, func(cpu *GbcCPU) { cpu.Sla_r(&cpu.R.B) }},
-	&Instruction{0x21, "SLA C", 0, 2, func(cpu *GbcCPU) { cpu.Sla_r(&cpu.R.C) }},
-	&Instruction{0x22, "SLA D", 0, 2, func(cpu *GbcCPU) { cpu.Sla_r(&cpu.R.D) }},
-	&Instruction{0x23, "SLA E", 0, 2, func(cpu *GbcCPU) { cpu.Sla_r(&cpu.R.E) }},
-	&Instruction{0x24, "SLA H", 0, 2, func(cpu *GbcCPU) { cpu.Sla_r(&cpu.R.H) }},
-	&Instruction{0x25, "SLA L", 0, 2, func(cpu *GbcCPU) { cpu.Sla_r(&cpu.R.L) }},
-	&Instruction{0x26, "SLA (HL)", 0, 4, func(cpu *GbcCPU) { cpu.Sla_hl() }},
-	&Instruction{0x27, "SLA A", 0, 2, func(cpu *GbcCPU) { cpu.Sla_r(&cpu.R.A) }},
-	&Instruction{0x28, "SRA B", 0, 2, func(cpu *GbcCPU) { cpu.Sra_r(&cpu.R.B) }},
-	&Instruction{0x29, "SRA C", 0, 2, func(cpu *GbcCPU) { cpu.Sra_r(&cpu.R.C) }},
-	&Instruction{0x2A, "SRA D", 0, 2, func(cpu *GbcCPU) { cpu.Sra_r(&cpu.R.D) }},
-	&Instruction{0x2B, "SRA E", 0, 2, func(cpu *GbcCPU) { cpu.Sra_r(&cpu.R.E) }},
-	&Instruction{0x2C, "SRA H", 0, 2, func(cpu *GbcCPU) { cpu.Sra_r(&cpu.R.H) }},
-	&Instruction{0x2D, "SRA L", 0, 2, func(cpu *GbcCPU) { cpu.Sra_r(&cpu.R.L) }},
-	&Instruction{0x2E, "SRA (HL)", 0, 4, func(cpu *GbcCPU) { cpu.Sra_hl() }},
-	&Instruction{0x2F, "SRA A", 0, 2, func(cpu *GbcCPU) { cpu.Sra_r(&cpu.R.A) }},
-	&Instruction{0x30, "SWAP B", 0, 2, func(cpu *GbcCPU) { cpu.Swap_r(&cpu.R.B) }},
-	&Instruction{0x31, "SWAP C", 0, 2, func(cpu *GbcCPU) { cpu.Swap_r(&cpu.R.C) }},
-	&Instruction{0x32, "SWAP D", 0, 2, func(cpu *GbcCPU) { cpu.Swap_r(&cpu.R.D) }},
-	&Instruction{0x33, "SWAP E", 0, 2, func(cpu *GbcCPU) { cpu.Swap_r(&cpu.R.E) }},
-	&Instruction{0x34, "SWAP H", 0, 2, func(cpu *GbcCPU) { cpu.Swap_r(&cpu.R.H) }},
-	&Instruction{0x35, "SWAP L", 0, 2, func(cpu *GbcCPU) { cpu.Swap_r(&cpu.R.L) }},
-	&Instruction{0x36, "SWAP (HL)", 0, 4, func(cpu *GbcCPU) { cpu.Swap_hl() }},
-	&Instruction{0x37, "SWAP A", 0, 2, func(cpu *GbcCPU) { cpu.Swap_r(&cpu.R.A) }},
-	&Instruction{0x38, "SRL B", 0, 2, func(cpu *GbcCPU) { cpu.Srl_r(&cpu.R.B) }},
-	&Instruction{0x39, "SRL C", 0, 2, func(cpu *GbcCPU) { cpu.Srl_r(&cpu.R.C) }},
-	&Instruction{0x3A, "SRL D", 0, 2, func(cpu *GbcCPU) { cpu.Srl_r(&cpu.R.D) }},
-	&Instruction{0x3B, "SRL E", 0, 2, func(cpu *GbcCPU) { cpu.Srl_r(&cpu.R.E) }},
-	&Instruction{0x3C, "SRL H", 0, 2, func(cpu *GbcCPU) { cpu.Srl_r(&cpu.R.H) }},
-	&Instruction{0x3D, "SRL L", 0, 2, func(cpu *GbcCPU) { cpu.Srl_r(&cpu.R.L) }},
-	&Instruction{0x3E, "SRL (HL)", 0, 4, func(cpu *GbcCPU) { cpu.Srl_hl() }},
-	&Instruction{0x3F, "SRL A", 0, 2, func(cpu *GbcCPU) { cpu.Srl_r(&cpu.R.A) }},
-	&Instruction{0x40, "BIT 0,B", 0, 2, func(cpu *GbcCPU) { cpu.Bitb_r(0x00, &cpu.R.B) }},
-	&Instruction{0x41, "BIT 0,C", 0, 2, func(cpu *GbcCPU) { cpu.Bitb_r(0x00, &cpu.R.C) }},
-	&Instruction{0x42, "BIT 0,D", 0, 2, func(cpu *GbcCPU) { cpu.Bitb_r(0x00, &cpu.R.D) }},
-	&Instruction{0x43, "BIT 0,E", 0, 2, func(cpu *GbcCPU) { cpu.Bitb_r(0x00, &cpu.R.E) }},
-	&Instruction{0x44, "BIT 0,H", 0, 2, func(cpu *GbcCPU) { cpu.Bitb_r(0x00, &cpu.R.H) }},
-	&Instruction{0x45, "BIT 0,L", 0, 2, func(cpu *GbcCPU) { cpu.Bitb_r(0x00, &cpu.R.L) }},
-	&Instruction{0x46, "BIT 0,(HL)", 0, 3, func(cpu *GbcCPU) { cpu.Bitb_hl(0x00) }},
-	&Instruction{0x47, "BIT 0,A", 0, 2, func(cpu *GbcCPU) { cpu.Bitb_r(0x00, &cpu.R.A) }},
-	&Instruction{0x48, "BIT 1,B", 0, 2, func(cpu *GbcCPU) { cpu.Bitb_r(0x01, &cpu.R.B) }},
-	&Instruction{0x49, "BIT 1,C", 0, 2, func(cpu *GbcCPU) { cpu.Bitb_r(0x01, &cpu.R.C) }},
-	&Instruction{0x4A, "BIT 1,D", 0, 2, func(cpu *GbcCPU) { cpu.Bitb_r(0x01, &cpu.R.D) }},
-	&Instruction{0x4B, "BIT 1,E", 0, 2, func(cpu *GbcCPU) { cpu.Bitb_r(0x01, &cpu.R.E) }},
-	&Instruction{0x4C, "BIT 1,H", 0, 2, func(cpu *GbcCPU) { cpu.Bitb_r(0x01, &cpu.R.H) }},
-	&Instruction{0x4D, "BIT 1,L", 0, 2, func(cpu *GbcCPU) { cpu.Bitb_r(0x01, &cpu.R.L) }},
-	&Instruction{0x4E, "BIT 1,(HL)", 0, 3, func(cpu *GbcCPU) { cpu.Bitb_hl(0x01) }},
-	&Instruction{0x4F, "BIT 1,A", 0, 2, func(cpu *GbcCPU) { cpu.Bitb_r(0x01, &cpu.R.A) }},
-	&Instruction{0x50, "BIT 2,B", 0, 2, func(cpu *GbcCPU) { cpu.Bitb_r(0x02, &cpu.R.B) }},
-	&Instruction{0x51, "BIT 2,C", 0, 2, func(cpu *GbcCPU) { cpu.Bitb_r(0x02, &cpu.R.C) }},
-	&Instruction{0x52, "BIT 2,D", 0, 2, func(cpu *GbcCPU) { cpu.Bitb_r(0x02, &cpu.R.D) }},
-	&Instruction{0x53, "BIT 2,E", 0, 2, func(cpu *GbcCPU) { cpu.Bitb_r(0x02, &cpu.R.E) }},
-	&Instruction{0x54, "BIT 2,H", 0, 2, func(cpu *GbcCPU) { cpu.Bitb_r(0x02, &cpu.R.H) }},
-	&Instruction{0x55, "BIT 2,L", 0, 2, func(cpu *GbcCPU) { cpu.Bitb_r(0x02, &cpu.R.L) }},
-	&Instruction{0x56, "BIT 2,(HL)", 0, 3, func(cpu *GbcCPU) { cpu.Bitb_hl(0x02) }},
-	&Instruction{0x57, "BIT 2,A", 0, 2, func(cpu *GbcCPU) { cpu.Bitb_r(0x02, &cpu.R.A) }},
-	&Instruction{0x58, "BIT 3,B", 0, 2, func(cpu *GbcCPU) { cpu.Bitb_r(0x03, &cpu.R.B) }},
-	&Instruction{0x59, "BIT 3,C", 0, 2, func(cpu *GbcCPU) { cpu.Bitb_r(0x03, &cpu.R.C) }},
-	&Instruction{0x5A, "BIT 3,D", 0, 2, func(cpu *GbcCPU) { cpu.Bitb_r(0x03, &cpu.R.D) }},
-	&Instruction{0x5B, "BIT 3,E", 0, 2, func(cpu *GbcCPU) { cpu.Bitb_r(0x03, &cpu.R.E) }},
-	&Instruction{0x5C, "BIT 3,H", 0, 2, func(cpu *GbcCPU) { cpu.Bitb_r(0x03, &cpu.R.H) }},
-	&Instruction{0x5D, "BIT 3,L", 0, 2, func(cpu *GbcCPU) { cpu.Bitb_r(0x03, &cpu.R.L) }},
-	&Instruction{0x5E, "BIT 3,(HL)", 0, 3, func(cpu *GbcCPU) { cpu.Bitb_hl(0x03) }},
-	&Instruction{0x5F, "BIT 3,A", 0, 2, func(cpu *GbcCPU) { cpu.Bitb_r(0x03, &cpu.R.A) }},
-	&Instruction{0x60, "BIT 4,B", 0, 2, func(cpu *GbcCPU) { cpu.Bitb_r(0x04, &cpu.R.B) }},
-	&Instruction{0x61, "BIT 4,C", 0, 2, func(cpu *GbcCPU) { cpu.Bitb_r(0x04, &cpu.R.C) }},
-	&Instruction{0x62, "BIT 4,D", 0, 2, func(cpu *GbcCPU) { cpu.Bitb_r(0x04, &cpu.R.D) }},
-	&Instruction{0x63, "BIT 4,E", 0, 2, func(cpu *GbcCPU) { cpu.Bitb_r(0x04, &cpu.R.E) }},
-	&Instruction{0x64, "BIT 4,H", 0, 2, func(cpu *GbcCPU) { cpu.Bitb_r(0x04, &cpu.R.H) }},
-	&Instruction{0x65, "BIT 4,L", 0, 2, func(cpu *GbcCPU) { cpu.Bitb_r(0x04, &cpu.R.L) }},
-	&Instruction{0x66, "BIT 4,(HL)", 0, 3, func(cpu *GbcCPU) { cpu.Bitb_hl(0x04) }},
-	&Instruction{0x67, "BIT 4,A", 0, 2, func(cpu *GbcCPU) { cpu.Bitb_r(0x04, &cpu.R.A) }},
-	&Instruction{0x68, "BIT 5,B", 0, 2, func(cpu *GbcCPU) { cpu.Bitb_r(0x05, &cpu.R.B) }},
-	&Instruction{0x69, "BIT 5,C", 0, 2, func(cpu *GbcCPU) { cpu.Bitb_r(0x05, &cpu.R.C) }},
-	&Instruction{0x6A, "BIT 5,D", 0, 2, func(cpu *GbcCPU) { cpu.Bitb_r(0x05, &cpu.R.D) }},
-	&Instruction{0x6B, "BIT 5,E", 0, 2, func(cpu *GbcCPU) { cpu.Bitb_r(0x05, &cpu.R.E) }},
-	&Instruction{0x6C, "BIT 5,H", 0, 2, func(cpu *GbcCPU) { cpu.Bitb_r(0x05, &cpu.R.H) }},
-	&Instruction{0x6D, "BIT 5,L", 0, 2, func(cpu *GbcCPU) { cpu.Bitb_r(0x05, &cpu.R.L) }},
-	&Instruction{0x6E, "BIT 5,(HL)", 0, 3, func(cpu *GbcCPU) { cpu.Bitb_hl(0x05) }},
-	&Instruction{0x6F, "BIT 5,A", 0, 2, func(cpu *GbcCPU) { cpu.Bitb_r(0x05, &cpu.R.A) }},
-	&Instruction{0x70, "BIT 6,B", 0, 2, func(cpu *GbcCPU) { cpu.Bitb_r(0x06, &cpu.R.B) }},
-	&Instruction{0x71, "BIT 6,C", 0, 2, func(cpu *GbcCPU) { cpu.Bitb_r(0x06, &cpu.R.C) }},
-	&Instruction{0x72, "BIT 6,D", 0, 2, func(cpu *GbcCPU) { cpu.Bitb_r(0x06, &cpu.R.D) }},
-	&Instruction{0x73, "BIT 6,E", 0, 2, func(cpu *GbcCPU) { cpu.Bitb_r(0x06, &cpu.R.E) }},
-	&Instruction{0x74, "BIT 6,H", 0, 2, func(cpu *GbcCPU) { cpu.Bitb_r(0x06, &cpu.R.H) }},
-	&Instruction{0x75, "BIT 6,L", 0, 2, func(cpu *GbcCPU) { cpu.Bitb_r(0x06, &cpu.R.L) }},
-	&Instruction{0x76, "BIT 6,(HL)", 0, 3, func(cpu *GbcCPU) { cpu.Bitb_hl(0x06) }},
-	&Instruction{0x77, "BIT 6,A", 0, 2, func(cpu *GbcCPU) { cpu.Bitb_r(0x06, &cpu.R.A) }},
-	&Instruction{0x78, "BIT 7,B", 0, 2, func(cpu *GbcCPU) { cpu.Bitb_r(0x07, &cpu.R.B) }},
-	&Instruction{0x79, "BIT 7,C", 0, 2, func(cpu *GbcCPU) { cpu.Bitb_r(0x07, &cpu.R.C) }},
-	&Instruction{0x7A, "BIT 7,D", 0, 2, func(cpu *GbcCPU) { cpu.Bitb_r(0x07, &cpu.R.D) }},
-	&Instruction{0x7B, "BIT 7,E", 0, 2, func(cpu *GbcCPU) { cpu.Bitb_r(0x07, &cpu.R.E) }},
-	&Instruction{0x7C, "BIT 7,H", 0, 2, func(cpu *GbcCPU) { cpu.Bitb_r(0x07, &cpu.R.H) }},
-	&Instruction{0x7D, "BIT 7,L", 0, 2, func(cpu *GbcCPU) { cpu.Bitb_r(0x07, &cpu.R.L) }},
-	&Instruction{0x7E, "BIT 7,(HL)", 0, 3, func(cpu *GbcCPU) { cpu.Bitb_hl(0x07) }},
-	&Instruction{0x7F, "BIT 7,A", 0, 2, func(cpu *GbcCPU) { cpu.Bitb_r(0x07, &cpu.R.A) }},
-	&Instruction{0x80, "RES 0,B", 0, 2, func(cpu *GbcCPU) { cpu.Resb_r(0x00, &cpu.R.B) }},
-	&Instruction{0x81, "RES 0,C", 0, 2, func(cpu *GbcCPU) { cpu.Resb_r(0x00, &cpu.R.C) }},
-	&Instruction{0x82, "RES 0,D", 0, 2, func(cpu *GbcCPU) { cpu.Resb_r(0x00, &cpu.R.D) }},
-	&Instruction{0x83, "RES 0,E", 0, 2, func(cpu *GbcCPU) { cpu.Resb_r(0x00, &cpu.R.E) }},
-	&Instruction{0x84, "RES 0,H", 0, 2, func(cpu *GbcCPU) { cpu.Resb_r(0x00, &cpu.R.H) }},
-	&Instruction{0x85, "RES 0,L", 0, 2, func(cpu *GbcCPU) { cpu.Resb_r(0x00, &cpu.R.L) }},
-	&Instruction{0x86, "RES 0,(HL)", 0, 4, func(cpu *GbcCPU) { cpu.Resb_hl(0x00) }},
-	&Instruction{0x87, "RES 0,A", 0, 2, func(cpu *GbcCPU) { cpu.Resb_r(0x00, &cpu.R.A) }},
-	&Instruction{0x88, "RES 1,B", 0, 2, func(cpu *GbcCPU) { cpu.Resb_r(0x01, &cpu.R.B) }},
-	&Instruction{0x89, "RES 1,C", 0, 2, func(cpu *GbcCPU) { cpu.Resb_r(0x01, &cpu.R.C) }},
-	&Instruction{0x8A, "RES 1,D", 0, 2, func(cpu *GbcCPU) { cpu.Resb_r(0x01, &cpu.R.D) }},
-	&Instruction{0x8B, "RES 1,E", 0, 2, func(cpu *GbcCPU) { cpu.Resb_r(0x01, &cpu.R.E) }},
-	&Instruction{0x8C, "RES 1,H", 0, 2, func(cpu *GbcCPU) { cpu.Resb_r(0x01, &cpu.R.H) }},
-	&Instruction{0x8D, "RES 1,L", 0, 2, func(cpu *GbcCPU) { cpu.Resb_r(0x01, &cpu.R.L) }},
-	&Instruction{0x8E, "RES 1,(HL)", 0, 4, func(cpu *GbcCPU) { cpu.Resb_hl(0x01) }},
-	&Instruction{0x8F, "RES 1,A", 0, 2, func(cpu *GbcCPU) { cpu.Resb_r(0x01, &cpu.R.A) }},
-	&Instruction{0x90, "RES 2,B", 0, 2, func(cpu *GbcCPU) { cpu.Resb_r(0x02, &cpu.R.B) }},
-	&Instruction{0x91, "RES 2,C", 0, 2, func(cpu *GbcCPU) { cpu.Resb_r(0x02, &cpu.R.C) }},
-	&Instruction{0x92, "RES 2,D", 0, 2, func(cpu *GbcCPU) { cpu.Resb_r(0x02, &cpu.R.D) }},
-	&Instruction{0x93, "RES 2,E", 0, 2, func(cpu *GbcCPU) { cpu.Resb_r(0x02, &cpu.R.E) }},
-	&Instruction{0x94, "RES 2,H", 0, 2, func(cpu *GbcCPU) { cpu.Resb_r(0x02, &cpu.R.H) }},
-	&Instruction{0x95, "RES 2,L", 0, 2, func(cpu *GbcCPU) { cpu.Resb_r(0x02, &cpu.R.L) }},
-	&Instruction{0x96, "RES 2,(HL)", 0, 4, func(cpu *GbcCPU) { cpu.Resb_hl(0x02) }},
-	&Instruction{0x97, "RES 2,A", 0, 2, func(cpu *GbcCPU) { cpu.Resb_r(0x02, &cpu.R.A) }},
-	&Instruction{0x98, "RES 3,B", 0, 2, func(cpu *GbcCPU) { cpu.Resb_r(0x03, &cpu.R.B) }},
-	&Instruction{0x99, "RES 3,C", 0, 2, func(cpu *GbcCPU) { cpu.Resb_r(0x03, &cpu.R.C) }},
-	&Instruction{0x9A, "RES 3,D", 0, 2, func(cpu *GbcCPU) { cpu.Resb_r(0x03, &cpu.R.D) }},
-	&Instruction{0x9B, "RES 3,E", 0, 2, func(cpu *GbcCPU) { cpu.Resb_r(0x03, &cpu.R.E) }},
-	&Instruction{0x9C, "RES 3,H", 0, 2, func(cpu *GbcCPU) { cpu.Resb_r(0x03, &cpu.R.H) }},
-	&Instruction{0x9D, "RES 3,L", 0, 2, func(cpu *GbcCPU) { cpu.Resb_r(0x03, &cpu.R.L) }},
-	&Instruction{0x9E, "RES 3,(HL)", 0, 4, func(cpu *GbcCPU) { cpu.Resb_hl(0x03) }},
-	&Instruction{0x9F, "RES 3,A", 0, 2, func(cpu *GbcCPU) { cpu.Resb_r(0x03, &cpu.R.A) }},
-	&Instruction{0xA0, "RES 4,B", 0, 2, func(cpu *GbcCPU) { cpu.Resb_r(0x04, &cpu.R.B) }},
-	&Instruction{0xA1, "RES 4,C", 0, 2, func(cpu *GbcCPU) { cpu.Resb_r(0x04, &cpu.R.C) }},
-	&Instruction{0xA2, "RES 4,D", 0, 2, func(cpu *GbcCPU) { cpu.Resb_r(0x04, &cpu.R.D) }},
-	&Instruction{0xA3, "RES 4,E", 0, 2, func(cpu *GbcCPU) { cpu.Resb_r(0x04, &cpu.R.E) }},
-	&Instruction{0xA4, "RES 4,H", 0, 2, func(cpu *GbcCPU) { cpu.Resb_r(0x04, &cpu.R.H) }},
-	&Instruction{0xA5, "RES 4,L", 0, 2, func(cpu *GbcCPU) { cpu.Resb_r(0x04, &cpu.R.L) }},
-	&Instruction{0xA6, "RES 4,(HL)", 0, 4, func(cpu *GbcCPU) { cpu.Resb_hl(0x04) }},
-	&Instruction{0xA7, "RES 4,A", 0, 2, func(cpu *GbcCPU) { cpu.Resb_r(0x04, &cpu.R.A) }},
-	&Instruction{0xA8, "RES 5,B", 0, 2, func(cpu *GbcCPU) { cpu.Resb_r(0x05, &cpu.R.B) }},
-	&Instruction{0xA9, "RES 5,C", 0, 2, func(cpu *GbcCPU) { cpu.Resb_r(0x05, &cpu.R.C) }},
-	&Instruction{0xAA, "RES 5,D", 0, 2, func(cpu *GbcCPU) { cpu.Resb_r(0x05, &cpu.R.D) }},
-	&Instruction{0xAB, "RES 5,E", 0, 2, func(cpu *GbcCPU) { cpu.Resb_r(0x05, &cpu.R.E) }},
-	&Instruction{0xAC, "RES 5,H", 0, 2, func(cpu *GbcCPU) { cpu.Resb_r(0x05, &cpu.R.H) }},
-	&Instruction{0xAD, "RES 5,L", 0, 2, func(cpu *GbcCPU) { cpu.Resb_r(0x05, &cpu.R.L) }},
-	&Instruction{0xAE, "RES 5,(HL)", 0, 4, func(cpu *GbcCPU) { cpu.Resb_hl(0x05) }},
-	&Instruction{0xAF, "RES 5,A", 0, 2, func(cpu *GbcCPU) { cpu.Resb_r(0x05, &cpu.R.A) }},
-	&Instruction{0xB0, "RES 6,B", 0, 2, func(cpu *GbcCPU) { cpu.Resb_r(0x06, &cpu.R.B) }},
-	&Instruction{0xB1, "RES 6,C", 0, 2, func(cpu *GbcCPU) { cpu.Resb_r(0x06, &cpu.R.C) }},
-	&Instruction{0xB2, "RES 6,D", 0, 2, func(cpu *GbcCPU) { cpu.Resb_r(0x06, &cpu.R.D) }},
-	&Instruction{0xB3, "RES 6,E", 0, 2, func(cpu *GbcCPU) { cpu.Resb_r(0x06, &cpu.R.E) }},
-	&Instruction{0xB4, "RES 6,H", 0, 2, func(cpu *GbcCPU) { cpu.Resb_r(0x06, &cpu.R.H) }},
-	&Instruction{0xB5, "RES 6,L", 0, 2, func(cpu *GbcCPU) { cpu.Resb_r(0x06, &cpu.R.L) }},
-	&Instruction{0xB6, "RES 6,(HL)", 0, 4, func(cpu *GbcCPU) { cpu.Resb_hl(0x06) }},
-	&Instruction{0xB7, "RES 6,A", 0, 2, func(cpu *GbcCPU) { cpu.Resb_r(0x06, &cpu.R.A) }},
-	&Instruction{0xB8, "RES 7,B", 0, 2, func(cpu *GbcCPU) { cpu.Resb_r(0x07, &cpu.R.B) }},
-	&Instruction{0xB9, "RES 7,C", 0, 2, func(cpu *GbcCPU) { cpu.Resb_r(0x07, &cpu.R.C) }},
-	&Instruction{0xBA, "RES 7,D", 0, 2, func(cpu *GbcCPU) { cpu.Resb_r(0x07, &cpu.R.D) }},
-	&Instruction{0xBB, "RES 7,E", 0, 2, func(cpu *GbcCPU) { cpu.Resb_r(0x07, &cpu.R.E) }},
-	&Instruction{0xBC, "RES 7,H", 0, 2, func(cpu *GbcCPU) { cpu.Resb_r(0x07, &cpu.R.H) }},
-	&Instruction{0xBD, "RES 7,L", 0, 2, func(cpu *GbcCPU) { cpu.Resb_r(0x07, &cpu.R.L) }},
-	&Instruction{0xBE, "RES 7,(HL)", 0, 4, func(cpu *GbcCPU) { cpu.Resb_hl(0x07) }},
-	&Instruction{0xBF, "RES 7,A", 0, 2, func(cpu *GbcCPU) { cpu.Resb_r(0x07, &cpu.R.A) }},
-	&Instruction{0xC0, "SET 0,B", 0, 2, func(cpu *GbcCPU) { cpu.Setb_r(0x00, &cpu.R.B) }},
-	&Instruction{0xC1, "SET 0,C", 0, 2, func(cpu *GbcCPU) { cpu.Setb_r(0x00, &cpu.R.C) }},
-	&Instruction{0xC2, "SET 0,D", 0, 2, func(cpu *GbcCPU) { cpu.Setb_r(0x00, &cpu.R.D) }},
-	&Instruction{0xC3, "SET 0,E", 0, 2, func(cpu *GbcCPU) { cpu.Setb_r(0x00, &cpu.R.E) }},
-	&Instruction{0xC4, "SET 0,H", 0, 2, func(cpu *GbcCPU) { cpu.Setb_r(0x00, &cpu.R.H) }},
-	&Instruction{0xC5, "SET 0,L", 0, 2, func(cpu *GbcCPU) { cpu.Setb_r(0x00, &cpu.R.L) }},
-	&Instruction{0xC6, "SET 0,(HL)", 0, 4, func(cpu *GbcCPU) { cpu.Setb_hl(0x00) }},
-	&Instruction{0xC7, "SET 0,A", 0, 2, func(cpu *GbcCPU) { cpu.Setb_r(0x00, &cpu.R.A) }},
-	&Instruction{0xC8, "SET 1,B", 0, 2, func(cpu *GbcCPU) { cpu.Setb_r(0x01, &cpu.R.B) }},
-	&Instruction{0xC9, "SET 1,C", 0, 2, func(cpu *GbcCPU) { cpu.Setb_r(0x01, &cpu.R.C) }},
-	&Instruction{0xCA, "SET 1,D", 0, 2, func(cpu *GbcCPU) { cpu.Setb_r(0x01, &cpu.R.D) }},
-	&Instruction{0xCB, "SET 1,E", 0, 2, func(cpu *GbcCPU) { cpu.Setb_r(0x01, &cpu.R.E) }},
-	&Instruction{0xCC, "SET 1,H", 0, 2, func(cpu *GbcCPU) { cpu.Setb_r(0x01, &cpu.R.H) }},
-	&Instruction{0xCD, "SET 1,L", 0, 2, func(cpu *GbcCPU) { cpu.Setb_r(0x01, &cpu.R.L) }},
-	&Instruction{0xCE, "SET 1,(HL)", 0, 4, func(cpu *GbcCPU) { cpu.Setb_hl(0x01) }},
-	&Instruction{0xCF, "SET 1,A", 0, 2, func(cpu *GbcCPU) { cpu.Setb_r(0x01, &cpu.R.A) }},
-	&Instruction{0xD0, "SET 2,B", 0, 2, func(cpu *GbcCPU) { cpu.Setb_r(0x02, &cpu.R.B) }},
-	&Instruction{0xD1, "SET 2,C", 0, 2, func(cpu *GbcCPU) { cpu.Setb_r(0x02, &cpu.R.C) }},
-	&Instruction{0xD2, "SET 2,D", 0, 2, func(cpu *GbcCPU) { cpu.Setb_r(0x02, &cpu.R.D) }},
-	&Instruction{0xD3, "SET 2,E", 0, 2, func(cpu *GbcCPU) { cpu.Setb_r(0x02, &cpu.R.E) }},
-	&Instruction{0xD4, "SET 2,H", 0, 2, func(cpu *GbcCPU) { cpu.Setb_r(0x02, &cpu.R.H) }},
-	&Instruction{0xD5, "SET 2,L", 0, 2, func(cpu *GbcCPU) { cpu.Setb_r(0x02, &cpu.R.L) }},
-	&Instruction{0xD6, "SET 2,(HL)", 0, 4, func(cpu *GbcCPU) { cpu.Setb_hl(0x02) }},
-	&Instruction{0xD7, "SET 2,A", 0, 2, func(cpu *GbcCPU) { cpu.Setb_r(0x02, &cpu.R.A) }},
-	&Instruction{0xD8, "SET 3,B", 0, 2, func(cpu *GbcCPU) { cpu.Setb_r(0x03, &cpu.R.B) }},
-	&Instruction{0xD9, "SET 3,C", 0, 2, func(cpu *GbcCPU) { cpu.Setb_r(0x03, &cpu.R.C) }},
-	&Instruction{0xDA, "SET 3,D", 0, 2, func(cpu *GbcCPU) { cpu.Setb_r(0x03, &cpu.R.D) }},
-	&Instruction{0xDB, "SET 3,E", 0, 2, func(cpu *GbcCPU) { cpu.Setb_r(0x03, &cpu.R.E) }},
-	&Instruction{0xDC, "SET 3,H", 0, 2, func(cpu *GbcCPU) { cpu.Setb_r(0x03, &cpu.R.H) }},
-	&Instruction{0xDD, "SET 3,L", 0, 2, func(cpu *GbcCPU) { cpu.Setb_r(0x03, &cpu.R.L) }},
-	&Instruction{0xDE, "SET 3,(HL)", 0, 4, func(cpu *GbcCPU) { cpu.Setb_hl(0x03) }},
-	&Instruction{0xDF, "SET 3,A", 0, 2, func(cpu *GbcCPU) { cpu.Setb_r(0x03, &cpu.R.A) }},
-	&Instruction{0xE0, "SET 4,B", 0, 2, func(cpu *GbcCPU) { cpu.Setb_r(0x04, &cpu.R.B) }},
-	&Instruction{0xE1, "SET 4,C", 0, 2, func(cpu *GbcCPU) { cpu.Setb_r(0x04, &cpu.R.C) }},
-	&Instruction{0xE2, "SET 4,D", 0, 2, func(cpu *GbcCPU) { cpu.Setb_r(0x04, &cpu.R.D) }},
-	&Instruction{0xE3, "SET 4,E", 0, 2, func(cpu *GbcCPU) { cpu.Setb_r(0x04, &cpu.R.E) }},
-	&Instruction{0xE4, "SET 4,H", 0, 2, func(cpu *GbcCPU) { cpu.Setb_r(0x04, &cpu.R.H) }},
-	&Instruction{0xE5, "SET 4,L", 0, 2, func(cpu *GbcCPU) { cpu.Setb_r(0x04, &cpu.R.L) }},
-	&Instruction{0xE6, "SET 4,(HL)", 0, 4, func(cpu *GbcCPU) { cpu.Setb_hl(0x04) }},
-	&Instruction{0xE7, "SET 4,A", 0, 2, func(cpu *GbcCPU) { cpu.Setb_r(0x04, &cpu.R.A) }},
-	&Instruction{0xE8, "SET 5,B", 0, 2, func(cpu *GbcCPU) { cpu.Setb_r(0x05, &cpu.R.B) }},
-	&Instruction{0xE9, "SET 5,C", 0, 2, func(cpu *GbcCPU) { cpu.Setb_r(0x05, &cpu.R.C) }},
-	&Instruction{0xEA, "SET 5,D", 0, 2, func(cpu *GbcCPU) { cpu.Setb_r(0x05, &cpu.R.D) }},
-	&Instruction{0xEB, "SET 5,E", 0, 2, func(cpu *GbcCPU) { cpu.Setb_r(0x05, &cpu.R.E) }},
-	&Instruction{0xEC, "SET 5,H", 0, 2, func(cpu *GbcCPU) { cpu.Setb_r(0x05, &cpu.R.H) }},
-	&Instruction{0xED, "SET 5,L", 0, 2, func(cpu *GbcCPU) { cpu.Setb_r(0x05, &cpu.R.L) }},
-	&Instruction{0xEE, "SET 5,(HL)", 0, 4, func(cpu *GbcCPU) { cpu.Setb_hl(0x05) }},
-	&Instruction{0xEF, "SET 5,A", 0, 2, func(cpu *GbcCPU) { cpu.Setb_r(0x05, &cpu.R.A) }},
-	&Instruction{0xF0, "SET 6,B", 0, 2, func(cpu *GbcCPU) { cpu.Setb_r(0x06, &cpu.R.B) }},
-	&Instruction{0xF1, "SET 6,C", 0, 2, func(cpu *GbcCPU) { cpu.Setb_r(0x06, &cpu.R.C) }},
-	&Instruction{0xF2, "SET 6,D", 0, 2, func(cpu *GbcCPU) { cpu.Setb_r(0x06, &cpu.R.D) }},
-	&Instruction{0xF3, "SET 6,E", 0, 2, func(cpu *GbcCPU) { cpu.Setb_r(0x06, &cpu.R.E) }},
-	&Instruction{0xF4, "SET 6,H", 0, 2, func(cpu *GbcCPU) { cpu.Setb_r(0x06, &cpu.R.H) }},
-	&Instruction{0xF5, "SET 6,L", 0, 2, func(cpu *GbcCPU) { cpu.Setb_r(0x06, &cpu.R.L) }},
-	&Instruction{0xF6, "SET 6,(HL)", 0, 4, func(cpu *GbcCPU) { cpu.Setb_hl(0x06) }},
-	&Instruction{0xF7, "SET 6,A", 0, 2, func(cpu *GbcCPU) { cpu.Setb_r(0x06, &cpu.R.A) }},
-	&Instruction{0xF8, "SET 7,B", 0, 2, func(cpu *GbcCPU) { cpu.Setb_r(0x07, &cpu.R.B) }},
-	&Instruction{0xF9, "SET 7,C", 0, 2, func(cpu *GbcCPU) { cpu.Setb_r(0x07, &cpu.R.C) }},
-	&Instruction{0xFA, "SET 7,D", 0, 2, func(cpu *GbcCPU) { cpu.Setb_r(0x07, &cpu.R.D) }},
-	&Instruction{0xFB, "SET 7,E", 0, 2, func(cpu *GbcCPU) { cpu.Setb_r(0x07, &cpu.R.E) }},
-	&Instruction{0xFC, "SET 7,H", 0, 2, func(cpu *GbcCPU) { cpu.Setb_r(0x07, &cpu.R.H) }},
-	&Instruction{0xFD, "SET 7,L", 0, 2, func(cpu *GbcCPU) { cpu.Setb_r(0x07, &cpu.R.L) }},
-	&Instruction{0xFE, "SET 7,(HL)", 0, 4, func(cpu *GbcCPU) { cpu.Setb_hl(0x07) }},
-	&Instruction{0xFF, "SET 7,A", 0, 2, func(cpu *GbcCPU) { cpu.Setb_r(0x07, &cpu.R.A) }},
+	&Instruction{0x0, "RLC B", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Rlc_r(&cpu.R.B) }},
+	&Instruction{0x1, "RLC C", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Rlc_r(&cpu.R.C) }},
+	&Instruction{0x2, "RLC D", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Rlc_r(&cpu.R.D) }},
+	&Instruction{0x3, "RLC E", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Rlc_r(&cpu.R.E) }},
+	&Instruction{0x4, "RLC H", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Rlc_r(&cpu.R.H) }},
+	&Instruction{0x5, "RLC L", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Rlc_r(&cpu.R.L) }},
+	&Instruction{0x6, "RLC (HL)", 0, 4, func(cpu *GbcCPU, operands [2]byte) { cpu.Rlc_hl() }},
+	&Instruction{0x7, "RLC A", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Rlc_r(&cpu.R.A) }},
+	&Instruction{0x8, "RRC B", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Rrc_r(&cpu.R.B) }},
+	&Instruction{0x9, "RRC C", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Rrc_r(&cpu.R.C) }},
+	&Instruction{0xA, "RRC D", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Rrc_r(&cpu.R.D) }},
+	&Instruction{0xB, "RRC E", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Rrc_r(&cpu.R.E) }},
+	&Instruction{0xC, "RRC H", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Rrc_r(&cpu.R.H) }},
+	&Instruction{0xD, "RRC L", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Rrc_r(&cpu.R.L) }},
+	&Instruction{0xE, "RRC (HL)", 0, 4, func(cpu *GbcCPU, operands [2]byte) { cpu.Rrc_hl() }},
+	&Instruction{0xF, "RRC A", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Rrc_r(&cpu.R.A) }},
+	&Instruction{0x10, "RL B", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Rl_r(&cpu.R.B) }},
+	&Instruction{0x11, "RL C", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Rl_r(&cpu.R.C) }},
+	&Instruction{0x12, "RL D", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Rl_r(&cpu.R.D) }},
+	&Instruction{0x13, "RL E", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Rl_r(&cpu.R.E) }},
+	&Instruction{0x14, "RL H", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Rl_r(&cpu.R.H) }},
+	&Instruction{0x15, "RL L", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Rl_r(&cpu.R.L) }},
+	&Instruction{0x16, "RL (HL)", 0, 4, func(cpu *GbcCPU, operands [2]byte) { cpu.Rl_hl() }},
+	&Instruction{0x17, "RL A", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Rl_r(&cpu.R.A) }},
+	&Instruction{0x18, "RR B", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Rr_r(&cpu.R.B) }},
+	&Instruction{0x19, "RR C", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Rr_r(&cpu.R.C) }},
+	&Instruction{0x1A, "RR D", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Rr_r(&cpu.R.D) }},
+	&Instruction{0x1B, "RR E", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Rr_r(&cpu.R.E) }},
+	&Instruction{0x1C, "RR H", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Rr_r(&cpu.R.H) }},
+	&Instruction{0x1D, "RR L", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Rr_r(&cpu.R.L) }},
+	&Instruction{0x1E, "RR (HL)", 0, 4, func(cpu *GbcCPU, operands [2]byte) { cpu.Rr_hl() }},
+	&Instruction{0x1F, "RR A", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Rr_r(&cpu.R.A) }},
+	&Instruction{0x20, "SLA B", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Sla_r(&cpu.R.B) }},
+	&Instruction{0x21, "SLA C", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Sla_r(&cpu.R.C) }},
+	&Instruction{0x22, "SLA D", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Sla_r(&cpu.R.D) }},
+	&Instruction{0x23, "SLA E", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Sla_r(&cpu.R.E) }},
+	&Instruction{0x24, "SLA H", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Sla_r(&cpu.R.H) }},
+	&Instruction{0x25, "SLA L", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Sla_r(&cpu.R.L) }},
+	&Instruction{0x26, "SLA (HL)", 0, 4, func(cpu *GbcCPU, operands [2]byte) { cpu.Sla_hl() }},
+	&Instruction{0x27, "SLA A", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Sla_r(&cpu.R.A) }},
+	&Instruction{0x28, "SRA B", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Sra_r(&cpu.R.B) }},
+	&Instruction{0x29, "SRA C", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Sra_r(&cpu.R.C) }},
+	&Instruction{0x2A, "SRA D", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Sra_r(&cpu.R.D) }},
+	&Instruction{0x2B, "SRA E", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Sra_r(&cpu.R.E) }},
+	&Instruction{0x2C, "SRA H", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Sra_r(&cpu.R.H) }},
+	&Instruction{0x2D, "SRA L", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Sra_r(&cpu.R.L) }},
+	&Instruction{0x2E, "SRA (HL)", 0, 4, func(cpu *GbcCPU, operands [2]byte) { cpu.Sra_hl() }},
+	&Instruction{0x2F, "SRA A", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Sra_r(&cpu.R.A) }},
+	&Instruction{0x30, "SWAP B", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Swap_r(&cpu.R.B) }},
+	&Instruction{0x31, "SWAP C", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Swap_r(&cpu.R.C) }},
+	&Instruction{0x32, "SWAP D", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Swap_r(&cpu.R.D) }},
+	&Instruction{0x33, "SWAP E", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Swap_r(&cpu.R.E) }},
+	&Instruction{0x34, "SWAP H", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Swap_r(&cpu.R.H) }},
+	&Instruction{0x35, "SWAP L", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Swap_r(&cpu.R.L) }},
+	&Instruction{0x36, "SWAP (HL)", 0, 4, func(cpu *GbcCPU, operands [2]byte) { cpu.Swap_hl() }},
+	&Instruction{0x37, "SWAP A", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Swap_r(&cpu.R.A) }},
+	&Instruction{0x38, "SRL B", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Srl_r(&cpu.R.B) }},
+	&Instruction{0x39, "SRL C", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Srl_r(&cpu.R.C) }},
+	&Instruction{0x3A, "SRL D", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Srl_r(&cpu.R.D) }},
+	&Instruction{0x3B, "SRL E", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Srl_r(&cpu.R.E) }},
+	&Instruction{0x3C, "SRL H", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Srl_r(&cpu.R.H) }},
+	&Instruction{0x3D, "SRL L", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Srl_r(&cpu.R.L) }},
+	&Instruction{0x3E, "SRL (HL)", 0, 4, func(cpu *GbcCPU, operands [2]byte) { cpu.Srl_hl() }},
+	&Instruction{0x3F, "SRL A", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Srl_r(&cpu.R.A) }},
+	&Instruction{0x40, "BIT 0,B", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Bitb_r(0x00, &cpu.R.B) }},
+	&Instruction{0x41, "BIT 0,C", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Bitb_r(0x00, &cpu.R.C) }},
+	&Instruction{0x42, "BIT 0,D", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Bitb_r(0x00, &cpu.R.D) }},
+	&Instruction{0x43, "BIT 0,E", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Bitb_r(0x00, &cpu.R.E) }},
+	&Instruction{0x44, "BIT 0,H", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Bitb_r(0x00, &cpu.R.H) }},
+	&Instruction{0x45, "BIT 0,L", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Bitb_r(0x00, &cpu.R.L) }},
+	&Instruction{0x46, "BIT 0,(HL)", 0, 3, func(cpu *GbcCPU, operands [2]byte) { cpu.Bitb_hl(0x00) }},
+	&Instruction{0x47, "BIT 0,A", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Bitb_r(0x00, &cpu.R.A) }},
+	&Instruction{0x48, "BIT 1,B", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Bitb_r(0x01, &cpu.R.B) }},
+	&Instruction{0x49, "BIT 1,C", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Bitb_r(0x01, &cpu.R.C) }},
+	&Instruction{0x4A, "BIT 1,D", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Bitb_r(0x01, &cpu.R.D) }},
+	&Instruction{0x4B, "BIT 1,E", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Bitb_r(0x01, &cpu.R.E) }},
+	&Instruction{0x4C, "BIT 1,H", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Bitb_r(0x01, &cpu.R.H) }},
+	&Instruction{0x4D, "BIT 1,L", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Bitb_r(0x01, &cpu.R.L) }},
+	&Instruction{0x4E, "BIT 1,(HL)", 0, 3, func(cpu *GbcCPU, operands [2]byte) { cpu.Bitb_hl(0x01) }},
+	&Instruction{0x4F, "BIT 1,A", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Bitb_r(0x01, &cpu.R.A) }},
+	&Instruction{0x50, "BIT 2,B", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Bitb_r(0x02, &cpu.R.B) }},
+	&Instruction{0x51, "BIT 2,C", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Bitb_r(0x02, &cpu.R.C) }},
+	&Instruction{0x52, "BIT 2,D", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Bitb_r(0x02, &cpu.R.D) }},
+	&Instruction{0x53, "BIT 2,E", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Bitb_r(0x02, &cpu.R.E) }},
+	&Instruction{0x54, "BIT 2,H", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Bitb_r(0x02, &cpu.R.H) }},
+	&Instruction{0x55, "BIT 2,L", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Bitb_r(0x02, &cpu.R.L) }},
+	&Instruction{0x56, "BIT 2,(HL)", 0, 3, func(cpu *GbcCPU, operands [2]byte) { cpu.Bitb_hl(0x02) }},
+	&Instruction{0x57, "BIT 2,A", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Bitb_r(0x02, &cpu.R.A) }},
+	&Instruction{0x58, "BIT 3,B", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Bitb_r(0x03, &cpu.R.B) }},
+	&Instruction{0x59, "BIT 3,C", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Bitb_r(0x03, &cpu.R.C) }},
+	&Instruction{0x5A, "BIT 3,D", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Bitb_r(0x03, &cpu.R.D) }},
+	&Instruction{0x5B, "BIT 3,E", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Bitb_r(0x03, &cpu.R.E) }},
+	&Instruction{0x5C, "BIT 3,H", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Bitb_r(0x03, &cpu.R.H) }},
+	&Instruction{0x5D, "BIT 3,L", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Bitb_r(0x03, &cpu.R.L) }},
+	&Instruction{0x5E, "BIT 3,(HL)", 0, 3, func(cpu *GbcCPU, operands [2]byte) { cpu.Bitb_hl(0x03) }},
+	&Instruction{0x5F, "BIT 3,A", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Bitb_r(0x03, &cpu.R.A) }},
+	&Instruction{0x60, "BIT 4,B", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Bitb_r(0x04, &cpu.R.B) }},
+	&Instruction{0x61, "BIT 4,C", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Bitb_r(0x04, &cpu.R.C) }},
+	&Instruction{0x62, "BIT 4,D", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Bitb_r(0x04, &cpu.R.D) }},
+	&Instruction{0x63, "BIT 4,E", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Bitb_r(0x04, &cpu.R.E) }},
+	&Instruction{0x64, "BIT 4,H", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Bitb_r(0x04, &cpu.R.H) }},
+	&Instruction{0x65, "BIT 4,L", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Bitb_r(0x04, &cpu.R.L) }},
+	&Instruction{0x66, "BIT 4,(HL)", 0, 3, func(cpu *GbcCPU, operands [2]byte) { cpu.Bitb_hl(0x04) }},
+	&Instruction{0x67, "BIT 4,A", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Bitb_r(0x04, &cpu.R.A) }},
+	&Instruction{0x68, "BIT 5,B", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Bitb_r(0x05, &cpu.R.B) }},
+	&Instruction{0x69, "BIT 5,C", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Bitb_r(0x05, &cpu.R.C) }},
+	&Instruction{0x6A, "BIT 5,D", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Bitb_r(0x05, &cpu.R.D) }},
+	&Instruction{0x6B, "BIT 5,E", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Bitb_r(0x05, &cpu.R.E) }},
+	&Instruction{0x6C, "BIT 5,H", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Bitb_r(0x05, &cpu.R.H) }},
+	&Instruction{0x6D, "BIT 5,L", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Bitb_r(0x05, &cpu.R.L) }},
+	&Instruction{0x6E, "BIT 5,(HL)", 0, 3, func(cpu *GbcCPU, operands [2]byte) { cpu.Bitb_hl(0x05) }},
+	&Instruction{0x6F, "BIT 5,A", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Bitb_r(0x05, &cpu.R.A) }},
+	&Instruction{0x70, "BIT 6,B", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Bitb_r(0x06, &cpu.R.B) }},
+	&Instruction{0x71, "BIT 6,C", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Bitb_r(0x06, &cpu.R.C) }},
+	&Instruction{0x72, "BIT 6,D", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Bitb_r(0x06, &cpu.R.D) }},
+	&Instruction{0x73, "BIT 6,E", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Bitb_r(0x06, &cpu.R.E) }},
+	&Instruction{0x74, "BIT 6,H", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Bitb_r(0x06, &cpu.R.H) }},
+	&Instruction{0x75, "BIT 6,L", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Bitb_r(0x06, &cpu.R.L) }},
+	&Instruction{0x76, "BIT 6,(HL)", 0, 3, func(cpu *GbcCPU, operands [2]byte) { cpu.Bitb_hl(0x06) }},
+	&Instruction{0x77, "BIT 6,A", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Bitb_r(0x06, &cpu.R.A) }},
+	&Instruction{0x78, "BIT 7,B", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Bitb_r(0x07, &cpu.R.B) }},
+	&Instruction{0x79, "BIT 7,C", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Bitb_r(0x07, &cpu.R.C) }},
+	&Instruction{0x7A, "BIT 7,D", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Bitb_r(0x07, &cpu.R.D) }},
+	&Instruction{0x7B, "BIT 7,E", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Bitb_r(0x07, &cpu.R.E) }},
+	&Instruction{0x7C, "BIT 7,H", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Bitb_r(0x07, &cpu.R.H) }},
+	&Instruction{0x7D, "BIT 7,L", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Bitb_r(0x07, &cpu.R.L) }},
+	&Instruction{0x7E, "BIT 7,(HL)", 0, 3, func(cpu *GbcCPU, operands [2]byte) { cpu.Bitb_hl(0x07) }},
+	&Instruction{0x7F, "BIT 7,A", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Bitb_r(0x07, &cpu.R.A) }},
+	&Instruction{0x80, "RES 0,B", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Resb_r(0x00, &cpu.R.B) }},
+	&Instruction{0x81, "RES 0,C", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Resb_r(0x00, &cpu.R.C) }},
+	&Instruction{0x82, "RES 0,D", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Resb_r(0x00, &cpu.R.D) }},
+	&Instruction{0x83, "RES 0,E", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Resb_r(0x00, &cpu.R.E) }},
+	&Instruction{0x84, "RES 0,H", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Resb_r(0x00, &cpu.R.H) }},
+	&Instruction{0x85, "RES 0,L", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Resb_r(0x00, &cpu.R.L) }},
+	&Instruction{0x86, "RES 0,(HL)", 0, 4, func(cpu *GbcCPU, operands [2]byte) { cpu.Resb_hl(0x00) }},
+	&Instruction{0x87, "RES 0,A", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Resb_r(0x00, &cpu.R.A) }},
+	&Instruction{0x88, "RES 1,B", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Resb_r(0x01, &cpu.R.B) }},
+	&Instruction{0x89, "RES 1,C", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Resb_r(0x01, &cpu.R.C) }},
+	&Instruction{0x8A, "RES 1,D", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Resb_r(0x01, &cpu.R.D) }},
+	&Instruction{0x8B, "RES 1,E", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Resb_r(0x01, &cpu.R.E) }},
+	&Instruction{0x8C, "RES 1,H", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Resb_r(0x01, &cpu.R.H) }},
+	&Instruction{0x8D, "RES 1,L", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Resb_r(0x01, &cpu.R.L) }},
+	&Instruction{0x8E, "RES 1,(HL)", 0, 4, func(cpu *GbcCPU, operands [2]byte) { cpu.Resb_hl(0x01) }},
+	&Instruction{0x8F, "RES 1,A", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Resb_r(0x01, &cpu.R.A) }},
+	&Instruction{0x90, "RES 2,B", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Resb_r(0x02, &cpu.R.B) }},
+	&Instruction{0x91, "RES 2,C", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Resb_r(0x02, &cpu.R.C) }},
+	&Instruction{0x92, "RES 2,D", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Resb_r(0x02, &cpu.R.D) }},
+	&Instruction{0x93, "RES 2,E", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Resb_r(0x02, &cpu.R.E) }},
+	&Instruction{0x94, "RES 2,H", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Resb_r(0x02, &cpu.R.H) }},
+	&Instruction{0x95, "RES 2,L", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Resb_r(0x02, &cpu.R.L) }},
+	&Instruction{0x96, "RES 2,(HL)", 0, 4, func(cpu *GbcCPU, operands [2]byte) { cpu.Resb_hl(0x02) }},
+	&Instruction{0x97, "RES 2,A", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Resb_r(0x02, &cpu.R.A) }},
+	&Instruction{0x98, "RES 3,B", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Resb_r(0x03, &cpu.R.B) }},
+	&Instruction{0x99, "RES 3,C", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Resb_r(0x03, &cpu.R.C) }},
+	&Instruction{0x9A, "RES 3,D", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Resb_r(0x03, &cpu.R.D) }},
+	&Instruction{0x9B, "RES 3,E", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Resb_r(0x03, &cpu.R.E) }},
+	&Instruction{0x9C, "RES 3,H", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Resb_r(0x03, &cpu.R.H) }},
+	&Instruction{0x9D, "RES 3,L", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Resb_r(0x03, &cpu.R.L) }},
+	&Instruction{0x9E, "RES 3,(HL)", 0, 4, func(cpu *GbcCPU, operands [2]byte) { cpu.Resb_hl(0x03) }},
+	&Instruction{0x9F, "RES 3,A", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Resb_r(0x03, &cpu.R.A) }},
+	&Instruction{0xA0, "RES 4,B", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Resb_r(0x04, &cpu.R.B) }},
+	&Instruction{0xA1, "RES 4,C", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Resb_r(0x04, &cpu.R.C) }},
+	&Instruction{0xA2, "RES 4,D", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Resb_r(0x04, &cpu.R.D) }},
+	&Instruction{0xA3, "RES 4,E", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Resb_r(0x04, &cpu.R.E) }},
+	&Instruction{0xA4, "RES 4,H", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Resb_r(0x04, &cpu.R.H) }},
+	&Instruction{0xA5, "RES 4,L", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Resb_r(0x04, &cpu.R.L) }},
+	&Instruction{0xA6, "RES 4,(HL)", 0, 4, func(cpu *GbcCPU, operands [2]byte) { cpu.Resb_hl(0x04) }},
+	&Instruction{0xA7, "RES 4,A", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Resb_r(0x04, &cpu.R.A) }},
+	&Instruction{0xA8, "RES 5,B", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Resb_r(0x05, &cpu.R.B) }},
+	&Instruction{0xA9, "RES 5,C", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Resb_r(0x05, &cpu.R.C) }},
+	&Instruction{0xAA, "RES 5,D", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Resb_r(0x05, &cpu.R.D) }},
+	&Instruction{0xAB, "RES 5,E", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Resb_r(0x05, &cpu.R.E) }},
+	&Instruction{0xAC, "RES 5,H", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Resb_r(0x05, &cpu.R.H) }},
+	&Instruction{0xAD, "RES 5,L", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Resb_r(0x05, &cpu.R.L) }},
+	&Instruction{0xAE, "RES 5,(HL)", 0, 4, func(cpu *GbcCPU, operands [2]byte) { cpu.Resb_hl(0x05) }},
+	&Instruction{0xAF, "RES 5,A", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Resb_r(0x05, &cpu.R.A) }},
+	&Instruction{0xB0, "RES 6,B", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Resb_r(0x06, &cpu.R.B) }},
+	&Instruction{0xB1, "RES 6,C", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Resb_r(0x06, &cpu.R.C) }},
+	&Instruction{0xB2, "RES 6,D", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Resb_r(0x06, &cpu.R.D) }},
+	&Instruction{0xB3, "RES 6,E", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Resb_r(0x06, &cpu.R.E) }},
+	&Instruction{0xB4, "RES 6,H", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Resb_r(0x06, &cpu.R.H) }},
+	&Instruction{0xB5, "RES 6,L", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Resb_r(0x06, &cpu.R.L) }},
+	&Instruction{0xB6, "RES 6,(HL)", 0, 4, func(cpu *GbcCPU, operands [2]byte) { cpu.Resb_hl(0x06) }},
+	&Instruction{0xB7, "RES 6,A", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Resb_r(0x06, &cpu.R.A) }},
+	&Instruction{0xB8, "RES 7,B", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Resb_r(0x07, &cpu.R.B) }},
+	&Instruction{0xB9, "RES 7,C", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Resb_r(0x07, &cpu.R.C) }},
+	&Instruction{0xBA, "RES 7,D", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Resb_r(0x07, &cpu.R.D) }},
+	&Instruction{0xBB, "RES 7,E", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Resb_r(0x07, &cpu.R.E) }},
+	&Instruction{0xBC, "RES 7,H", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Resb_r(0x07, &cpu.R.H) }},
+	&Instruction{0xBD, "RES 7,L", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Resb_r(0x07, &cpu.R.L) }},
+	&Instruction{0xBE, "RES 7,(HL)", 0, 4, func(cpu *GbcCPU, operands [2]byte) { cpu.Resb_hl(0x07) }},
+	&Instruction{0xBF, "RES 7,A", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Resb_r(0x07, &cpu.R.A) }},
+	&Instruction{0xC0, "SET 0,B", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Setb_r(0x00, &cpu.R.B) }},
+	&Instruction{0xC1, "SET 0,C", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Setb_r(0x00, &cpu.R.C) }},
+	&Instruction{0xC2, "SET 0,D", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Setb_r(0x00, &cpu.R.D) }},
+	&Instruction{0xC3, "SET 0,E", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Setb_r(0x00, &cpu.R.E) }},
+	&Instruction{0xC4, "SET 0,H", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Setb_r(0x00, &cpu.R.H) }},
+	&Instruction{0xC5, "SET 0,L", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Setb_r(0x00, &cpu.R.L) }},
+	&Instruction{0xC6, "SET 0,(HL)", 0, 4, func(cpu *GbcCPU, operands [2]byte) { cpu.Setb_hl(0x00) }},
+	&Instruction{0xC7, "SET 0,A", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Setb_r(0x00, &cpu.R.A) }},
+	&Instruction{0xC8, "SET 1,B", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Setb_r(0x01, &cpu.R.B) }},
+	&Instruction{0xC9, "SET 1,C", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Setb_r(0x01, &cpu.R.C) }},
+	&Instruction{0xCA, "SET 1,D", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Setb_r(0x01, &cpu.R.D) }},
+	&Instruction{0xCB, "SET 1,E", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Setb_r(0x01, &cpu.R.E) }},
+	&Instruction{0xCC, "SET 1,H", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Setb_r(0x01, &cpu.R.H) }},
+	&Instruction{0xCD, "SET 1,L", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Setb_r(0x01, &cpu.R.L) }},
+	&Instruction{0xCE, "SET 1,(HL)", 0, 4, func(cpu *GbcCPU, operands [2]byte) { cpu.Setb_hl(0x01) }},
+	&Instruction{0xCF, "SET 1,A", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Setb_r(0x01, &cpu.R.A) }},
+	&Instruction{0xD0, "SET 2,B", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Setb_r(0x02, &cpu.R.B) }},
+	&Instruction{0xD1, "SET 2,C", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Setb_r(0x02, &cpu.R.C) }},
+	&Instruction{0xD2, "SET 2,D", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Setb_r(0x02, &cpu.R.D) }},
+	&Instruction{0xD3, "SET 2,E", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Setb_r(0x02, &cpu.R.E) }},
+	&Instruction{0xD4, "SET 2,H", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Setb_r(0x02, &cpu.R.H) }},
+	&Instruction{0xD5, "SET 2,L", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Setb_r(0x02, &cpu.R.L) }},
+	&Instruction{0xD6, "SET 2,(HL)", 0, 4, func(cpu *GbcCPU, operands [2]byte) { cpu.Setb_hl(0x02) }},
+	&Instruction{0xD7, "SET 2,A", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Setb_r(0x02, &cpu.R.A) }},
+	&Instruction{0xD8, "SET 3,B", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Setb_r(0x03, &cpu.R.B) }},
+	&Instruction{0xD9, "SET 3,C", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Setb_r(0x03, &cpu.R.C) }},
+	&Instruction{0xDA, "SET 3,D", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Setb_r(0x03, &cpu.R.D) }},
+	&Instruction{0xDB, "SET 3,E", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Setb_r(0x03, &cpu.R.E) }},
+	&Instruction{0xDC, "SET 3,H", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Setb_r(0x03, &cpu.R.H) }},
+	&Instruction{0xDD, "SET 3,L", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Setb_r(0x03, &cpu.R.L) }},
+	&Instruction{0xDE, "SET 3,(HL)", 0, 4, func(cpu *GbcCPU, operands [2]byte) { cpu.Setb_hl(0x03) }},
+	&Instruction{0xDF, "SET 3,A", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Setb_r(0x03, &cpu.R.A) }},
+	&Instruction{0xE0, "SET 4,B", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Setb_r(0x04, &cpu.R.B) }},
+	&Instruction{0xE1, "SET 4,C", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Setb_r(0x04, &cpu.R.C) }},
+	&Instruction{0xE2, "SET 4,D", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Setb_r(0x04, &cpu.R.D) }},
+	&Instruction{0xE3, "SET 4,E", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Setb_r(0x04, &cpu.R.E) }},
+	&Instruction{0xE4, "SET 4,H", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Setb_r(0x04, &cpu.R.H) }},
+	&Instruction{0xE5, "SET 4,L", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Setb_r(0x04, &cpu.R.L) }},
+	&Instruction{0xE6, "SET 4,(HL)", 0, 4, func(cpu *GbcCPU, operands [2]byte) { cpu.Setb_hl(0x04) }},
+	&Instruction{0xE7, "SET 4,A", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Setb_r(0x04, &cpu.R.A) }},
+	&Instruction{0xE8, "SET 5,B", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Setb_r(0x05, &cpu.R.B) }},
+	&Instruction{0xE9, "SET 5,C", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Setb_r(0x05, &cpu.R.C) }},
+	&Instruction{0xEA, "SET 5,D", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Setb_r(0x05, &cpu.R.D) }},
+	&Instruction{0xEB, "SET 5,E", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Setb_r(0x05, &cpu.R.E) }},
+	&Instruction{0xEC, "SET 5,H", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Setb_r(0x05, &cpu.R.H) }},
+	&Instruction{0xED, "SET 5,L", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Setb_r(0x05, &cpu.R.L) }},
+	&Instruction{0xEE, "SET 5,(HL)", 0, 4, func(cpu *GbcCPU, operands [2]byte) { cpu.Setb_hl(0x05) }},
+	&Instruction{0xEF, "SET 5,A", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Setb_r(0x05, &cpu.R.A) }},
+	&Instruction{0xF0, "SET 6,B", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Setb_r(0x06, &cpu.R.B) }},
+	&Instruction{0xF1, "SET 6,C", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Setb_r(0x06, &cpu.R.C) }},
+	&Instruction{0xF2, "SET 6,D", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Setb_r(0x06, &cpu.R.D) }},
+	&Instruction{0xF3, "SET 6,E", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Setb_r(0x06, &cpu.R.E) }},
+	&Instruction{0xF4, "SET 6,H", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Setb_r(0x06, &cpu.R.H) }},
+	&Instruction{0xF5, "SET 6,L", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Setb_r(0x06, &cpu.R.L) }},
+	&Instruction{0xF6, "SET 6,(HL)", 0, 4, func(cpu *GbcCPU, operands [2]byte) { cpu.Setb_hl(0x06) }},
+	&Instruction{0xF7, "SET 6,A", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Setb_r(0x06, &cpu.R.A) }},
+	&Instruction{0xF8, "SET 7,B", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Setb_r(0x07, &cpu.R.B) }},
+	&Instruction{0xF9, "SET 7,C", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Setb_r(0x07, &cpu.R.C) }},
+	&Instruction{0xFA, "SET 7,D", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Setb_r(0x07, &cpu.R.D) }},
+	&Instruction{0xFB, "SET 7,E", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Setb_r(0x07, &cpu.R.E) }},
+	&Instruction{0xFC, "SET 7,H", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Setb_r(0x07, &cpu.R.H) }},
+	&Instruction{0xFD, "SET 7,L", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Setb_r(0x07, &cpu.R.L) }},
+	&Instruction{0xFE, "SET 7,(HL)", 0, 4, func(cpu *GbcCPU, operands [2]byte) { cpu.Setb_hl(0x07) }},
+	&Instruction{0xFF, "SET 7,A", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Setb_r(0x07, &cpu.R.A) }},
 }
 
 var Instructions []*Instruction = []*Instruction{
-	&Instruction{0x0, "NOP", 0, 1, func(cpu *GbcCPU) { cpu.NOP() }},
-	&Instruction{0x1, "LD  BC,(a16)", 2, 3, func(cpu *GbcCPU) { cpu.LDn_nn(&cpu.R.B, &cpu.R.C) }},
-	&Instruction{0x2, "LD  (BC),A", 0, 2, func(cpu *GbcCPU) { cpu.LDrr_r(&cpu.R.B, &cpu.R.C, &cpu.R.A) }},
-	&Instruction{0x3, "INC  BC", 0, 2, func(cpu *GbcCPU) { cpu.Inc_rr(&cpu.R.B, &cpu.R.C) }},
-	&Instruction{0x4, "INC  B", 0, 1, func(cpu *GbcCPU) { cpu.Inc_r(&cpu.R.B) }},
-	&Instruction{0x5, "DEC  B", 0, 1, func(cpu *GbcCPU) { cpu.Dec_r(&cpu.R.B) }},
-	&Instruction{0x6, "LD  B,n", 1, 2, func(cpu *GbcCPU) { cpu.LDrn(&cpu.R.B) }},
-	&Instruction{0x7, "RLCA", 0, 1, func(cpu *GbcCPU) { cpu.RLCA() }},
-	&Instruction{0x8, "LD  (a16),SP", 2, 5, func(cpu *GbcCPU) { cpu.LDnn_SP() }},
-	&Instruction{0x9, "ADD  HL,BC", 0, 2, func(cpu *GbcCPU) { cpu.Addhl_rr(&cpu.R.B, &cpu.R.C) }},
-	&Instruction{0xA, "LD  A,(BC)", 0, 2, func(cpu *GbcCPU) { cpu.LDr_rr(&cpu.R.B, &cpu.R.C, &cpu.R.A) }},
-	&Instruction{0xB, "DEC  BC", 0, 2, func(cpu *GbcCPU) { cpu.Dec_rr(&cpu.R.B, &cpu.R.C) }},
-	&Instruction{0xC, "INC  C", 0, 1, func(cpu *GbcCPU) { cpu.Inc_r(&cpu.R.C) }},
-	&Instruction{0xD, "DEC  C", 0, 1, func(cpu *GbcCPU) { cpu.Dec_r(&cpu.R.C) }},
-	&Instruction{0xE, "LD  C,n", 1, 2, func(cpu *GbcCPU) { cpu.LDrn(&cpu.R.C) }},
-	&Instruction{0xF, "RRCA", 0, 1, func(cpu *GbcCPU) { cpu.RRCA() }},
-	&Instruction{0x10, "STOP", 1, 0, func(cpu *GbcCPU) { cpu.Stop() }},
-	&Instruction{0x11, "LD  DE,(a16)", 2, 3, func(cpu *GbcCPU) { cpu.LDn_nn(&cpu.R.D, &cpu.R.E) }},
-	&Instruction{0x12, "LD  (DE),A", 0, 2, func(cpu *GbcCPU) { cpu.LDrr_r(&cpu.R.D, &cpu.R.E, &cpu.R.A) }},
-	&Instruction{0x13, "INC  DE", 0, 2, func(cpu *GbcCPU) { cpu.Inc_rr(&cpu.R.D, &cpu.R.E) }},
-	&Instruction{0x14, "INC  D", 0, 1, func(cpu *GbcCPU) { cpu.Inc_r(&cpu.R.D) }},
-	&Instruction{0x15, "DEC  D", 0, 1, func(cpu *GbcCPU) { cpu.Dec_r(&cpu.R.D) }},
-	&Instruction{0x16, "LD  D,n", 1, 2, func(cpu *GbcCPU) { cpu.LDrn(&cpu.R.D) }},
-	&Instruction{0x17, "RLA", 0, 1, func(cpu *GbcCPU) { cpu.RLA() }},
-	&Instruction{0x18, "JR  r8", 1, 3, func(cpu *GbcCPU) { cpu.JR_n() }},
-	&Instruction{0x19, "ADD  HL,DE", 0, 2, func(cpu *GbcCPU) { cpu.Addhl_rr(&cpu.R.D, &cpu.R.E) }},
-	&Instruction{0x1A, "LD  A,(DE)", 0, 2, func(cpu *GbcCPU) { cpu.LDr_rr(&cpu.R.D, &cpu.R.E, &cpu.R.A) }},
-	&Instruction{0x1B, "DEC  DE", 0, 2, func(cpu *GbcCPU) { cpu.Dec_rr(&cpu.R.D, &cpu.R.E) }},
-	&Instruction{0x1C, "INC  E", 0, 1, func(cpu *GbcCPU) { cpu.Inc_r(&cpu.R.E) }},
-	&Instruction{0x1D, "DEC  E", 0, 1, func(cpu *GbcCPU) { cpu.Dec_r(&cpu.R.E) }},
-	&Instruction{0x1E, "LD E,n", 1, 2, func(cpu *GbcCPU) { cpu.LDrn(&cpu.R.E) }},
-	&Instruction{0x1F, "RRA", 0, 1, func(cpu *GbcCPU) { cpu.RRA() }},
-	&Instruction{0x20, "JR  NZ,r8", 1, 0, func(cpu *GbcCPU) { cpu.JRcc_nn(Z, false) }},
-	&Instruction{0x21, "LD  HL,d16", 2, 3, func(cpu *GbcCPU) { cpu.LDn_nn(&cpu.R.H, &cpu.R.L) }},
-	&Instruction{0x22, "LD  (HL+),A", 0, 2, func(cpu *GbcCPU) { cpu.LDIhl_r(&cpu.R.A) }},
-	&Instruction{0x23, "INC  HL", 0, 2, func(cpu *GbcCPU) { cpu.Inc_rr(&cpu.R.H, &cpu.R.L) }},
-	&Instruction{0x24, "INC  H", 0, 1, func(cpu *GbcCPU) { cpu.Inc_r(&cpu.R.H) }},
-	&Instruction{0x25, "DEC  H", 0, 1, func(cpu *GbcCPU) { cpu.Dec_r(&cpu.R.H) }},
-	&Instruction{0x26, "LD H,n", 1, 2, func(cpu *GbcCPU) { cpu.LDrn(&cpu.R.H) }},
-	&Instruction{0x27, "DAA", 0, 1, func(cpu *GbcCPU) { cpu.Daa() }},
-	&Instruction{0x28, "JR  Z,r8", 1, 0, func(cpu *GbcCPU) { cpu.JRcc_nn(Z, true) }},
-	&Instruction{0x29, "ADD  HL,HL", 0, 2, func(cpu *GbcCPU) { cpu.Addhl_rr(&cpu.R.H, &cpu.R.L) }},
-	&Instruction{0x2A, "LD  A,(HL+)", 0, 2, func(cpu *GbcCPU) { cpu.LDIr_hl(&cpu.R.A) }},
-	&Instruction{0x2B, "DEC  HL", 0, 2, func(cpu *GbcCPU) { cpu.Dec_rr(&cpu.R.H, &cpu.R.L) }},
-	&Instruction{0x2C, "INC  L", 0, 1, func(cpu *GbcCPU) { cpu.Inc_r(&cpu.R.L) }},
-	&Instruction{0x2D, "DEC  L", 0, 1, func(cpu *GbcCPU) { cpu.Dec_r(&cpu.R.L) }},
-	&Instruction{0x2E, "LD L,n", 1, 2, func(cpu *GbcCPU) { cpu.LDrn(&cpu.R.L) }},
-	&Instruction{0x2F, "CPL", 0, 1, func(cpu *GbcCPU) { cpu.CPL() }},
-	&Instruction{0x30, "JR  NC,r8", 1, 0, func(cpu *GbcCPU) { cpu.JRcc_nn(C, false) }},
-	&Instruction{0x31, "LD SP, d16", 2, 3, func(cpu *GbcCPU) { cpu.LDSP_nn() }},
-	&Instruction{0x32, "LD  (HL-),A", 0, 2, func(cpu *GbcCPU) { cpu.LDDhl_r(&cpu.R.A) }},
-	&Instruction{0x33, "INC  SP", 0, 2, func(cpu *GbcCPU) { cpu.Inc_sp() }},
-	&Instruction{0x34, "INC  (HL)", 0, 3, func(cpu *GbcCPU) { cpu.Inc_hl() }},
-	&Instruction{0x35, "DEC  (HL)", 0, 3, func(cpu *GbcCPU) { cpu.Dec_hl() }},
-	&Instruction{0x36, "LD  (HL),d8", 1, 3, func(cpu *GbcCPU) { cpu.LDhl_n() }},
-	&Instruction{0x37, "SCF", 0, 1, func(cpu *GbcCPU) { cpu.SCF() }},
-	&Instruction{0x38, "JR  C,r8", 1, 0, func(cpu *GbcCPU) { cpu.JRcc_nn(C, true) }},
-	&Instruction{0x39, "ADD  HL,SP", 0, 2, func(cpu *GbcCPU) { cpu.Addhl_sp() }},
-	&Instruction{0x3A, "LD  A,(HL-)", 0, 2, func(cpu *GbcCPU) { cpu.LDDr_hl(&cpu.R.A) }},
-	&Instruction{0x3B, "DEC  SP", 0, 2, func(cpu *GbcCPU) { cpu.Dec_sp() }},
-	&Instruction{0x3C, "INC  A", 0, 1, func(cpu *GbcCPU) { cpu.Inc_r(&cpu.R.A) }},
-	&Instruction{0x3D, "DEC  A", 0, 1, func(cpu *GbcCPU) { cpu.Dec_r(&cpu.R.A) }},
-	&Instruction{0x3E, "LD  A,d8", 1, 2, func(cpu *GbcCPU) { cpu.LDrn(&cpu.R.A) }},
-	&Instruction{0x3F, "CCF", 0, 1, func(cpu *GbcCPU) { cpu.CCF() }},
-	&Instruction{0x40, "LD  B,B", 0, 1, func(cpu *GbcCPU) { cpu.LDrr(&cpu.R.B, &cpu.R.B) }},
-	&Instruction{0x41, "LD  B,C", 0, 1, func(cpu *GbcCPU) { cpu.LDrr(&cpu.R.B, &cpu.R.C) }},
-	&Instruction{0x42, "LD  B,D", 0, 1, func(cpu *GbcCPU) { cpu.LDrr(&cpu.R.B, &cpu.R.D) }},
-	&Instruction{0x43, "LD  B,E", 0, 1, func(cpu *GbcCPU) { cpu.LDrr(&cpu.R.B, &cpu.R.E) }},
-	&Instruction{0x44, "LD  B,H", 0, 1, func(cpu *GbcCPU) { cpu.LDrr(&cpu.R.B, &cpu.R.H) }},
-	&Instruction{0x45, "LD  B,L", 0, 1, func(cpu *GbcCPU) { cpu.LDrr(&cpu.R.B, &cpu.R.L) }},
-	&Instruction{0x46, "LD  B,(HL)", 0, 2, func(cpu *GbcCPU) { cpu.LDr_rr(&cpu.R.H, &cpu.R.L, &cpu.R.B) }},
-	&Instruction{0x47, "LD  B,A", 0, 1, func(cpu *GbcCPU) { cpu.LDrr(&cpu.R.B, &cpu.R.A) }},
-	&Instruction{0x48, "LD  C,B", 0, 1, func(cpu *GbcCPU) { cpu.LDrr(&cpu.R.C, &cpu.R.B) }},
-	&Instruction{0x49, "LD  C,C", 0, 1, func(cpu *GbcCPU) { cpu.LDrr(&cpu.R.C, &cpu.R.C) }},
-	&Instruction{0x4A, "LD  C,D", 0, 1, func(cpu *GbcCPU) { cpu.LDrr(&cpu.R.C, &cpu.R.D) }},
-	&Instruction{0x4B, "LD  C,E", 0, 1, func(cpu *GbcCPU) { cpu.LDrr(&cpu.R.C, &cpu.R.E) }},
-	&Instruction{0x4C, "LD  C,H", 0, 1, func(cpu *GbcCPU) { cpu.LDrr(&cpu.R.C, &cpu.R.H) }},
-	&Instruction{0x4D, "LD  C,L", 0, 1, func(cpu *GbcCPU) { cpu.LDrr(&cpu.R.C, &cpu.R.L) }},
-	&Instruction{0x4E, "LD  C,(HL)", 0, 2, func(cpu *GbcCPU) { cpu.LDr_rr(&cpu.R.H, &cpu.R.L, &cpu.R.C) }},
-	&Instruction{0x4F, "LD  C,A", 0, 1, func(cpu *GbcCPU) { cpu.LDrr(&cpu.R.C, &cpu.R.A) }},
-	&Instruction{0x50, "LD  D,B", 0, 1, func(cpu *GbcCPU) { cpu.LDrr(&cpu.R.D, &cpu.R.B) }},
-	&Instruction{0x51, "LD  D,C", 0, 1, func(cpu *GbcCPU) { cpu.LDrr(&cpu.R.D, &cpu.R.C) }},
-	&Instruction{0x52, "LD  D,D", 0, 1, func(cpu *GbcCPU) { cpu.LDrr(&cpu.R.D, &cpu.R.D) }},
-	&Instruction{0x53, "LD  D,E", 0, 1, func(cpu *GbcCPU) { cpu.LDrr(&cpu.R.D, &cpu.R.E) }},
-	&Instruction{0x54, "LD  D,H", 0, 1, func(cpu *GbcCPU) { cpu.LDrr(&cpu.R.D, &cpu.R.H) }},
-	&Instruction{0x55, "LD  D,L", 0, 1, func(cpu *GbcCPU) { cpu.LDrr(&cpu.R.D, &cpu.R.L) }},
-	&Instruction{0x56, "LD  D,(HL)", 0, 2, func(cpu *GbcCPU) { cpu.LDr_rr(&cpu.R.H, &cpu.R.L, &cpu.R.D) }},
-	&Instruction{0x57, "LD  D,A", 0, 1, func(cpu *GbcCPU) { cpu.LDrr(&cpu.R.D, &cpu.R.A) }},
-	&Instruction{0x58, "LD  E,B", 0, 1, func(cpu *GbcCPU) { cpu.LDrr(&cpu.R.E, &cpu.R.B) }},
-	&Instruction{0x59, "LD  E,C", 0, 1, func(cpu *GbcCPU) { cpu.LDrr(&cpu.R.E, &cpu.R.C) }},
-	&Instruction{0x5A, "LD  E,D", 0, 1, func(cpu *GbcCPU) { cpu.LDrr(&cpu.R.E, &cpu.R.D) }},
-	&Instruction{0x5B, "LD  E,E", 0, 1, func(cpu *GbcCPU) { cpu.LDrr(&cpu.R.E, &cpu.R.E) }},
-	&Instruction{0x5C, "LD  E,H", 0, 1, func(cpu *GbcCPU) { cpu.LDrr(&cpu.R.E, &cpu.R.H) }},
-	&Instruction{0x5D, "LD  E,L", 0, 1, func(cpu *GbcCPU) { cpu.LDrr(&cpu.R.E, &cpu.R.L) }},
-	&Instruction{0x5E, "LD  E,(HL)", 0, 2, func(cpu *GbcCPU) { cpu.LDr_rr(&cpu.R.H, &cpu.R.L, &cpu.R.E) }},
-	&Instruction{0x5F, "LD  E,A", 0, 1, func(cpu *GbcCPU) { cpu.LDrr(&cpu.R.E, &cpu.R.A) }},
-	&Instruction{0x60, "LD  H,B", 0, 1, func(cpu *GbcCPU) { cpu.LDrr(&cpu.R.H, &cpu.R.B) }},
-	&Instruction{0x61, "LD  H,C", 0, 1, func(cpu *GbcCPU) { cpu.LDrr(&cpu.R.H, &cpu.R.C) }},
-	&Instruction{0x62, "LD  H,D", 0, 1, func(cpu *GbcCPU) { cpu.LDrr(&cpu.R.H, &cpu.R.D) }},
-	&Instruction{0x63, "LD  H,E", 0, 1, func(cpu *GbcCPU) { cpu.LDrr(&cpu.R.H, &cpu.R.E) }},
-	&Instruction{0x64, "LD  H,H", 0, 1, func(cpu *GbcCPU) { cpu.LDrr(&cpu.R.H, &cpu.R.H) }},
-	&Instruction{0x65, "LD  H,L", 0, 1, func(cpu *GbcCPU) { cpu.LDrr(&cpu.R.H, &cpu.R.L) }},
-	&Instruction{0x66, "LD  H,(HL)", 0, 2, func(cpu *GbcCPU) { cpu.LDr_rr(&cpu.R.H, &cpu.R.L, &cpu.R.H) }},
-	&Instruction{0x67, "LD  H,A", 0, 1, func(cpu *GbcCPU) { cpu.LDrr(&cpu.R.H, &cpu.R.A) }},
-	&Instruction{0x68, "LD  L,B", 0, 1, func(cpu *GbcCPU) { cpu.LDrr(&cpu.R.L, &cpu.R.B) }},
-	&Instruction{0x69, "LD  L,C", 0, 1, func(cpu *GbcCPU) { cpu.LDrr(&cpu.R.L, &cpu.R.C) }},
-	&Instruction{0x6A, "LD  L,D", 0, 1, func(cpu *GbcCPU) { cpu.LDrr(&cpu.R.L, &cpu.R.D) }},
-	&Instruction{0x6B, "LD  L,E", 0, 1, func(cpu *GbcCPU) { cpu.LDrr(&cpu.R.L, &cpu.R.E) }},
-	&Instruction{0x6C, "LD  L,H", 0, 1, func(cpu *GbcCPU) { cpu.LDrr(&cpu.R.L, &cpu.R.H) }},
-	&Instruction{0x6D, "LD  L,L", 0, 1, func(cpu *GbcCPU) { cpu.LDrr(&cpu.R.L, &cpu.R.L) }},
-	&Instruction{0x6E, "LD  L,(HL)", 0, 2, func(cpu *GbcCPU) { cpu.LDr_rr(&cpu.R.H, &cpu.R.L, &cpu.R.L) }},
-	&Instruction{0x6F, "LD  L,A", 0, 1, func(cpu *GbcCPU) { cpu.LDrr(&cpu.R.L, &cpu.R.A) }},
-	&Instruction{0x70, "LD  (HL),B", 0, 2, func(cpu *GbcCPU) { cpu.LDrr_r(&cpu.R.H, &cpu.R.L, &cpu.R.B) }},
-	&Instruction{0x71, "LD  (HL),C", 0, 2, func(cpu *GbcCPU) { cpu.LDrr_r(&cpu.R.H, &cpu.R.L, &cpu.R.C) }},
-	&Instruction{0x72, "LD  (HL),D", 0, 2, func(cpu *GbcCPU) { cpu.LDrr_r(&cpu.R.H, &cpu.R.L, &cpu.R.D) }},
-	&Instruction{0x73, "LD  (HL),E", 0, 2, func(cpu *GbcCPU) { cpu.LDrr_r(&cpu.R.H, &cpu.R.L, &cpu.R.E) }},
-	&Instruction{0x74, "LD  (HL),H", 0, 2, func(cpu *GbcCPU) { cpu.LDrr_r(&cpu.R.H, &cpu.R.L, &cpu.R.H) }},
-	&Instruction{0x75, "LD  (HL),L", 0, 2, func(cpu *GbcCPU) { cpu.LDrr_r(&cpu.R.H, &cpu.R.L, &cpu.R.L) }},
-	&Instruction{0x76, "HALT", 0, 0, func(cpu *GbcCPU) { cpu.HALT() }},
-	&Instruction{0x77, "LD  (HL),A", 0, 2, func(cpu *GbcCPU) { cpu.LDrr_r(&cpu.R.H, &cpu.R.L, &cpu.R.A) }},
-	&Instruction{0x78, "LD  A,B", 0, 1, func(cpu *GbcCPU) { cpu.LDrr(&cpu.R.A, &cpu.R.B) }},
-	&Instruction{0x79, "LD  A,C", 0, 1, func(cpu *GbcCPU) { cpu.LDrr(&cpu.R.A, &cpu.R.C) }},
-	&Instruction{0x7A, "LD  A,D", 0, 1, func(cpu *GbcCPU) { cpu.LDrr(&cpu.R.A, &cpu.R.D) }},
-	&Instruction{0x7B, "LD  A,E", 0, 1, func(cpu *GbcCPU) { cpu.LDrr(&cpu.R.A, &cpu.R.E) }},
-	&Instruction{0x7C, "LD  A,H", 0, 1, func(cpu *GbcCPU) { cpu.LDrr(&cpu.R.A, &cpu.R.H) }},
-	&Instruction{0x7D, "LD  A,L", 0, 1, func(cpu *GbcCPU) { cpu.LDrr(&cpu.R.A, &cpu.R.L) }},
-	&Instruction{0x7E, "LD  A,(HL)", 0, 2, func(cpu *GbcCPU) { cpu.LDr_rr(&cpu.R.H, &cpu.R.L, &cpu.R.A) }},
-	&Instruction{0x7F, "LD  A,A", 0, 1, func(cpu *GbcCPU) { cpu.LDrr(&cpu.R.A, &cpu.R.A) }},
-	&Instruction{0x80, "ADD  A,B", 0, 1, func(cpu *GbcCPU) { cpu.AddA_r(&cpu.R.B) }},
-	&Instruction{0x81, "ADD  A,C", 0, 1, func(cpu *GbcCPU) { cpu.AddA_r(&cpu.R.C) }},
-	&Instruction{0x82, "ADD  A,D", 0, 1, func(cpu *GbcCPU) { cpu.AddA_r(&cpu.R.D) }},
-	&Instruction{0x83, "ADD  A,E", 0, 1, func(cpu *GbcCPU) { cpu.AddA_r(&cpu.R.E) }},
-	&Instruction{0x84, "ADD  A,H", 0, 1, func(cpu *GbcCPU) { cpu.AddA_r(&cpu.R.H) }},
-	&Instruction{0x85, "ADD  A,L", 0, 1, func(cpu *GbcCPU) { cpu.AddA_r(&cpu.R.L) }},
-	&Instruction{0x86, "ADD  A,(HL)", 0, 2, func(cpu *GbcCPU) { cpu.AddA_hl() }},
-	&Instruction{0x87, "ADD  A,A", 0, 1, func(cpu *GbcCPU) { cpu.AddA_r(&cpu.R.A) }},
-	&Instruction{0x88, "ADC  A,B", 0, 1, func(cpu *GbcCPU) { cpu.AddCA_r(&cpu.R.B) }},
-	&Instruction{0x89, "ADC  A,C", 0, 1, func(cpu *GbcCPU) { cpu.AddCA_r(&cpu.R.C) }},
-	&Instruction{0x8A, "ADC  A,D", 0, 1, func(cpu *GbcCPU) { cpu.AddCA_r(&cpu.R.D) }},
-	&Instruction{0x8B, "ADC  A,E", 0, 1, func(cpu *GbcCPU) { cpu.AddCA_r(&cpu.R.E) }},
-	&Instruction{0x8C, "ADC  A,H", 0, 1, func(cpu *GbcCPU) { cpu.AddCA_r(&cpu.R.H) }},
-	&Instruction{0x8D, "ADC  A,L", 0, 1, func(cpu *GbcCPU) { cpu.AddCA_r(&cpu.R.L) }},
-	&Instruction{0x8E, "ADC  A,(HL)", 0, 2, func(cpu *GbcCPU) { cpu.AddCA_hl() }},
-	&Instruction{0x8F, "ADC  A,A", 0, 1, func(cpu *GbcCPU) { cpu.AddCA_r(&cpu.R.A) }},
-	&Instruction{0x90, "SUB  B", 0, 1, func(cpu *GbcCPU) { cpu.SubA_r(&cpu.R.B) }},
-	&Instruction{0x91, "SUB  C", 0, 1, func(cpu *GbcCPU) { cpu.SubA_r(&cpu.R.C) }},
-	&Instruction{0x92, "SUB  D", 0, 1, func(cpu *GbcCPU) { cpu.SubA_r(&cpu.R.D) }},
-	&Instruction{0x93, "SUB  E", 0, 1, func(cpu *GbcCPU) { cpu.SubA_r(&cpu.R.E) }},
-	&Instruction{0x94, "SUB  H", 0, 1, func(cpu *GbcCPU) { cpu.SubA_r(&cpu.R.H) }},
-	&Instruction{0x95, "SUB  L", 0, 1, func(cpu *GbcCPU) { cpu.SubA_r(&cpu.R.L) }},
-	&Instruction{0x96, "SUB  (HL)", 0, 2, func(cpu *GbcCPU) { cpu.SubA_hl() }},
-	&Instruction{0x97, "SUB  A", 0, 1, func(cpu *GbcCPU) { cpu.SubA_r(&cpu.R.A) }},
-	&Instruction{0x98, "SBC  A,B", 0, 1, func(cpu *GbcCPU) { cpu.SubAC_r(&cpu.R.B) }},
-	&Instruction{0x99, "SBC  A,C", 0, 1, func(cpu *GbcCPU) { cpu.SubAC_r(&cpu.R.C) }},
-	&Instruction{0x9A, "SBC  A,D", 0, 1, func(cpu *GbcCPU) { cpu.SubAC_r(&cpu.R.D) }},
-	&Instruction{0x9B, "SBC  A,E", 0, 1, func(cpu *GbcCPU) { cpu.SubAC_r(&cpu.R.E) }},
-	&Instruction{0x9C, "SBC  A,H", 0, 1, func(cpu *GbcCPU) { cpu.SubAC_r(&cpu.R.H) }},
-	&Instruction{0x9D, "SBC  A,L", 0, 1, func(cpu *GbcCPU) { cpu.SubAC_r(&cpu.R.L) }},
-	&Instruction{0x9E, "SBC  A,(HL)", 0, 2, func(cpu *GbcCPU) { cpu.SubAC_hl() }},
-	&Instruction{0x9F, "SBC  A,A", 0, 1, func(cpu *GbcCPU) { cpu.SubAC_r(&cpu.R.A) }},
-	&Instruction{0xA0, "AND  B", 0, 1, func(cpu *GbcCPU) { cpu.AndA_r(&cpu.R.B) }},
-	&Instruction{0xA1, "AND  C", 0, 1, func(cpu *GbcCPU) { cpu.AndA_r(&cpu.R.C) }},
-	&Instruction{0xA2, "AND  D", 0, 1, func(cpu *GbcCPU) { cpu.AndA_r(&cpu.R.D) }},
-	&Instruction{0xA3, "AND  E", 0, 1, func(cpu *GbcCPU) { cpu.AndA_r(&cpu.R.E) }},
-	&Instruction{0xA4, "AND  H", 0, 1, func(cpu *GbcCPU) { cpu.AndA_r(&cpu.R.H) }},
-	&Instruction{0xA5, "AND  L", 0, 1, func(cpu *GbcCPU) { cpu.AndA_r(&cpu.R.L) }},
-	&Instruction{0xA6, "AND  (HL)", 0, 2, func(cpu *GbcCPU) { cpu.AndA_hl() }},
-	&Instruction{0xA7, "AND  A", 0, 1, func(cpu *GbcCPU) { cpu.AndA_r(&cpu.R.A) }},
-	&Instruction{0xA8, "XOR  B", 0, 1, func(cpu *GbcCPU) { cpu.XorA_r(&cpu.R.B) }},
-	&Instruction{0xA9, "XOR  C", 0, 1, func(cpu *GbcCPU) { cpu.XorA_r(&cpu.R.C) }},
-	&Instruction{0xAA, "XOR  D", 0, 1, func(cpu *GbcCPU) { cpu.XorA_r(&cpu.R.D) }},
-	&Instruction{0xAB, "XOR  E", 0, 1, func(cpu *GbcCPU) { cpu.XorA_r(&cpu.R.E) }},
-	&Instruction{0xAC, "XOR  H", 0, 1, func(cpu *GbcCPU) { cpu.XorA_r(&cpu.R.H) }},
-	&Instruction{0xAD, "XOR  L", 0, 1, func(cpu *GbcCPU) { cpu.XorA_r(&cpu.R.L) }},
-	&Instruction{0xAE, "XOR  (HL)", 0, 2, func(cpu *GbcCPU) { cpu.XorA_hl() }},
-	&Instruction{0xAF, "XOR  A", 0, 1, func(cpu *GbcCPU) { cpu.XorA_r(&cpu.R.A) }},
-	&Instruction{0xB0, "OR  B", 0, 1, func(cpu *GbcCPU) { cpu.OrA_r(&cpu.R.B) }},
-	&Instruction{0xB1, "OR  C", 0, 1, func(cpu *GbcCPU) { cpu.OrA_r(&cpu.R.C) }},
-	&Instruction{0xB2, "OR  D", 0, 1, func(cpu *GbcCPU) { cpu.OrA_r(&cpu.R.D) }},
-	&Instruction{0xB3, "OR  E", 0, 1, func(cpu *GbcCPU) { cpu.OrA_r(&cpu.R.E) }},
-	&Instruction{0xB4, "OR  H", 0, 1, func(cpu *GbcCPU) { cpu.OrA_r(&cpu.R.H) }},
-	&Instruction{0xB5, "OR  L", 0, 1, func(cpu *GbcCPU) { cpu.OrA_r(&cpu.R.L) }},
-	&Instruction{0xB6, "OR  (HL)", 0, 2, func(cpu *GbcCPU) { cpu.OrA_hl() }},
-	&Instruction{0xB7, "OR  A", 0, 1, func(cpu *GbcCPU) { cpu.OrA_r(&cpu.R.A) }},
-	&Instruction{0xB8, "CP  B", 0, 1, func(cpu *GbcCPU) { cpu.CPA_r(&cpu.R.B) }},
-	&Instruction{0xB9, "CP  C", 0, 1, func(cpu *GbcCPU) { cpu.CPA_r(&cpu.R.C) }},
-	&Instruction{0xBA, "CP  D", 0, 1, func(cpu *GbcCPU) { cpu.CPA_r(&cpu.R.D) }},
-	&Instruction{0xBB, "CP  E", 0, 1, func(cpu *GbcCPU) { cpu.CPA_r(&cpu.R.E) }},
-	&Instruction{0xBC, "CP  H", 0, 1, func(cpu *GbcCPU) { cpu.CPA_r(&cpu.R.H) }},
-	&Instruction{0xBD, "CP  L", 0, 1, func(cpu *GbcCPU) { cpu.CPA_r(&cpu.R.L) }},
-	&Instruction{0xBE, "CP  (HL)", 0, 2, func(cpu *GbcCPU) { cpu.CPA_hl() }},
-	&Instruction{0xBF, "CP  A", 0, 1, func(cpu *GbcCPU) { cpu.CPA_r(&cpu.R.A) }},
-	&Instruction{0xC0, "RET NZ", 0, 0, func(cpu *GbcCPU) { cpu.Retcc(Z, false) }},
-	&Instruction{0xC1, "POP  BC", 0, 3, func(cpu *GbcCPU) { cpu.Pop_nn(&cpu.R.B, &cpu.R.C) }},
-	&Instruction{0xC2, "JP  NZ,a16", 2, 0, func(cpu *GbcCPU) { cpu.JPcc_nn(Z, false) }},
-	&Instruction{0xC3, "JP  a16", 2, 4, func(cpu *GbcCPU) { cpu.JP_nn() }},
-	&Instruction{0xC4, "CALL  NZ,a16", 2, 0, func(cpu *GbcCPU) { cpu.Callcc_nn(Z, false) }},
-	&Instruction{0xC5, "PUSH BC", 0, 4, func(cpu *GbcCPU) { cpu.Push_nn(&cpu.R.B, &cpu.R.C) }},
-	&Instruction{0xC6, "ADD  A,d8", 1, 2, func(cpu *GbcCPU) { cpu.AddA_n() }},
-	&Instruction{0xC7, "RST n", 0, 4, func(cpu *GbcCPU) { cpu.Rst(0x00) }},
-	&Instruction{0xC8, "RET Z", 0, 0, func(cpu *GbcCPU) { cpu.Retcc(Z, true) }},
-	&Instruction{0xC9, "RET", 0, 4, func(cpu *GbcCPU) { cpu.Ret() }},
-	&Instruction{0xCA, "JP  Z,a16", 2, 0, func(cpu *GbcCPU) { cpu.JPcc_nn(Z, true) }},
+	&Instruction{0x0, "NOP", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.NOP() }},
+	&Instruction{0x1, "LD  BC,d16", 2, 3, func(cpu *GbcCPU, operands [2]byte) { cpu.LDn_nn(&cpu.R.B, &cpu.R.C, operands[0], operands[1]) }},
+	&Instruction{0x2, "LD  (BC),A", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.LDrr_r(&cpu.R.B, &cpu.R.C, &cpu.R.A) }},
+	&Instruction{0x3, "INC  BC", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Inc_rr(&cpu.R.B, &cpu.R.C) }},
+	&Instruction{0x4, "INC  B", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.Inc_r(&cpu.R.B) }},
+	&Instruction{0x5, "DEC  B", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.Dec_r(&cpu.R.B) }},
+	&Instruction{0x6, "LD  B,n", 1, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.LDrn(&cpu.R.B, operands[0]) }},
+	&Instruction{0x7, "RLCA", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.RLCA() }},
+	&Instruction{0x8, "LD  (a16),SP", 2, 5, func(cpu *GbcCPU, operands [2]byte) { cpu.LDnn_SP(operands[0], operands[1]) }},
+	&Instruction{0x9, "ADD  HL,BC", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Addhl_rr(&cpu.R.B, &cpu.R.C) }},
+	&Instruction{0xA, "LD  A,(BC)", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.LDr_rr(&cpu.R.B, &cpu.R.C, &cpu.R.A) }},
+	&Instruction{0xB, "DEC  BC", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Dec_rr(&cpu.R.B, &cpu.R.C) }},
+	&Instruction{0xC, "INC  C", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.Inc_r(&cpu.R.C) }},
+	&Instruction{0xD, "DEC  C", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.Dec_r(&cpu.R.C) }},
+	&Instruction{0xE, "LD  C,n", 1, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.LDrn(&cpu.R.C, operands[0]) }},
+	&Instruction{0xF, "RRCA", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.RRCA() }},
+	&Instruction{0x10, "STOP", 1, 0, func(cpu *GbcCPU, operands [2]byte) { cpu.Stop() }},
+	&Instruction{0x11, "LD  DE,d16", 2, 3, func(cpu *GbcCPU, operands [2]byte) { cpu.LDn_nn(&cpu.R.D, &cpu.R.E, operands[0], operands[1]) }},
+	&Instruction{0x12, "LD  (DE),A", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.LDrr_r(&cpu.R.D, &cpu.R.E, &cpu.R.A) }},
+	&Instruction{0x13, "INC  DE", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Inc_rr(&cpu.R.D, &cpu.R.E) }},
+	&Instruction{0x14, "INC  D", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.Inc_r(&cpu.R.D) }},
+	&Instruction{0x15, "DEC  D", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.Dec_r(&cpu.R.D) }},
+	&Instruction{0x16, "LD  D,n", 1, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.LDrn(&cpu.R.D, operands[0]) }},
+	&Instruction{0x17, "RLA", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.RLA() }},
+	&Instruction{0x18, "JR  r8", 1, 3, func(cpu *GbcCPU, operands [2]byte) { cpu.JR_n(operands[0]) }},
+	&Instruction{0x19, "ADD  HL,DE", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Addhl_rr(&cpu.R.D, &cpu.R.E) }},
+	&Instruction{0x1A, "LD  A,(DE)", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.LDr_rr(&cpu.R.D, &cpu.R.E, &cpu.R.A) }},
+	&Instruction{0x1B, "DEC  DE", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Dec_rr(&cpu.R.D, &cpu.R.E) }},
+	&Instruction{0x1C, "INC  E", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.Inc_r(&cpu.R.E) }},
+	&Instruction{0x1D, "DEC  E", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.Dec_r(&cpu.R.E) }},
+	&Instruction{0x1E, "LD E,n", 1, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.LDrn(&cpu.R.E, operands[0]) }},
+	&Instruction{0x1F, "RRA", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.RRA() }},
+	&Instruction{0x20, "JR  NZ,r8", 1, 0, func(cpu *GbcCPU, operands [2]byte) { cpu.JRcc_nn(Z, false, operands[0]) }},
+	&Instruction{0x21, "LD  HL,d16", 2, 3, func(cpu *GbcCPU, operands [2]byte) { cpu.LDn_nn(&cpu.R.H, &cpu.R.L, operands[0], operands[1]) }},
+	&Instruction{0x22, "LD  (HL+),A", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.LDIhl_r(&cpu.R.A) }},
+	&Instruction{0x23, "INC  HL", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Inc_rr(&cpu.R.H, &cpu.R.L) }},
+	&Instruction{0x24, "INC  H", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.Inc_r(&cpu.R.H) }},
+	&Instruction{0x25, "DEC  H", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.Dec_r(&cpu.R.H) }},
+	&Instruction{0x26, "LD H,n", 1, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.LDrn(&cpu.R.H, operands[0]) }},
+	&Instruction{0x27, "DAA", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.Daa() }},
+	&Instruction{0x28, "JR  Z,r8", 1, 0, func(cpu *GbcCPU, operands [2]byte) { cpu.JRcc_nn(Z, true, operands[0]) }},
+	&Instruction{0x29, "ADD  HL,HL", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Addhl_rr(&cpu.R.H, &cpu.R.L) }},
+	&Instruction{0x2A, "LD  A,(HL+)", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.LDIr_hl(&cpu.R.A) }},
+	&Instruction{0x2B, "DEC  HL", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Dec_rr(&cpu.R.H, &cpu.R.L) }},
+	&Instruction{0x2C, "INC  L", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.Inc_r(&cpu.R.L) }},
+	&Instruction{0x2D, "DEC  L", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.Dec_r(&cpu.R.L) }},
+	&Instruction{0x2E, "LD L,n", 1, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.LDrn(&cpu.R.L, operands[0]) }},
+	&Instruction{0x2F, "CPL", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.CPL() }},
+	&Instruction{0x30, "JR  NC,r8", 1, 0, func(cpu *GbcCPU, operands [2]byte) { cpu.JRcc_nn(C, false, operands[0]) }},
+	&Instruction{0x31, "LD  SP,d16", 2, 3, func(cpu *GbcCPU, operands [2]byte) { cpu.LDSP_nn(operands[0], operands[1]) }},
+	&Instruction{0x32, "LD  (HL-),A", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.LDDhl_r(&cpu.R.A) }},
+	&Instruction{0x33, "INC  SP", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Inc_sp() }},
+	&Instruction{0x34, "INC  (HL)", 0, 3, func(cpu *GbcCPU, operands [2]byte) { cpu.Inc_hl() }},
+	&Instruction{0x35, "DEC  (HL)", 0, 3, func(cpu *GbcCPU, operands [2]byte) { cpu.Dec_hl() }},
+	&Instruction{0x36, "LD  (HL),d8", 1, 3, func(cpu *GbcCPU, operands [2]byte) { cpu.LDhl_n(operands[0]) }},
+	&Instruction{0x37, "SCF", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.SCF() }},
+	&Instruction{0x38, "JR  C,r8", 1, 0, func(cpu *GbcCPU, operands [2]byte) { cpu.JRcc_nn(C, true, operands[0]) }},
+	&Instruction{0x39, "ADD  HL,SP", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Addhl_sp() }},
+	&Instruction{0x3A, "LD  A,(HL-)", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.LDDr_hl(&cpu.R.A) }},
+	&Instruction{0x3B, "DEC  SP", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.Dec_sp() }},
+	&Instruction{0x3C, "INC  A", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.Inc_r(&cpu.R.A) }},
+	&Instruction{0x3D, "DEC  A", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.Dec_r(&cpu.R.A) }},
+	&Instruction{0x3E, "LD  A,d8", 1, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.LDrn(&cpu.R.A, operands[0]) }},
+	&Instruction{0x3F, "CCF", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.CCF() }},
+	&Instruction{0x40, "LD  B,B", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.LDrr(&cpu.R.B, &cpu.R.B) }},
+	&Instruction{0x41, "LD  B,C", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.LDrr(&cpu.R.B, &cpu.R.C) }},
+	&Instruction{0x42, "LD  B,D", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.LDrr(&cpu.R.B, &cpu.R.D) }},
+	&Instruction{0x43, "LD  B,E", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.LDrr(&cpu.R.B, &cpu.R.E) }},
+	&Instruction{0x44, "LD  B,H", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.LDrr(&cpu.R.B, &cpu.R.H) }},
+	&Instruction{0x45, "LD  B,L", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.LDrr(&cpu.R.B, &cpu.R.L) }},
+	&Instruction{0x46, "LD  B,(HL)", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.LDr_rr(&cpu.R.H, &cpu.R.L, &cpu.R.B) }},
+	&Instruction{0x47, "LD  B,A", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.LDrr(&cpu.R.B, &cpu.R.A) }},
+	&Instruction{0x48, "LD  C,B", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.LDrr(&cpu.R.C, &cpu.R.B) }},
+	&Instruction{0x49, "LD  C,C", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.LDrr(&cpu.R.C, &cpu.R.C) }},
+	&Instruction{0x4A, "LD  C,D", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.LDrr(&cpu.R.C, &cpu.R.D) }},
+	&Instruction{0x4B, "LD  C,E", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.LDrr(&cpu.R.C, &cpu.R.E) }},
+	&Instruction{0x4C, "LD  C,H", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.LDrr(&cpu.R.C, &cpu.R.H) }},
+	&Instruction{0x4D, "LD  C,L", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.LDrr(&cpu.R.C, &cpu.R.L) }},
+	&Instruction{0x4E, "LD  C,(HL)", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.LDr_rr(&cpu.R.H, &cpu.R.L, &cpu.R.C) }},
+	&Instruction{0x4F, "LD  C,A", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.LDrr(&cpu.R.C, &cpu.R.A) }},
+	&Instruction{0x50, "LD  D,B", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.LDrr(&cpu.R.D, &cpu.R.B) }},
+	&Instruction{0x51, "LD  D,C", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.LDrr(&cpu.R.D, &cpu.R.C) }},
+	&Instruction{0x52, "LD  D,D", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.LDrr(&cpu.R.D, &cpu.R.D) }},
+	&Instruction{0x53, "LD  D,E", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.LDrr(&cpu.R.D, &cpu.R.E) }},
+	&Instruction{0x54, "LD  D,H", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.LDrr(&cpu.R.D, &cpu.R.H) }},
+	&Instruction{0x55, "LD  D,L", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.LDrr(&cpu.R.D, &cpu.R.L) }},
+	&Instruction{0x56, "LD  D,(HL)", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.LDr_rr(&cpu.R.H, &cpu.R.L, &cpu.R.D) }},
+	&Instruction{0x57, "LD  D,A", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.LDrr(&cpu.R.D, &cpu.R.A) }},
+	&Instruction{0x58, "LD  E,B", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.LDrr(&cpu.R.E, &cpu.R.B) }},
+	&Instruction{0x59, "LD  E,C", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.LDrr(&cpu.R.E, &cpu.R.C) }},
+	&Instruction{0x5A, "LD  E,D", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.LDrr(&cpu.R.E, &cpu.R.D) }},
+	&Instruction{0x5B, "LD  E,E", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.LDrr(&cpu.R.E, &cpu.R.E) }},
+	&Instruction{0x5C, "LD  E,H", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.LDrr(&cpu.R.E, &cpu.R.H) }},
+	&Instruction{0x5D, "LD  E,L", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.LDrr(&cpu.R.E, &cpu.R.L) }},
+	&Instruction{0x5E, "LD  E,(HL)", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.LDr_rr(&cpu.R.H, &cpu.R.L, &cpu.R.E) }},
+	&Instruction{0x5F, "LD  E,A", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.LDrr(&cpu.R.E, &cpu.R.A) }},
+	&Instruction{0x60, "LD  H,B", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.LDrr(&cpu.R.H, &cpu.R.B) }},
+	&Instruction{0x61, "LD  H,C", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.LDrr(&cpu.R.H, &cpu.R.C) }},
+	&Instruction{0x62, "LD  H,D", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.LDrr(&cpu.R.H, &cpu.R.D) }},
+	&Instruction{0x63, "LD  H,E", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.LDrr(&cpu.R.H, &cpu.R.E) }},
+	&Instruction{0x64, "LD  H,H", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.LDrr(&cpu.R.H, &cpu.R.H) }},
+	&Instruction{0x65, "LD  H,L", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.LDrr(&cpu.R.H, &cpu.R.L) }},
+	&Instruction{0x66, "LD  H,(HL)", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.LDr_rr(&cpu.R.H, &cpu.R.L, &cpu.R.H) }},
+	&Instruction{0x67, "LD  H,A", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.LDrr(&cpu.R.H, &cpu.R.A) }},
+	&Instruction{0x68, "LD  L,B", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.LDrr(&cpu.R.L, &cpu.R.B) }},
+	&Instruction{0x69, "LD  L,C", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.LDrr(&cpu.R.L, &cpu.R.C) }},
+	&Instruction{0x6A, "LD  L,D", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.LDrr(&cpu.R.L, &cpu.R.D) }},
+	&Instruction{0x6B, "LD  L,E", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.LDrr(&cpu.R.L, &cpu.R.E) }},
+	&Instruction{0x6C, "LD  L,H", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.LDrr(&cpu.R.L, &cpu.R.H) }},
+	&Instruction{0x6D, "LD  L,L", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.LDrr(&cpu.R.L, &cpu.R.L) }},
+	&Instruction{0x6E, "LD  L,(HL)", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.LDr_rr(&cpu.R.H, &cpu.R.L, &cpu.R.L) }},
+	&Instruction{0x6F, "LD  L,A", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.LDrr(&cpu.R.L, &cpu.R.A) }},
+	&Instruction{0x70, "LD  (HL),B", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.LDrr_r(&cpu.R.H, &cpu.R.L, &cpu.R.B) }},
+	&Instruction{0x71, "LD  (HL),C", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.LDrr_r(&cpu.R.H, &cpu.R.L, &cpu.R.C) }},
+	&Instruction{0x72, "LD  (HL),D", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.LDrr_r(&cpu.R.H, &cpu.R.L, &cpu.R.D) }},
+	&Instruction{0x73, "LD  (HL),E", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.LDrr_r(&cpu.R.H, &cpu.R.L, &cpu.R.E) }},
+	&Instruction{0x74, "LD  (HL),H", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.LDrr_r(&cpu.R.H, &cpu.R.L, &cpu.R.H) }},
+	&Instruction{0x75, "LD  (HL),L", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.LDrr_r(&cpu.R.H, &cpu.R.L, &cpu.R.L) }},
+	&Instruction{0x76, "HALT", 0, 0, func(cpu *GbcCPU, operands [2]byte) { cpu.HALT() }},
+	&Instruction{0x77, "LD  (HL),A", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.LDrr_r(&cpu.R.H, &cpu.R.L, &cpu.R.A) }},
+	&Instruction{0x78, "LD  A,B", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.LDrr(&cpu.R.A, &cpu.R.B) }},
+	&Instruction{0x79, "LD  A,C", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.LDrr(&cpu.R.A, &cpu.R.C) }},
+	&Instruction{0x7A, "LD  A,D", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.LDrr(&cpu.R.A, &cpu.R.D) }},
+	&Instruction{0x7B, "LD  A,E", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.LDrr(&cpu.R.A, &cpu.R.E) }},
+	&Instruction{0x7C, "LD  A,H", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.LDrr(&cpu.R.A, &cpu.R.H) }},
+	&Instruction{0x7D, "LD  A,L", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.LDrr(&cpu.R.A, &cpu.R.L) }},
+	&Instruction{0x7E, "LD  A,(HL)", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.LDr_rr(&cpu.R.H, &cpu.R.L, &cpu.R.A) }},
+	&Instruction{0x7F, "LD  A,A", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.LDrr(&cpu.R.A, &cpu.R.A) }},
+	&Instruction{0x80, "ADD  A,B", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.AddA_r(&cpu.R.B) }},
+	&Instruction{0x81, "ADD  A,C", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.AddA_r(&cpu.R.C) }},
+	&Instruction{0x82, "ADD  A,D", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.AddA_r(&cpu.R.D) }},
+	&Instruction{0x83, "ADD  A,E", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.AddA_r(&cpu.R.E) }},
+	&Instruction{0x84, "ADD  A,H", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.AddA_r(&cpu.R.H) }},
+	&Instruction{0x85, "ADD  A,L", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.AddA_r(&cpu.R.L) }},
+	&Instruction{0x86, "ADD  A,(HL)", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.AddA_hl() }},
+	&Instruction{0x87, "ADD  A,A", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.AddA_r(&cpu.R.A) }},
+	&Instruction{0x88, "ADC  A,B", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.AddCA_r(&cpu.R.B) }},
+	&Instruction{0x89, "ADC  A,C", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.AddCA_r(&cpu.R.C) }},
+	&Instruction{0x8A, "ADC  A,D", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.AddCA_r(&cpu.R.D) }},
+	&Instruction{0x8B, "ADC  A,E", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.AddCA_r(&cpu.R.E) }},
+	&Instruction{0x8C, "ADC  A,H", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.AddCA_r(&cpu.R.H) }},
+	&Instruction{0x8D, "ADC  A,L", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.AddCA_r(&cpu.R.L) }},
+	&Instruction{0x8E, "ADC  A,(HL)", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.AddCA_hl() }},
+	&Instruction{0x8F, "ADC  A,A", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.AddCA_r(&cpu.R.A) }},
+	&Instruction{0x90, "SUB  B", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.SubA_r(&cpu.R.B) }},
+	&Instruction{0x91, "SUB  C", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.SubA_r(&cpu.R.C) }},
+	&Instruction{0x92, "SUB  D", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.SubA_r(&cpu.R.D) }},
+	&Instruction{0x93, "SUB  E", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.SubA_r(&cpu.R.E) }},
+	&Instruction{0x94, "SUB  H", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.SubA_r(&cpu.R.H) }},
+	&Instruction{0x95, "SUB  L", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.SubA_r(&cpu.R.L) }},
+	&Instruction{0x96, "SUB  (HL)", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.SubA_hl() }},
+	&Instruction{0x97, "SUB  A", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.SubA_r(&cpu.R.A) }},
+	&Instruction{0x98, "SBC  A,B", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.SubAC_r(&cpu.R.B) }},
+	&Instruction{0x99, "SBC  A,C", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.SubAC_r(&cpu.R.C) }},
+	&Instruction{0x9A, "SBC  A,D", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.SubAC_r(&cpu.R.D) }},
+	&Instruction{0x9B, "SBC  A,E", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.SubAC_r(&cpu.R.E) }},
+	&Instruction{0x9C, "SBC  A,H", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.SubAC_r(&cpu.R.H) }},
+	&Instruction{0x9D, "SBC  A,L", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.SubAC_r(&cpu.R.L) }},
+	&Instruction{0x9E, "SBC  A,(HL)", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.SubAC_hl() }},
+	&Instruction{0x9F, "SBC  A,A", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.SubAC_r(&cpu.R.A) }},
+	&Instruction{0xA0, "AND  B", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.AndA_r(&cpu.R.B) }},
+	&Instruction{0xA1, "AND  C", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.AndA_r(&cpu.R.C) }},
+	&Instruction{0xA2, "AND  D", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.AndA_r(&cpu.R.D) }},
+	&Instruction{0xA3, "AND  E", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.AndA_r(&cpu.R.E) }},
+	&Instruction{0xA4, "AND  H", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.AndA_r(&cpu.R.H) }},
+	&Instruction{0xA5, "AND  L", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.AndA_r(&cpu.R.L) }},
+	&Instruction{0xA6, "AND  (HL)", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.AndA_hl() }},
+	&Instruction{0xA7, "AND  A", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.AndA_r(&cpu.R.A) }},
+	&Instruction{0xA8, "XOR  B", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.XorA_r(&cpu.R.B) }},
+	&Instruction{0xA9, "XOR  C", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.XorA_r(&cpu.R.C) }},
+	&Instruction{0xAA, "XOR  D", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.XorA_r(&cpu.R.D) }},
+	&Instruction{0xAB, "XOR  E", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.XorA_r(&cpu.R.E) }},
+	&Instruction{0xAC, "XOR  H", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.XorA_r(&cpu.R.H) }},
+	&Instruction{0xAD, "XOR  L", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.XorA_r(&cpu.R.L) }},
+	&Instruction{0xAE, "XOR  (HL)", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.XorA_hl() }},
+	&Instruction{0xAF, "XOR  A", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.XorA_r(&cpu.R.A) }},
+	&Instruction{0xB0, "OR  B", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.OrA_r(&cpu.R.B) }},
+	&Instruction{0xB1, "OR  C", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.OrA_r(&cpu.R.C) }},
+	&Instruction{0xB2, "OR  D", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.OrA_r(&cpu.R.D) }},
+	&Instruction{0xB3, "OR  E", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.OrA_r(&cpu.R.E) }},
+	&Instruction{0xB4, "OR  H", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.OrA_r(&cpu.R.H) }},
+	&Instruction{0xB5, "OR  L", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.OrA_r(&cpu.R.L) }},
+	&Instruction{0xB6, "OR  (HL)", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.OrA_hl() }},
+	&Instruction{0xB7, "OR  A", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.OrA_r(&cpu.R.A) }},
+	&Instruction{0xB8, "CP  B", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.CPA_r(&cpu.R.B) }},
+	&Instruction{0xB9, "CP  C", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.CPA_r(&cpu.R.C) }},
+	&Instruction{0xBA, "CP  D", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.CPA_r(&cpu.R.D) }},
+	&Instruction{0xBB, "CP  E", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.CPA_r(&cpu.R.E) }},
+	&Instruction{0xBC, "CP  H", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.CPA_r(&cpu.R.H) }},
+	&Instruction{0xBD, "CP  L", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.CPA_r(&cpu.R.L) }},
+	&Instruction{0xBE, "CP  (HL)", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.CPA_hl() }},
+	&Instruction{0xBF, "CP  A", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.CPA_r(&cpu.R.A) }},
+	&Instruction{0xC0, "RET NZ", 0, 0, func(cpu *GbcCPU, operands [2]byte) { cpu.Retcc(Z, false) }},
+	&Instruction{0xC1, "POP  BC", 0, 3, func(cpu *GbcCPU, operands [2]byte) { cpu.Pop_nn(&cpu.R.B, &cpu.R.C) }},
+	&Instruction{0xC2, "JP  NZ,a16", 2, 0, func(cpu *GbcCPU, operands [2]byte) { cpu.JPcc_nn(Z, false, operands[0], operands[1]) }},
+	&Instruction{0xC3, "JP  a16", 2, 4, func(cpu *GbcCPU, operands [2]byte) { cpu.JP_nn(operands[0], operands[1]) }},
+	&Instruction{0xC4, "CALL  NZ,a16", 2, 0, func(cpu *GbcCPU, operands [2]byte) { cpu.Callcc_nn(Z, false, operands[0], operands[1]) }},
+	&Instruction{0xC5, "PUSH BC", 0, 4, func(cpu *GbcCPU, operands [2]byte) { cpu.Push_nn(&cpu.R.B, &cpu.R.C) }},
+	&Instruction{0xC6, "ADD  A,d8", 1, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.AddA_n(operands[0]) }},
+	&Instruction{0xC7, "RST  0x00", 0, 4, func(cpu *GbcCPU, operands [2]byte) { cpu.Rst(0x00) }},
+	&Instruction{0xC8, "RET Z", 0, 0, func(cpu *GbcCPU, operands [2]byte) { cpu.Retcc(Z, true) }},
+	&Instruction{0xC9, "RET", 0, 4, func(cpu *GbcCPU, operands [2]byte) { cpu.Ret() }},
+	&Instruction{0xCA, "JP  Z,a16", 2, 0, func(cpu *GbcCPU, operands [2]byte) { cpu.JPcc_nn(Z, true, operands[0], operands[1]) }},
 	EMPTY_INSTRUCTION,
-	&Instruction{0xCC, "CALL  Z,a16", 2, 0, func(cpu *GbcCPU) { cpu.Callcc_nn(Z, true) }},
-	&Instruction{0xCD, "CALL a16", 2, 6, func(cpu *GbcCPU) { cpu.Call_nn() }},
-	&Instruction{0xCE, "ADC  A,d8", 1, 2, func(cpu *GbcCPU) { cpu.AddCA_n() }},
-	&Instruction{0xCF, "RST n", 0, 4, func(cpu *GbcCPU) { cpu.Rst(0x08) }},
-	&Instruction{0xD0, "RET NC", 0, 0, func(cpu *GbcCPU) { cpu.Retcc(C, false) }},
-	&Instruction{0xD1, "POP  DE", 0, 3, func(cpu *GbcCPU) { cpu.Pop_nn(&cpu.R.D, &cpu.R.E) }},
-	&Instruction{0xD2, "JP  NC,a16", 2, 0, func(cpu *GbcCPU) { cpu.JPcc_nn(C, false) }},
+	&Instruction{0xCC, "CALL  Z,a16", 2, 0, func(cpu *GbcCPU, operands [2]byte) { cpu.Callcc_nn(Z, true, operands[0], operands[1]) }},
+	&Instruction{0xCD, "CALL a16", 2, 6, func(cpu *GbcCPU, operands [2]byte) { cpu.Call_nn(operands[0], operands[1]) }},
+	&Instruction{0xCE, "ADC  A,d8", 1, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.AddCA_n(operands[0]) }},
+	&Instruction{0xCF, "RST  0x08", 0, 4, func(cpu *GbcCPU, operands [2]byte) { cpu.Rst(0x08) }},
+	&Instruction{0xD0, "RET NC", 0, 0, func(cpu *GbcCPU, operands [2]byte) { cpu.Retcc(C, false) }},
+	&Instruction{0xD1, "POP  DE", 0, 3, func(cpu *GbcCPU, operands [2]byte) { cpu.Pop_nn(&cpu.R.D, &cpu.R.E) }},
+	&Instruction{0xD2, "JP  NC,a16", 2, 0, func(cpu *GbcCPU, operands [2]byte) { cpu.JPcc_nn(C, false, operands[0], operands[1]) }},
 	EMPTY_INSTRUCTION,
-	&Instruction{0xD4, "CALL  NC,a16", 2, 0, func(cpu *GbcCPU) { cpu.Callcc_nn(C, false) }},
-	&Instruction{0xD5, "PUSH DE", 0, 4, func(cpu *GbcCPU) { cpu.Push_nn(&cpu.R.D, &cpu.R.E) }},
-	&Instruction{0xD6, "SUB  d8", 1, 2, func(cpu *GbcCPU) { cpu.SubA_n() }},
-	&Instruction{0xD7, "RST n", 0, 4, func(cpu *GbcCPU) { cpu.Rst(0x10) }},
-	&Instruction{0xD8, "RET C", 0, 0, func(cpu *GbcCPU) { cpu.Retcc(C, true) }},
-	&Instruction{0xD9, "RETI", 0, 4, func(cpu *GbcCPU) { cpu.Ret_i() }},
-	&Instruction{0xDA, "JP  C,a16", 2, 0, func(cpu *GbcCPU) { cpu.JPcc_nn(C, true) }},
+	&Instruction{0xD4, "CALL  NC,a16", 2, 0, func(cpu *GbcCPU, operands [2]byte) { cpu.Callcc_nn(C, false, operands[0], operands[1]) }},
+	&Instruction{0xD5, "PUSH DE", 0, 4, func(cpu *GbcCPU, operands [2]byte) { cpu.Push_nn(&cpu.R.D, &cpu.R.E) }},
+	&Instruction{0xD6, "SUB  d8", 1, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.SubA_n(operands[0]) }},
+	&Instruction{0xD7, "RST  0x10", 0, 4, func(cpu *GbcCPU, operands [2]byte) { cpu.Rst(0x10) }},
+	&Instruction{0xD8, "RET C", 0, 0, func(cpu *GbcCPU, operands [2]byte) { cpu.Retcc(C, true) }},
+	&Instruction{0xD9, "RETI", 0, 4, func(cpu *GbcCPU, operands [2]byte) { cpu.Ret_i() }},
+	&Instruction{0xDA, "JP  C,a16", 2, 0, func(cpu *GbcCPU, operands [2]byte) { cpu.JPcc_nn(C, true, operands[0], operands[1]) }},
 	EMPTY_INSTRUCTION,
-	&Instruction{0xDC, "CALL  C,a16", 2, 0, func(cpu *GbcCPU) { cpu.Callcc_nn(C, true) }},
+	&Instruction{0xDC, "CALL  C,a16", 2, 0, func(cpu *GbcCPU, operands [2]byte) { cpu.Callcc_nn(C, true, operands[0], operands[1]) }},
 	EMPTY_INSTRUCTION,
-	&Instruction{0xDE, "SBC  A,d8", 1, 2, func(cpu *GbcCPU) { cpu.SubAC_n() }},
-	&Instruction{0xDF, "RST  n", 0, 4, func(cpu *GbcCPU) { cpu.Rst(0x18) }},
-	&Instruction{0xE0, "LDH  (a8),A", 1, 3, func(cpu *GbcCPU) { cpu.LDHn_r(&cpu.R.A) }},
-	&Instruction{0xE1, "POP  HL", 0, 3, func(cpu *GbcCPU) { cpu.Pop_nn(&cpu.R.H, &cpu.R.L) }},
-	&Instruction{0xE2, "LD  (C),A", 0, 2, func(cpu *GbcCPU) { cpu.LDffplusc_r(&cpu.R.A) }},
+	&Instruction{0xDE, "SBC  A,d8", 1, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.SubAC_n(operands[0]) }},
+	&Instruction{0xDF, "RST  0x18", 0, 4, func(cpu *GbcCPU, operands [2]byte) { cpu.Rst(0x18) }},
+	&Instruction{0xE0, "LDH  (a8),A", 1, 3, func(cpu *GbcCPU, operands [2]byte) { cpu.LDHn_r(&cpu.R.A, operands[0]) }},
+	&Instruction{0xE1, "POP  HL", 0, 3, func(cpu *GbcCPU, operands [2]byte) { cpu.Pop_nn(&cpu.R.H, &cpu.R.L) }},
+	&Instruction{0xE2, "LD  (C),A", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.LDffplusc_r(&cpu.R.A) }},
 	EMPTY_INSTRUCTION,
 	EMPTY_INSTRUCTION,
-	&Instruction{0xE5, "PUSH HL", 0, 4, func(cpu *GbcCPU) { cpu.Push_nn(&cpu.R.H, &cpu.R.L) }},
-	&Instruction{0xE6, "AND  d8", 1, 2, func(cpu *GbcCPU) { cpu.AndA_n() }},
-	&Instruction{0xE7, "RST n", 0, 4, func(cpu *GbcCPU) { cpu.Rst(0x20) }},
-	&Instruction{0xE8, "ADD  SP,r8", 1, 4, func(cpu *GbcCPU) { cpu.Addsp_n() }},
-	&Instruction{0xE9, "JP  (HL)", 0, 1, func(cpu *GbcCPU) { cpu.JP_hl() }},
-	&Instruction{0xEA, "LD  (a16),A", 2, 4, func(cpu *GbcCPU) { cpu.LDnn_r(&cpu.R.A) }},
+	&Instruction{0xE5, "PUSH HL", 0, 4, func(cpu *GbcCPU, operands [2]byte) { cpu.Push_nn(&cpu.R.H, &cpu.R.L) }},
+	&Instruction{0xE6, "AND  d8", 1, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.AndA_n(operands[0]) }},
+	&Instruction{0xE7, "RST  0x20", 0, 4, func(cpu *GbcCPU, operands [2]byte) { cpu.Rst(0x20) }},
+	&Instruction{0xE8, "ADD  SP,r8", 1, 4, func(cpu *GbcCPU, operands [2]byte) { cpu.Addsp_n(operands[0]) }},
+	&Instruction{0xE9, "JP  (HL)", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.JP_hl() }},
+	&Instruction{0xEA, "LD  (a16),A", 2, 4, func(cpu *GbcCPU, operands [2]byte) { cpu.LDnn_r(&cpu.R.A, operands[0], operands[1]) }},
 	EMPTY_INSTRUCTION,
 	EMPTY_INSTRUCTION,
 	EMPTY_INSTRUCTION,
-	&Instruction{0xEE, "XOR  d8", 1, 2, func(cpu *GbcCPU) { cpu.XorA_n() }},
-	&Instruction{0xEF, "RST n", 0, 4, func(cpu *GbcCPU) { cpu.Rst(0x28) }},
-	&Instruction{0xF0, "LDH  A,(a8)", 1, 3, func(cpu *GbcCPU) { cpu.LDHr_n(&cpu.R.A) }},
-	&Instruction{0xF1, "POP  AF", 0, 3, func(cpu *GbcCPU) { cpu.Pop_AF() }},
-	&Instruction{0xF2, "LD  A,(C)", 0, 2, func(cpu *GbcCPU) { cpu.LDr_ffplusc(&cpu.R.A) }},
-	&Instruction{0xF3, "DI", 0, 1, func(cpu *GbcCPU) { cpu.DI() }},
+	&Instruction{0xEE, "XOR  d8", 1, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.XorA_n(operands[0]) }},
+	&Instruction{0xEF, "RST  0x28", 0, 4, func(cpu *GbcCPU, operands [2]byte) { cpu.Rst(0x28) }},
+	&Instruction{0xF0, "LDH  A,(a8)", 1, 3, func(cpu *GbcCPU, operands [2]byte) { cpu.LDHr_n(&cpu.R.A, operands[0]) }},
+	&Instruction{0xF1, "POP  AF", 0, 3, func(cpu *GbcCPU, operands [2]byte) { cpu.Pop_AF() }},
+	&Instruction{0xF2, "LD  A,(C)", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.LDr_ffplusc(&cpu.R.A) }},
+	&Instruction{0xF3, "DI", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.DI() }},
 	EMPTY_INSTRUCTION,
-	&Instruction{0xF5, "PUSH AF", 0, 4, func(cpu *GbcCPU) { cpu.Push_nn(&cpu.R.A, &cpu.R.F) }},
-	&Instruction{0xF6, "OR  d8", 1, 2, func(cpu *GbcCPU) { cpu.OrA_n() }},
-	&Instruction{0xF7, "RST n", 0, 4, func(cpu *GbcCPU) { cpu.Rst(0x30) }},
-	&Instruction{0xF8, "LD  HL,SP+r8", 1, 3, func(cpu *GbcCPU) { cpu.LDHLSP_n() }},
-	&Instruction{0xF9, "LD  SP,HL", 0, 2, func(cpu *GbcCPU) { cpu.LDSP_hl() }},
-	&Instruction{0xFA, "LD  A,(a16)", 2, 4, func(cpu *GbcCPU) { cpu.LDr_nn(&cpu.R.A) }},
-	&Instruction{0xFB, "EI", 0, 1, func(cpu *GbcCPU) { cpu.EI() }},
+	&Instruction{0xF5, "PUSH AF", 0, 4, func(cpu *GbcCPU, operands [2]byte) { cpu.Push_nn(&cpu.R.A, &cpu.R.F) }},
+	&Instruction{0xF6, "OR  d8", 1, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.OrA_n(operands[0]) }},
+	&Instruction{0xF7, "RST  0x30", 0, 4, func(cpu *GbcCPU, operands [2]byte) { cpu.Rst(0x30) }},
+	&Instruction{0xF8, "LD  HL,SP+r8", 1, 3, func(cpu *GbcCPU, operands [2]byte) { cpu.LDHLSP_n(operands[0]) }},
+	&Instruction{0xF9, "LD  SP,HL", 0, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.LDSP_hl() }},
+	&Instruction{0xFA, "LD  A,(a16)", 2, 4, func(cpu *GbcCPU, operands [2]byte) { cpu.LDr_nn(&cpu.R.A, operands[0], operands[1]) }},
+	&Instruction{0xFB, "EI", 0, 1, func(cpu *GbcCPU, operands [2]byte) { cpu.EI() }},
 	EMPTY_INSTRUCTION,
 	EMPTY_INSTRUCTION,
-	&Instruction{0xFE, "CP  d8", 1, 2, func(cpu *GbcCPU) { cpu.CPA_n() }},
-	&Instruction{0xFF, "RST n", 0, 4, func(cpu *GbcCPU) { cpu.Rst(0x38) }},
+	&Instruction{0xFE, "CP  d8", 1, 2, func(cpu *GbcCPU, operands [2]byte) { cpu.CPA_n(operands[0]) }},
+	&Instruction{0xFF, "RST  0x38", 0, 4, func(cpu *GbcCPU, operands [2]byte) { cpu.Rst(0x38) }},
 }
 
 func (i Instruction) String() string {
@@ -0,0 +1,47 @@
+package cpu
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/djhworld/gomeboycolor/types"
+)
+
+//Returned once the CPU has locked up after running one of the 11 undefined opcodes (0xD3, 0xDB,
+//0xDD, 0xE3, 0xE4, 0xEB, 0xEC, 0xED, 0xF4, 0xFC and 0xFD)
+type IllegalOpcodeError struct {
+	Opcode  byte
+	Address types.Word
+	Bank    int
+}
+
+func (e *IllegalOpcodeError) Error() string {
+	return fmt.Sprintf("CPU locked up executing illegal opcode 0x%02X at %02X:%04X", e.Opcode, e.Bank, uint16(e.Address))
+}
+
+//Called once when the CPU locks up
+type LockupHandler func(err *IllegalOpcodeError)
+
+func (cpu *GbcCPU) LinkLockupHandler(h LockupHandler) {
+	cpu.lockupHandler = h
+}
+
+//Why the CPU locked up, nil unless Locked is set
+func (cpu *GbcCPU) LockupError() *IllegalOpcodeError {
+	return cpu.lockupErr
+}
+
+//On hardware an undefined opcode hangs the CPU, it stops fetching instructions and ignores
+//interrupts until it is reset. The rest of the system carries on running. The PC is left
+//pointing at the opcode that caused it
+func (cpu *GbcCPU) lockup() {
+	cpu.Locked = true
+	cpu.PC = cpu.opcodeAddress
+	cpu.PCJumped = true
+	cpu.lockupErr = &IllegalOpcodeError{cpu.opcode, cpu.PC, cpu.bank(cpu.PC)}
+
+	log.Println(PREFIX, cpu.lockupErr)
+	if cpu.lockupHandler != nil {
+		cpu.lockupHandler(cpu.lockupErr)
+	}
+}
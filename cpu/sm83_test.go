@@ -0,0 +1,254 @@
+package cpu
+
+// Conformance tests using the SM83 single step test vectors (https://github.com/SingleStepTests/sm83)
+//
+// The vectors are not distributed with the emulator, copy the JSON files (one per opcode, e.g. "00.json",
+// "cb 7c.json") into testdata/sm83 or point GOMEBOYCOLOR_SM83_TESTS at them
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/djhworld/gomeboycolor/cartridge"
+	"github.com/djhworld/gomeboycolor/timer"
+	"github.com/djhworld/gomeboycolor/types"
+)
+
+const SM83_TEST_DIR = "testdata/sm83"
+
+//failures reported per opcode file before the rest are just counted
+const SM83_MAX_REPORTED_FAILURES = 5
+
+type SM83State struct {
+	PC  uint16     `json:"pc"`
+	SP  uint16     `json:"sp"`
+	A   byte       `json:"a"`
+	B   byte       `json:"b"`
+	C   byte       `json:"c"`
+	D   byte       `json:"d"`
+	E   byte       `json:"e"`
+	F   byte       `json:"f"`
+	H   byte       `json:"h"`
+	L   byte       `json:"l"`
+	IME byte       `json:"ime"`
+	RAM [][2]int64 `json:"ram"`
+}
+
+type SM83Test struct {
+	Name    string          `json:"name"`
+	Initial SM83State       `json:"initial"`
+	Final   SM83State       `json:"final"`
+	Cycles  [][]interface{} `json:"cycles"`
+}
+
+//A write seen on the bus, the vectors list one entry per M-cycle as [address, value, "rwm"]
+//where internal cycles have a null value and no r/w flags
+type busWrite struct {
+	address types.Word
+	value   byte
+}
+
+func (t *SM83Test) expectedWrites() []busWrite {
+	var writes []busWrite
+	for _, cycle := range t.Cycles {
+		if len(cycle) != 3 || cycle[0] == nil || cycle[1] == nil {
+			continue
+		}
+		if flags, ok := cycle[2].(string); ok && strings.Contains(flags, "w") {
+			writes = append(writes, busWrite{types.Word(cycle[0].(float64)), byte(cycle[1].(float64))})
+		}
+	}
+	return writes
+}
+
+//Flat 64KB address space with no registers or banking. When recordWrites is set writes are
+//recorded so they can be checked against the bus activity in the vectors
+type FlatMMU struct {
+	memory       [65536]byte
+	recordWrites bool
+	writes       []busWrite
+}
+
+func (m *FlatMMU) WriteByte(address types.Word, value byte) {
+	m.memory[address] = value
+	if m.recordWrites {
+		m.writes = append(m.writes, busWrite{address, value})
+	}
+}
+
+func (m *FlatMMU) WriteWord(address types.Word, value types.Word) {
+	m.WriteByte(address, byte(value&0x00FF))
+	m.WriteByte(address+1, byte(value>>8))
+}
+
+func (m *FlatMMU) ReadByte(address types.Word) byte {
+	return m.memory[address]
+}
+
+func (m *FlatMMU) ReadWord(address types.Word) types.Word {
+	return types.Word(m.memory[address+1])<<8 | types.Word(m.memory[address])
+}
+
+func (m *FlatMMU) SetInBootMode(mode bool) {
+}
+
+func (m *FlatMMU) LoadBIOS(data []byte) (bool, error) {
+	return true, nil
+}
+
+func (m *FlatMMU) LoadCartridge(cart *cartridge.Cartridge) {
+}
+
+func (m *FlatMMU) Reset() {
+	m.memory = [65536]byte{}
+	m.writes = nil
+}
+
+func LoadSM83Tests(r io.Reader) ([]SM83Test, error) {
+	var tests []SM83Test
+	err := json.NewDecoder(r).Decode(&tests)
+	return tests, err
+}
+
+//Puts the CPU into the initial state of the test, runs a single instruction and returns a
+//description of every difference from the expected final state
+func RunSM83Test(c *GbcCPU, m *FlatMMU, test *SM83Test) []string {
+	m.Reset()
+	for _, entry := range test.Initial.RAM {
+		m.memory[entry[0]] = byte(entry[1])
+	}
+	m.writes = nil
+
+	c.PC = types.Word(test.Initial.PC)
+	c.SP = types.Word(test.Initial.SP)
+	c.R = Registers{A: test.Initial.A, B: test.Initial.B, C: test.Initial.C, D: test.Initial.D, E: test.Initial.E, F: test.Initial.F, H: test.Initial.H, L: test.Initial.L}
+	c.InterruptsEnabled = test.Initial.IME == 1
+	c.enableInterruptsPending = false
+	c.haltBug = false
+	c.Halted = false
+	c.Stopped = false
+	c.PCJumped = false
+
+	cycles := c.Step()
+
+	var diffs []string
+	check := func(name string, expected, actual interface{}) {
+		if expected != actual {
+			diffs = append(diffs, fmt.Sprintf("%s: expected %v but got %v", name, expected, actual))
+		}
+	}
+
+	expected := test.Final
+	check("PC", types.Word(expected.PC), c.PC)
+	check("SP", types.Word(expected.SP), c.SP)
+	check("A", expected.A, c.R.A)
+	check("B", expected.B, c.R.B)
+	check("C", expected.C, c.R.C)
+	check("D", expected.D, c.R.D)
+	check("E", expected.E, c.R.E)
+	check("F", expected.F, c.R.F)
+	check("H", expected.H, c.R.H)
+	check("L", expected.L, c.R.L)
+	//the vectors don't model the delay after EI, IME is shown as already enabled
+	check("IME", expected.IME == 1, c.InterruptsEnabled || c.enableInterruptsPending)
+	for _, entry := range expected.RAM {
+		check(fmt.Sprintf("RAM[%s]", types.Word(entry[0])), byte(entry[1]), m.memory[entry[0]])
+	}
+
+	check("M-cycles", len(test.Cycles), cycles)
+	if writes := test.expectedWrites(); fmt.Sprint(writes) != fmt.Sprint(m.writes) {
+		diffs = append(diffs, fmt.Sprintf("bus writes: expected %v but got %v", writes, m.writes))
+	}
+	return diffs
+}
+
+//a NOP and one of the DAA edge cases in the same format as the vectors
+const SM83_SAMPLE_TESTS = `[
+	{
+		"name": "00 0000",
+		"initial": {"pc": 49152, "sp": 65534, "a": 1, "b": 2, "c": 3, "d": 4, "e": 5, "f": 176, "h": 6, "l": 7, "ime": 0, "ie": 0, "ram": [[49152, 0]]},
+		"final": {"pc": 49153, "sp": 65534, "a": 1, "b": 2, "c": 3, "d": 4, "e": 5, "f": 176, "h": 6, "l": 7, "ime": 0, "ie": 0, "ram": [[49152, 0]]},
+		"cycles": [[49152, 0, "r-m"]]
+	},
+	{
+		"name": "27 0000",
+		"initial": {"pc": 49152, "sp": 65534, "a": 154, "b": 0, "c": 0, "d": 0, "e": 0, "f": 0, "h": 0, "l": 0, "ime": 0, "ie": 0, "ram": [[49152, 39]]},
+		"final": {"pc": 49153, "sp": 65534, "a": 0, "b": 0, "c": 0, "d": 0, "e": 0, "f": 144, "h": 0, "l": 0, "ime": 0, "ie": 0, "ram": [[49152, 39]]},
+		"cycles": [[49152, 39, "r-m"]]
+	},
+	{
+		"name": "e0 0000",
+		"initial": {"pc": 49152, "sp": 65534, "a": 66, "b": 0, "c": 0, "d": 0, "e": 0, "f": 0, "h": 0, "l": 0, "ime": 0, "ie": 0, "ram": [[49152, 224], [49153, 128]]},
+		"final": {"pc": 49154, "sp": 65534, "a": 66, "b": 0, "c": 0, "d": 0, "e": 0, "f": 0, "h": 0, "l": 0, "ime": 0, "ie": 0, "ram": [[49152, 224], [49153, 128], [65408, 66]]},
+		"cycles": [[49152, 224, "r-m"], [49153, 128, "r-m"], [65408, 66, "-wm"]]
+	}
+]`
+
+func setupSM83CPU() (*GbcCPU, *FlatMMU) {
+	m := &FlatMMU{recordWrites: true}
+	return NewCPU(m, timer.NewTimer()), m
+}
+
+func TestSM83SampleVectors(t *testing.T) {
+	tests, err := LoadSM83Tests(strings.NewReader(SM83_SAMPLE_TESTS))
+	if err != nil {
+		t.Fatal("Could not parse sample vectors:", err)
+	}
+
+	c, m := setupSM83CPU()
+	for i := range tests {
+		if diffs := RunSM83Test(c, m, &tests[i]); len(diffs) > 0 {
+			t.Error(tests[i].Name, "\n\t"+strings.Join(diffs, "\n\t"))
+		}
+	}
+}
+
+func TestSM83Vectors(t *testing.T) {
+	var dir string = SM83_TEST_DIR
+	if d := os.Getenv("GOMEBOYCOLOR_SM83_TESTS"); d != "" {
+		dir = d
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	if len(files) == 0 {
+		t.Skip("No SM83 test vectors found in", dir)
+	}
+
+	c, m := setupSM83CPU()
+	for _, file := range files {
+		file := file
+		t.Run(strings.TrimSuffix(filepath.Base(file), ".json"), func(t *testing.T) {
+			f, err := os.Open(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+
+			tests, err := LoadSM83Tests(f)
+			if err != nil {
+				t.Fatal("Could not parse", file, err)
+			}
+
+			var failures int
+			for i := range tests {
+				diffs := RunSM83Test(c, m, &tests[i])
+				if len(diffs) == 0 {
+					continue
+				}
+				failures++
+				if failures <= SM83_MAX_REPORTED_FAILURES {
+					t.Error(tests[i].Name, "\n\t"+strings.Join(diffs, "\n\t"))
+				}
+			}
+
+			if failures > 0 {
+				t.Errorf("%d of %d vectors failed", failures, len(tests))
+			}
+		})
+	}
+}
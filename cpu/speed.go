@@ -0,0 +1,40 @@
+package cpu
+
+import (
+	"github.com/djhworld/gomeboycolor/components"
+	"github.com/djhworld/gomeboycolor/types"
+)
+
+//KEY1 (0xFF4D), the CGB register that prepares a speed switch. Bit 0 arms a switch that the
+//next STOP performs and is the only bit that can be written, bit 7 reads back the speed the CPU
+//is running at
+type SpeedSwitchRegister struct {
+	cpu   *GbcCPU
+	armed bool
+}
+
+func (r *SpeedSwitchRegister) Name() string {
+	return PREFIX + " KEY1"
+}
+
+func (r *SpeedSwitchRegister) Read(address types.Word) byte {
+	var value byte = 0x00
+	if r.armed {
+		value |= 0x01
+	}
+	if r.cpu.Speed == 2 {
+		value |= 0x80
+	}
+	return value
+}
+
+func (r *SpeedSwitchRegister) Write(address types.Word, value byte) {
+	r.armed = value&0x01 == 0x01
+}
+
+func (r *SpeedSwitchRegister) LinkIRQHandler(m components.IRQHandler) {
+}
+
+func (r *SpeedSwitchRegister) Reset() {
+	r.armed = false
+}
@@ -0,0 +1,99 @@
+package cpu
+
+import (
+	"bufio"
+	"io"
+	"sync"
+
+	"github.com/djhworld/gomeboycolor/types"
+)
+
+const hexDigits = "0123456789ABCDEF"
+
+//one line of the trace, the hex digits are filled in for each instruction
+const traceTemplate = "A:00 F:00 B:00 C:00 D:00 E:00 H:00 L:00 SP:0000 PC:0000 PCMEM:00,00,00,00\n"
+
+//offsets of each value within traceTemplate
+const (
+	traceA     = 2
+	traceF     = 7
+	traceB     = 12
+	traceC     = 17
+	traceD     = 22
+	traceE     = 27
+	traceH     = 32
+	traceL     = 37
+	traceSP    = 43
+	tracePC    = 51
+	tracePCMEM = 62
+)
+
+//Writes the state of the CPU before each instruction is executed in the format used by
+//gameboy-doctor, e.g.
+//
+//A:01 F:B0 B:00 C:13 D:00 E:D8 H:01 L:4D SP:FFFE PC:0100 PCMEM:00,C3,13,02
+//
+//Output is buffered, Flush or Stop must be called once tracing is finished
+type Tracer struct {
+	lock    sync.Mutex
+	w       *bufio.Writer
+	line    []byte
+	stopped bool
+}
+
+func NewTracer(w io.Writer) *Tracer {
+	return &Tracer{w: bufio.NewWriterSize(w, 64*1024), line: []byte(traceTemplate)}
+}
+
+//Writes a line for the instruction at PC. Reading PCMEM does not tick the CPU clock
+func (t *Tracer) Trace(cpu *GbcCPU) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if t.stopped {
+		return
+	}
+
+	putByte(t.line[traceA:], cpu.R.A)
+	putByte(t.line[traceF:], cpu.R.F)
+	putByte(t.line[traceB:], cpu.R.B)
+	putByte(t.line[traceC:], cpu.R.C)
+	putByte(t.line[traceD:], cpu.R.D)
+	putByte(t.line[traceE:], cpu.R.E)
+	putByte(t.line[traceH:], cpu.R.H)
+	putByte(t.line[traceL:], cpu.R.L)
+	putWord(t.line[traceSP:], cpu.SP)
+	putWord(t.line[tracePC:], cpu.PC)
+	for i := 0; i < 4; i++ {
+		putByte(t.line[tracePCMEM+i*3:], cpu.mmu.ReadByte(cpu.PC+types.Word(i)))
+	}
+
+	//write errors are held by the buffered writer and returned from Flush
+	t.w.Write(t.line)
+}
+
+//Writes any buffered lines to the underlying writer, returning the first error that
+//occurred while tracing
+func (t *Tracer) Flush() error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	return t.w.Flush()
+}
+
+//Flushes the trace and ignores any further instructions, this is safe to call while
+//the CPU is running on another goroutine
+func (t *Tracer) Stop() error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.stopped = true
+	return t.w.Flush()
+}
+
+func putByte(dst []byte, b byte) {
+	dst[0] = hexDigits[b>>4]
+	dst[1] = hexDigits[b&0x0F]
+}
+
+func putWord(dst []byte, w types.Word) {
+	putByte(dst, byte(w>>8))
+	putByte(dst[2:], byte(w))
+}
@@ -0,0 +1,232 @@
+package disasm
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/djhworld/gomeboycolor/cpu"
+	"github.com/djhworld/gomeboycolor/mmu"
+	"github.com/djhworld/gomeboycolor/types"
+)
+
+const CB_PREFIX byte = 0xCB
+
+var TruncatedInstruction error = errors.New("Not enough bytes to decode instruction")
+
+//M-cycle timings for the instructions whose duration depends on a condition, the CPU
+//instruction tables have these as 0. The first value is when the branch is not taken
+var conditionalCycles map[byte][2]int = map[byte][2]int{
+	0x20: {2, 3}, 0x28: {2, 3}, 0x30: {2, 3}, 0x38: {2, 3}, //JR cc,r8
+	0xC0: {2, 5}, 0xC8: {2, 5}, 0xD0: {2, 5}, 0xD8: {2, 5}, //RET cc
+	0xC2: {3, 4}, 0xCA: {3, 4}, 0xD2: {3, 4}, 0xDA: {3, 4}, //JP cc,a16
+	0xC4: {3, 6}, 0xCC: {3, 6}, 0xD4: {3, 6}, 0xDC: {3, 6}, //CALL cc,a16
+	0x10: {1, 1}, //STOP
+	0x76: {1, 1}, //HALT
+}
+
+//A single decoded instruction
+type Instruction struct {
+	Address  types.Word
+	Bytes    []byte
+	Mnemonic string
+	Operands string
+	Length   int
+
+	//M-cycles taken, for conditional instructions Cycles is when the condition fails
+	//and CyclesTaken when it passes
+	Cycles      int
+	CyclesTaken int
+
+	//set for opcodes that do not exist on the SM83, these are rendered as a DB directive
+	Illegal bool
+}
+
+func (i Instruction) String() string {
+	if i.Operands == "" {
+		return i.Mnemonic
+	}
+	return i.Mnemonic + " " + i.Operands
+}
+
+//Formats the instruction as a line of a listing with its address and raw bytes, e.g.
+//"0x0150  F0 44     LD A,(0xFF44)"
+func (i Instruction) Listing() string {
+	var raw []string = make([]string, len(i.Bytes))
+	for j, b := range i.Bytes {
+		raw[j] = fmt.Sprintf("%02X", b)
+	}
+	return fmt.Sprintf("%s  %-8s  %s", i.Address, strings.Join(raw, " "), i)
+}
+
+//Returns true when the number of cycles depends on whether a condition holds
+func (i Instruction) IsConditional() bool {
+	return i.Cycles != i.CyclesTaken
+}
+
+//Decodes the instruction at the start of data, address is the location of data[0] in
+//memory and is used to resolve relative jumps
+func Decode(data []byte, address types.Word) (Instruction, error) {
+	if len(data) == 0 {
+		return Instruction{}, TruncatedInstruction
+	}
+
+	var opcode byte = data[0]
+	var instr *cpu.Instruction
+	var length int
+
+	if opcode == CB_PREFIX {
+		if len(data) < 2 {
+			return Instruction{}, TruncatedInstruction
+		}
+		instr = cpu.InstructionsCB[data[1]]
+		length = 2
+	} else {
+		instr = cpu.Instructions[opcode]
+		if instr == cpu.EMPTY_INSTRUCTION {
+			return illegal(opcode, address), nil
+		}
+		length = instr.OperandsSize + 1
+	}
+
+	if len(data) < length {
+		return Instruction{}, TruncatedInstruction
+	}
+
+	var result Instruction = Instruction{
+		Address:     address,
+		Bytes:       append([]byte(nil), data[:length]...),
+		Length:      length,
+		Cycles:      instr.Cycles,
+		CyclesTaken: instr.Cycles,
+	}
+
+	if timing, ok := conditionalCycles[opcode]; ok {
+		result.Cycles, result.CyclesTaken = timing[0], timing[1]
+	}
+
+	result.Mnemonic, result.Operands = render(instr.Description, data[1:length], address, length)
+	return result, nil
+}
+
+//Decodes the instruction stored at address
+func DecodeAt(m mmu.MemoryMappedUnit, address types.Word) Instruction {
+	var data [3]byte
+	for i := range data {
+		data[i] = m.ReadByte(address + types.Word(i))
+	}
+
+	//three bytes is the longest instruction so this can't be truncated
+	instr, _ := Decode(data[:], address)
+	return instr
+}
+
+//Decodes every instruction in data, any trailing bytes that do not form a complete
+//instruction are returned as DB directives
+func Disassemble(data []byte, address types.Word) []Instruction {
+	var result []Instruction
+	for offset := 0; offset < len(data); {
+		instr, err := Decode(data[offset:], address+types.Word(offset))
+		if err != nil {
+			instr = illegal(data[offset], address+types.Word(offset))
+			instr.Illegal = false
+		}
+		result = append(result, instr)
+		offset += instr.Length
+	}
+	return result
+}
+
+//Decodes every instruction that starts between start and end (inclusive)
+func DisassembleRange(m mmu.MemoryMappedUnit, start, end types.Word) []Instruction {
+	var result []Instruction
+	for addr := int(start); addr <= int(end); {
+		instr := DecodeAt(m, types.Word(addr))
+		result = append(result, instr)
+		addr += instr.Length
+	}
+	return result
+}
+
+//Decodes count instructions starting from address
+func DisassembleCount(m mmu.MemoryMappedUnit, address types.Word, count int) []Instruction {
+	var result []Instruction = make([]Instruction, 0, count)
+	for i := 0; i < count; i++ {
+		instr := DecodeAt(m, address)
+		result = append(result, instr)
+		address += types.Word(instr.Length)
+	}
+	return result
+}
+
+func illegal(opcode byte, address types.Word) Instruction {
+	return Instruction{
+		Address:     address,
+		Bytes:       []byte{opcode},
+		Mnemonic:    "DB",
+		Operands:    fmt.Sprintf("0x%02X", opcode),
+		Length:      1,
+		Cycles:      1,
+		CyclesTaken: 1,
+		Illegal:     true,
+	}
+}
+
+//Turns an instruction table description (e.g. "LDH  A,(a8)") into a mnemonic and operands
+//with the placeholders replaced by the immediate values
+func render(description string, immediate []byte, address types.Word, length int) (string, string) {
+	var fields []string = strings.Fields(description)
+	var mnemonic string = fields[0]
+	if len(fields) == 1 {
+		return mnemonic, ""
+	}
+
+	//LDH is just LD with the high byte of the address fixed at 0xFF
+	if mnemonic == "LDH" {
+		mnemonic = "LD"
+	}
+
+	//STOP is followed by a padding byte that is not an operand
+	if mnemonic == "STOP" {
+		return mnemonic, ""
+	}
+
+	var operands []string = strings.Split(strings.Join(fields[1:], ""), ",")
+	for i, operand := range operands {
+		operands[i] = renderOperand(mnemonic, operand, immediate, address, length)
+	}
+	return mnemonic, strings.Join(operands, ",")
+}
+
+func renderOperand(mnemonic, operand string, immediate []byte, address types.Word, length int) string {
+	switch {
+	case strings.Contains(operand, "a16"), strings.Contains(operand, "d16"):
+		var value string = fmt.Sprintf("0x%04X", uint16(immediate[1])<<8|uint16(immediate[0]))
+		return strings.NewReplacer("a16", value, "d16", value).Replace(operand)
+	case strings.Contains(operand, "a8"):
+		return strings.Replace(operand, "a8", fmt.Sprintf("0x%04X", 0xFF00|uint16(immediate[0])), 1)
+	case operand == "d8", operand == "n":
+		return fmt.Sprintf("0x%02X", immediate[0])
+	case operand == "r8" && mnemonic == "JR":
+		//relative jumps are shown as the address they land on
+		var target types.Word = address + types.Word(length) + types.Word(int8(immediate[0]))
+		return fmt.Sprintf("0x%04X", uint16(target))
+	case strings.HasSuffix(operand, "r8"):
+		var prefix string = strings.TrimSuffix(strings.TrimSuffix(operand, "r8"), "+")
+		return prefix + signed(int8(immediate[0]), prefix != "")
+	case operand == "(C)":
+		return "(0xFF00+C)"
+	}
+	return operand
+}
+
+//signed offsets always carry their sign when they follow a register (e.g. SP+0x05)
+func signed(value int8, withPlus bool) string {
+	if value < 0 {
+		return fmt.Sprintf("-0x%02X", -int(value))
+	}
+	if withPlus {
+		return fmt.Sprintf("+0x%02X", value)
+	}
+	return fmt.Sprintf("0x%02X", value)
+}
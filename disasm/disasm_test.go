@@ -0,0 +1,107 @@
+package disasm
+
+import (
+	"testing"
+
+	"github.com/djhworld/gomeboycolor/types"
+	"github.com/stretchrcom/testify/assert"
+)
+
+func decode(t *testing.T, address types.Word, data ...byte) Instruction {
+	instr, err := Decode(data, address)
+	assert.Nil(t, err)
+	return instr
+}
+
+func TestDecodeRendersImmediateValues(t *testing.T) {
+	var tests = []struct {
+		data     []byte
+		expected string
+	}{
+		{[]byte{0x00}, "NOP"},
+		{[]byte{0x3E, 0x91}, "LD A,0x91"},
+		{[]byte{0x06, 0x0A}, "LD B,0x0A"},
+		{[]byte{0x21, 0x34, 0x12}, "LD HL,0x1234"},
+		{[]byte{0x01, 0x00, 0xC0}, "LD BC,0xC000"},
+		{[]byte{0xFA, 0x44, 0xFF}, "LD A,(0xFF44)"},
+		{[]byte{0xF0, 0x44}, "LD A,(0xFF44)"},
+		{[]byte{0xE0, 0x40}, "LD (0xFF40),A"},
+		{[]byte{0xE2}, "LD (0xFF00+C),A"},
+		{[]byte{0x08, 0xFE, 0xDF}, "LD (0xDFFE),SP"},
+		{[]byte{0xC3, 0x50, 0x01}, "JP 0x0150"},
+		{[]byte{0xCD, 0x00, 0x40}, "CALL 0x4000"},
+		{[]byte{0xE8, 0xFE}, "ADD SP,-0x02"},
+		{[]byte{0xF8, 0x05}, "LD HL,SP+0x05"},
+		{[]byte{0xFF}, "RST 0x38"},
+		{[]byte{0x10, 0x00}, "STOP"},
+		{[]byte{0x22}, "LD (HL+),A"},
+		{[]byte{0xCB, 0x7C}, "BIT 7,H"},
+		{[]byte{0xCB, 0x37}, "SWAP A"},
+	}
+
+	for _, test := range tests {
+		instr := decode(t, 0x0100, test.data...)
+		assert.Equal(t, test.expected, instr.String())
+		assert.Equal(t, len(test.data), instr.Length)
+		assert.Equal(t, test.data, instr.Bytes)
+	}
+}
+
+func TestDecodeResolvesRelativeJumps(t *testing.T) {
+	assert.Equal(t, "JR NZ,0x0105", decode(t, 0x0100, 0x20, 0x03).String())
+	assert.Equal(t, "JR 0x0100", decode(t, 0x0100, 0x18, 0xFE).String())
+}
+
+func TestDecodeSplitsMnemonicAndOperands(t *testing.T) {
+	instr := decode(t, 0x0000, 0xF0, 0x44)
+	assert.Equal(t, "LD", instr.Mnemonic)
+	assert.Equal(t, "A,(0xFF44)", instr.Operands)
+	assert.Equal(t, types.Word(0x0000), instr.Address)
+}
+
+func TestListing(t *testing.T) {
+	assert.Equal(t, "0x0150  F0 44     LD A,(0xFF44)", decode(t, 0x0150, 0xF0, 0x44).Listing())
+	assert.Equal(t, "0x0000  00        NOP", decode(t, 0x0000, 0x00).Listing())
+}
+
+func TestDecodeCycles(t *testing.T) {
+	instr := decode(t, 0x0000, 0xCD, 0x00, 0x40)
+	assert.Equal(t, 6, instr.Cycles)
+	assert.False(t, instr.IsConditional())
+
+	instr = decode(t, 0x0000, 0xC4, 0x00, 0x40)
+	assert.Equal(t, 3, instr.Cycles)
+	assert.Equal(t, 6, instr.CyclesTaken)
+	assert.True(t, instr.IsConditional())
+
+	instr = decode(t, 0x0000, 0xCB, 0x46)
+	assert.Equal(t, 3, instr.Cycles)
+}
+
+func TestDecodeIllegalOpcode(t *testing.T) {
+	instr := decode(t, 0x0000, 0xD3, 0x00)
+	assert.True(t, instr.Illegal)
+	assert.Equal(t, "DB 0xD3", instr.String())
+	assert.Equal(t, 1, instr.Length)
+}
+
+func TestDecodeTruncated(t *testing.T) {
+	_, err := Decode([]byte{0xC3, 0x00}, 0x0000)
+	assert.Equal(t, TruncatedInstruction, err)
+
+	_, err = Decode([]byte{0xCB}, 0x0000)
+	assert.Equal(t, TruncatedInstruction, err)
+}
+
+func TestDisassemble(t *testing.T) {
+	var program []byte = []byte{0x31, 0xFE, 0xFF, 0xAF, 0x21, 0xFF, 0x9F, 0x32, 0xCB, 0x7C, 0x20, 0xFB, 0xC3}
+	instrs := Disassemble(program, 0x0000)
+
+	var expected []string = []string{"LD SP,0xFFFE", "XOR A", "LD HL,0x9FFF", "LD (HL-),A", "BIT 7,H", "JR NZ,0x0007", "DB 0xC3"}
+	assert.Equal(t, len(expected), len(instrs))
+	for i, instr := range instrs {
+		assert.Equal(t, expected[i], instr.String())
+	}
+	assert.Equal(t, types.Word(0x000A), instrs[5].Address)
+	assert.False(t, instrs[6].Illegal)
+}
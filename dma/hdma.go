@@ -3,6 +3,7 @@ package dma
 import (
 	"log"
 
+	"github.com/djhworld/gomeboycolor/cdl"
 	"github.com/djhworld/gomeboycolor/components"
 	"github.com/djhworld/gomeboycolor/constants"
 	"github.com/djhworld/gomeboycolor/mmu"
@@ -18,6 +19,10 @@ const (
 	CGB_HDMA_REG             types.Word = 0xFF55
 )
 
+//M-cycles the CPU is paused for while each 16 byte block is copied at normal speed, this doubles
+//in double speed mode
+const HDMA_BLOCK_CYCLES int = 8
+
 type HDMARegisters struct {
 	srcHigh byte
 	srcLow  byte
@@ -39,6 +44,7 @@ type HDMA struct {
 	registers        HDMARegisters
 	hdmaTransfer     HDMATransfer
 	mmu              *mmu.GbcMMU
+	cdl              *cdl.Logger
 }
 
 func NewHDMA(mmu *mmu.GbcMMU) *HDMA {
@@ -57,6 +63,11 @@ func (h *HDMA) Step() {
 		return
 	}
 
+	if h.cdl != nil {
+		previous := h.cdl.SetAccess(cdl.DATA | cdl.VRAM)
+		defer h.cdl.SetAccess(previous)
+	}
+
 	// Write 1 block (16 bytes)
 	for i := types.Word(0x0000); i < 0x0010; i++ {
 		data := h.mmu.ReadByte(h.hdmaTransfer.Source + i)
@@ -129,6 +140,11 @@ func (h *HDMA) Write(address types.Word, value byte) {
 
 }
 
+//Marks the source of every block copied into VRAM for a code/data logger, nil stops logging
+func (h *HDMA) SetCodeDataLogger(l *cdl.Logger) {
+	h.cdl = l
+}
+
 func (h *HDMA) LinkIRQHandler(m components.IRQHandler) {
 
 }
@@ -1,6 +1,7 @@
 package dma
 
 import (
+	"github.com/djhworld/gomeboycolor/cdl"
 	"github.com/djhworld/gomeboycolor/components"
 	"github.com/djhworld/gomeboycolor/mmu"
 	"github.com/djhworld/gomeboycolor/types"
@@ -11,11 +12,15 @@ const (
 	DMA_TRANSFER types.Word = 0xFF46
 )
 
+//M-cycles taken to copy the 160 bytes of OAM, one byte per cycle
+const OAM_DMA_CYCLES int = 160
+
 type OAMDMA struct {
 	running      bool
 	cycles       int
 	transferFrom types.Word
 	mmu          *mmu.GbcMMU
+	cdl          *cdl.Logger
 }
 
 func NewOAMDMA(mmu *mmu.GbcMMU) *OAMDMA {
@@ -30,11 +35,9 @@ func (o *OAMDMA) Name() string {
 }
 
 func (o *OAMDMA) Step(cycles int) {
-	o.cycles += cycles
-
 	if o.running {
-		if o.cycles >= 648 {
-
+		o.cycles += cycles
+		if o.cycles >= OAM_DMA_CYCLES {
 			o.running = false
 			o.cycles = 0
 			o.doInstantDMATransfer(o.transferFrom, 0xFE00, 10, 16)
@@ -49,6 +52,12 @@ func (o *OAMDMA) Read(address types.Word) byte {
 func (o *OAMDMA) Write(address types.Word, value byte) {
 	o.transferFrom = types.Word(value) << 8
 	o.running = true
+	o.cycles = 0
+}
+
+//Marks the bytes copied into OAM for a code/data logger, these are logged as VRAM bound
+func (o *OAMDMA) SetCodeDataLogger(l *cdl.Logger) {
+	o.cdl = l
 }
 
 func (o *OAMDMA) LinkIRQHandler(m components.IRQHandler) {
@@ -62,6 +71,11 @@ func (o *OAMDMA) Reset() {
 }
 
 func (o *OAMDMA) doInstantDMATransfer(startAddress, destinationAddr types.Word, blocks, blockSize int) {
+	if o.cdl != nil {
+		previous := o.cdl.SetAccess(cdl.DATA | cdl.VRAM)
+		defer o.cdl.SetAccess(previous)
+	}
+
 	length := types.Word(blockSize * blocks)
 	var i types.Word = 0x0000
 	for ; i < length; i++ {
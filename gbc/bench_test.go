@@ -0,0 +1,97 @@
+package gbc
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/djhworld/gomeboycolor/cartridge"
+)
+
+//Builds a ROM that never waits for the display, it copies and mangles data from ROM into WRAM
+//calling a subroutine for every byte, so the CPU is running flat out
+func busyBenchmarkROM(b *testing.B) *cartridge.Cartridge {
+	var data []string
+	for i := 0; i < 0x100; i++ {
+		data = append(data, fmt.Sprint(byte(i*7)))
+	}
+
+	return assembleTestROM(b, "busy.gb", `
+		ORG 0x0100
+		JP main
+
+		ORG 0x0150
+	main:
+		LD HL,0xC000
+		LD DE,data
+		LD B,0x00
+	loop:
+		LD A,(DE)
+		INC DE
+		ADD A,B
+		XOR 0x5A
+		LD (HL+),A
+		CALL mangle
+		DEC B
+		JR NZ,loop
+		JR main
+
+	mangle:
+		SWAP A
+		RET
+
+	data:
+		DB `+strings.Join(data, ", "))
+}
+
+//Builds a ROM that spends most of each frame halted waiting for the VBlank interrupt, as most
+//games do, so the cost of everything other than the CPU is measured
+func idleBenchmarkROM(b *testing.B) *cartridge.Cartridge {
+	return assembleTestROM(b, "idle.gb", `
+		ORG 0x0040 ; VBlank
+		RETI
+
+		ORG 0x0100
+		JP 0x0150
+
+		ORG 0x0150
+		LD A,0x01
+		LD (0xFFFF),A
+		EI
+	wait:
+		HALT
+		INC A
+		JR wait
+	`)
+}
+
+//Runs whole frames headlessly, reporting how many frames a second one instance can run
+func benchmarkHeadless(b *testing.B, cart *cartridge.Cartridge, colorMode bool) {
+	gbc, io := newTestROMEmulator(cart, colorMode)
+	defer close(io.screen)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		gbc.doFrame()
+		gbc.cpuClockAcc = 0
+	}
+	b.StopTimer()
+
+	if gbc.cpu.Locked {
+		b.Fatal(gbc.Err())
+	}
+	b.ReportMetric(float64(b.N)/b.Elapsed().Seconds(), "fps")
+}
+
+func BenchmarkHeadlessBusy(b *testing.B) {
+	benchmarkHeadless(b, busyBenchmarkROM(b), false)
+}
+
+func BenchmarkHeadlessBusyColor(b *testing.B) {
+	benchmarkHeadless(b, busyBenchmarkROM(b), true)
+}
+
+func BenchmarkHeadlessIdle(b *testing.B) {
+	benchmarkHeadless(b, idleBenchmarkROM(b), false)
+}
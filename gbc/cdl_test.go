@@ -0,0 +1,61 @@
+package gbc
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/djhworld/gomeboycolor/cartridge"
+	"github.com/djhworld/gomeboycolor/cdl"
+	"github.com/stretchrcom/testify/assert"
+)
+
+//Builds a 4 bank MBC1 ROM that switches to bank 2 then reads data, calls a subroutine and
+//starts an OAM DMA transfer from it
+func bankedTestROM(t *testing.T) *cartridge.Cartridge {
+	return assembleTestROM(t, "banked.gb", fmt.Sprintf(`
+		ORG 0x0100
+		JP 0x0150
+
+		ORG 0x0147
+		DB %d, 0x01 ; 64KB
+
+		ORG 0x0150
+		LD A,0x02
+		LD (0x2000),A
+		LD A,(0x4000)
+		CALL 0x4010
+		LD A,0x41
+		LD (0xFF46),A
+	loop:
+		JR loop
+
+		ORG 0x8010 ; 0x4010 in bank 2
+		RET
+	`, cartridge.MBC_1))
+}
+
+func TestCodeDataLog(t *testing.T) {
+	gbc, io := newTestROMEmulator(bankedTestROM(t), false)
+	defer close(io.screen)
+
+	assert.Nil(t, gbc.startCodeDataLog(""))
+	gbc.runTestROM(20000, func() bool {
+		return false
+	})
+
+	var l *cdl.Logger = gbc.codeDataLog
+	assert.Equal(t, 0x10000, l.Size())
+	assert.Equal(t, cdl.CODE|cdl.OPCODE, l.Flags(0x0150))
+	assert.Equal(t, cdl.CODE, l.Flags(0x0151))
+	assert.Equal(t, cdl.CODE, l.Flags(0x0154))
+
+	//bank 2 is mapped at 0x4000
+	assert.Equal(t, cdl.DATA, l.Flags(0x8000))
+	assert.Equal(t, cdl.CODE|cdl.OPCODE, l.Flags(0x8010))
+	assert.Equal(t, cdl.NONE, l.Flags(0x4010))
+
+	//160 bytes copied from 0x4100 into OAM
+	assert.Equal(t, cdl.DATA|cdl.VRAM, l.Flags(0x8100))
+	assert.Equal(t, cdl.DATA|cdl.VRAM, l.Flags(0x819F))
+	assert.Equal(t, cdl.NONE, l.Flags(0x81A0))
+}
@@ -13,6 +13,9 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/djhworld/gomeboycolor/asm"
+	"github.com/djhworld/gomeboycolor/cpu"
+	"github.com/djhworld/gomeboycolor/disasm"
 	"github.com/djhworld/gomeboycolor/gpu"
 	"github.com/djhworld/gomeboycolor/types"
 	"github.com/djhworld/gomeboycolor/utils"
@@ -59,6 +62,11 @@ func (g *DebugOptions) Init(cpuDumpOnStep bool) {
 
 	g.AddDebugFunc("d", "Disconnect from debugger", func(gbc *GomeboyColor, remaining ...string) {
 		gbc.debugOptions.debuggerOn = false
+		gbc.cpu.TrackCallStack(false)
+	})
+
+	g.AddDebugFunc("bt", "Print a backtrace of the subroutines and interrupt handlers that led to the current PC", func(gbc *GomeboyColor, remaining ...string) {
+		fmt.Print(gbc.backtrace())
 	})
 
 	g.AddDebugFunc("dg", "Dump everything in graphics RAM to a file", func(gbc *GomeboyColor, remaining ...string) {
@@ -254,6 +262,236 @@ func (g *DebugOptions) Init(cpuDumpOnStep bool) {
 		}
 	})
 
+	g.AddDebugFunc("disasm", "Disassemble instructions from an address (defaults to PC)", func(gbc *GomeboyColor, remaining ...string) {
+		var startAddr types.Word = gbc.cpu.PC
+		var count int = 10
+		if len(remaining) > 0 {
+			addr, err := ToMemoryAddress(remaining[0])
+			if err != nil {
+				fmt.Println("Could not parse memory address: ", remaining[0])
+				return
+			}
+			startAddr = addr
+		}
+		if len(remaining) > 1 {
+			val, err := strconv.ParseInt(remaining[1], 10, 64)
+			if err != nil || val <= 0 {
+				fmt.Println("Could not parse instruction count: ", remaining[1])
+				return
+			}
+			count = int(val)
+		}
+
+		for _, instr := range disasm.DisassembleCount(gbc.mmu, startAddr, count) {
+			if instr.Address == gbc.cpu.PC {
+				fmt.Println("->", instr.Listing())
+			} else {
+				fmt.Println("  ", instr.Listing())
+			}
+		}
+	})
+
+	g.AddDebugFunc("asm", "Assemble an instruction over memory at an address, ROM is patched in place (asm <addr> <instr>)", func(gbc *GomeboyColor, remaining ...string) {
+		if len(remaining) < 2 {
+			fmt.Println("You must provide an address and an instruction to assemble")
+			return
+		}
+
+		addr, err := ToMemoryAddress(remaining[0])
+		if err != nil {
+			fmt.Println("Could not parse memory address: ", remaining[0])
+			return
+		}
+
+		instr, err := gbc.patch(addr, strings.Join(remaining[1:], " "))
+		if err != nil {
+			fmt.Println("Could not assemble instruction:", err)
+			return
+		}
+		fmt.Println("  ", instr.Listing())
+	})
+
+	g.AddDebugFunc("mute", "Toggle muting of a sound channel (1-4)", func(gbc *GomeboyColor, remaining ...string) {
+		channel, err := parseSoundChannel(remaining)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+
+		gbc.catchUpAPU()
+		muted := !gbc.apu.IsChannelMuted(channel)
+		gbc.apu.SetChannelMuted(channel, muted)
+		fmt.Println("Channel", channel, "muted:", muted)
+	})
+
+	g.AddDebugFunc("solo", "Toggle soloing of a sound channel (1-4)", func(gbc *GomeboyColor, remaining ...string) {
+		channel, err := parseSoundChannel(remaining)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+
+		gbc.catchUpAPU()
+		soloed := !gbc.apu.IsChannelSoloed(channel)
+		gbc.apu.SetChannelSolo(channel, soloed)
+		fmt.Println("Channel", channel, "soloed:", soloed)
+	})
+
+	g.AddDebugFunc("rec", "Start/stop recording a sound channel (1-4) to a WAV file", func(gbc *GomeboyColor, remaining ...string) {
+		channel, err := parseSoundChannel(remaining)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+
+		if gbc.apu.IsChannelCaptured(channel) {
+			if err := gbc.stopChannelRecording(channel); err != nil {
+				fmt.Println(err)
+				return
+			}
+			fmt.Println("Stopped recording channel", channel)
+			return
+		}
+
+		var filename string
+		if len(remaining) < 2 {
+			filename = fmt.Sprintf("channel%d.wav", channel)
+			fmt.Println("No filename provided, defaulting to", filename)
+		} else {
+			filename = remaining[1]
+		}
+
+		if err := gbc.startChannelRecording(channel, filename); err != nil {
+			fmt.Println("Could not record channel", channel)
+			fmt.Println("\t", err)
+			return
+		}
+		fmt.Println("Recording channel", channel, "to", filename)
+	})
+
+	g.AddDebugFunc("vgm", "Start/stop recording APU register writes to a VGM file", func(gbc *GomeboyColor, remaining ...string) {
+		if gbc.vgmRecorder != nil {
+			filename := gbc.vgmFilename
+			if err := gbc.stopVGMRecording(); err != nil {
+				fmt.Println("Could not save VGM file", filename)
+				fmt.Println("\t", err)
+				return
+			}
+			fmt.Println("Saved APU writes to", filename)
+			return
+		}
+
+		var filename string
+		if len(remaining) == 0 {
+			filename = "apu.vgm"
+			fmt.Println("No filename provided, defaulting to", filename)
+		} else {
+			filename = remaining[0]
+		}
+
+		if err := gbc.startVGMRecording(filename); err != nil {
+			fmt.Println(err)
+			return
+		}
+		fmt.Println("Recording APU writes to", filename)
+	})
+
+	g.AddDebugFunc("trace", "Start/stop writing a gameboy-doctor trace of every instruction to a file", func(gbc *GomeboyColor, remaining ...string) {
+		if gbc.tracer != nil {
+			filename := gbc.traceFile.Name()
+			if err := gbc.stopTrace(); err != nil {
+				fmt.Println("Could not save trace file", filename)
+				fmt.Println("\t", err)
+				return
+			}
+			fmt.Println("Saved CPU trace to", filename)
+			return
+		}
+
+		var filename string
+		if len(remaining) == 0 {
+			filename = "trace.log"
+			fmt.Println("No filename provided, defaulting to", filename)
+		} else {
+			filename = remaining[0]
+		}
+
+		if err := gbc.startTrace(filename); err != nil {
+			fmt.Println(err)
+			return
+		}
+		fmt.Println("Tracing CPU to", filename)
+	})
+
+	g.AddDebugFunc("cdl", "Start logging code/data accesses to the ROM, or save the log so far to a CDL file", func(gbc *GomeboyColor, remaining ...string) {
+		if gbc.codeDataLog == nil {
+			if err := gbc.startCodeDataLog(""); err != nil {
+				fmt.Println(err)
+				return
+			}
+			fmt.Println("Logging code/data accesses, run cdl again to save them")
+			return
+		}
+
+		var filename string = gbc.config.CodeDataLogFile
+		if len(remaining) > 0 {
+			filename = remaining[0]
+		} else if filename == "" {
+			filename = "rom.cdl"
+			fmt.Println("No filename provided, defaulting to", filename)
+		}
+
+		if err := gbc.saveCodeDataLog(filename); err != nil {
+			fmt.Println("Could not save code/data log to", filename)
+			fmt.Println("\t", err)
+			return
+		}
+		fmt.Print(gbc.codeDataLog.Coverage())
+		fmt.Println("Saved code/data log to", filename)
+	})
+
+	g.AddDebugFunc("profile", "Start profiling the CPU, then show the hot spots (profile [top]), save a pprof profile (profile save <file>) or stop (profile stop)", func(gbc *GomeboyColor, remaining ...string) {
+		if gbc.profiler == nil {
+			gbc.startProfiling()
+			fmt.Println("Profiling CPU, run profile again to see where the cycles are going")
+			return
+		}
+
+		var top int = 20
+		if len(remaining) > 0 {
+			switch remaining[0] {
+			case "stop":
+				gbc.stopProfiling()
+				fmt.Println("Stopped profiling CPU")
+				return
+			case "save":
+				var filename string = "cpu.pprof"
+				if len(remaining) > 1 {
+					filename = remaining[1]
+				} else {
+					fmt.Println("No filename provided, defaulting to", filename)
+				}
+
+				if err := gbc.saveProfile(filename); err != nil {
+					fmt.Println("Could not save CPU profile to", filename)
+					fmt.Println("\t", err)
+					return
+				}
+				fmt.Println("Saved CPU profile to", filename, "- view it with: go tool pprof -top", filename)
+				return
+			default:
+				n, err := strconv.Atoi(remaining[0])
+				if err != nil {
+					fmt.Println("Could not parse number of hot spots to show:", remaining[0])
+					return
+				}
+				top = n
+			}
+		}
+
+		gbc.profiler.WriteReport(os.Stdout, FRAME_CYCLES, top)
+	})
+
 	g.AddDebugFunc("q", "Quit emulator", func(gbc *GomeboyColor, remaining ...string) {
 		os.Exit(0)
 	})
@@ -275,6 +513,18 @@ func (g *DebugOptions) checkWatches(gbc *GomeboyColor) {
 	}
 }
 
+func parseSoundChannel(args []string) (int, error) {
+	if len(args) == 0 {
+		return 0, errors.New("You must provide a sound channel (1-4)!")
+	}
+
+	channel, err := strconv.Atoi(args[0])
+	if err != nil || channel < 1 || channel > 4 {
+		return 0, errors.New("Sound channel must be between 1 and 4")
+	}
+	return channel, nil
+}
+
 func ToMemoryAddress(s string) (types.Word, error) {
 	if len(s) > 4 {
 		return 0x0, errors.New("Please enter an address between 0000 and FFFF")
@@ -360,3 +610,42 @@ func TilemapToImage(img [256][256]types.RGB, caption string) (*image.NRGBA, erro
 	}
 	return out, nil
 }
+
+//Each level of the call stack, innermost first. A level shows where execution is (or will
+//return to) and the call or interrupt that entered the routine it is in
+func (gbc *GomeboyColor) backtrace() string {
+	var stack []cpu.CallFrame = gbc.cpu.CallStack()
+	var pc types.Word = gbc.cpu.PC
+	var bank int = gbc.cart.ROMBank(pc)
+
+	var b strings.Builder
+	for i := len(stack); i >= 0; i-- {
+		fmt.Fprintf(&b, "#%-3d %02X:%04X", len(stack)-i, bank, uint16(pc))
+		if i > 0 {
+			fmt.Fprintf(&b, "  %s", stack[i-1])
+			pc, bank = stack[i-1].CallSite, stack[i-1].CallSiteBank
+		}
+		fmt.Fprintln(&b)
+	}
+	return b.String()
+}
+
+//Assembles source at addr and writes the machine code over whatever is there, ROM is changed
+//directly rather than written through the memory bank controller. Returns the first
+//instruction as it now disassembles
+func (gbc *GomeboyColor) patch(addr types.Word, source string) (disasm.Instruction, error) {
+	code, err := asm.Assemble(source, addr)
+	if err != nil {
+		return disasm.Instruction{}, err
+	}
+
+	for i, b := range code {
+		var a types.Word = addr + types.Word(i)
+		if a < 0x8000 {
+			gbc.cart.PatchROM(a, b)
+		} else {
+			gbc.mmu.WriteByte(a, b)
+		}
+	}
+	return disasm.DecodeAt(gbc.mmu, addr), nil
+}
@@ -0,0 +1,50 @@
+package gbc
+
+import (
+	"testing"
+
+	"github.com/stretchrcom/testify/assert"
+)
+
+func TestBacktrace(t *testing.T) {
+	gbc, io := newTestROMEmulator(bankedTestROM(t), false)
+	defer close(io.screen)
+
+	gbc.cpu.TrackCallStack(true)
+	_, timedOut := gbc.runTestROM(20000, func() bool {
+		return gbc.cpu.PC == 0x4010
+	})
+	assert.False(t, timedOut)
+	assert.Equal(t, "#0   02:4010  CALL 02:4010\n#1   00:0158\n", gbc.backtrace())
+}
+
+func TestPatchAssemblesOverROMAndRAM(t *testing.T) {
+	gbc, io := newTestROMEmulator(bankedTestROM(t), false)
+	defer close(io.screen)
+
+	instr, err := gbc.patch(0x0150, "LD A,0x03")
+	assert.Nil(t, err)
+	assert.Equal(t, "LD A,0x03", instr.String())
+	assert.Equal(t, byte(0x3E), gbc.mmu.ReadByte(0x0150))
+	assert.Equal(t, byte(0x03), gbc.mmu.ReadByte(0x0151))
+
+	//the ROM is patched in the bank that is switched in rather than the MBC's registers written
+	_, timedOut := gbc.runTestROM(20000, func() bool {
+		return gbc.cpu.PC == 0x4010
+	})
+	assert.False(t, timedOut)
+	assert.Equal(t, 3, gbc.cart.ROMBank(0x4010))
+	instr, err = gbc.patch(0x4010, "INC B")
+	assert.Nil(t, err)
+	assert.Equal(t, "INC B", instr.String())
+	assert.Equal(t, 3, gbc.cart.ROMBank(0x4010))
+
+	instr, err = gbc.patch(0xC000, "JP 0x0150")
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{0xC3, 0x50, 0x01}, instr.Bytes)
+	assert.Equal(t, byte(0x01), gbc.mmu.ReadByte(0xC002))
+
+	_, err = gbc.patch(0x0150, "LD Q,A")
+	assert.NotNil(t, err)
+	assert.Equal(t, byte(0x3E), gbc.mmu.ReadByte(0x0150))
+}
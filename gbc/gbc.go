@@ -2,47 +2,68 @@ package gbc
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"log"
 	"os"
 	"strings"
+	"sync"
 
 	"github.com/djhworld/gomeboycolor/apu"
 	"github.com/djhworld/gomeboycolor/cartridge"
+	"github.com/djhworld/gomeboycolor/cdl"
 	"github.com/djhworld/gomeboycolor/config"
 	"github.com/djhworld/gomeboycolor/cpu"
 	"github.com/djhworld/gomeboycolor/dma"
 	"github.com/djhworld/gomeboycolor/gpu"
 	"github.com/djhworld/gomeboycolor/inputoutput"
 	"github.com/djhworld/gomeboycolor/mmu"
+	"github.com/djhworld/gomeboycolor/profiler"
 	"github.com/djhworld/gomeboycolor/saves"
+	"github.com/djhworld/gomeboycolor/serial"
 	"github.com/djhworld/gomeboycolor/timer"
 	"github.com/djhworld/gomeboycolor/types"
 	"github.com/djhworld/gomeboycolor/utils"
 )
 
+//cycles of the 4.19MHz system clock in one frame, this isn't affected by CGB double speed mode
 const FRAME_CYCLES = 70224
+
+//Sample rate used when recording audio without an audio sink (e.g. headless mode)
+const DEFAULT_SAMPLE_RATE = 44100
 const TITLE string = "gomeboycolor"
 
 var VERSION string
 
 type GomeboyColor struct {
-	gpu          *gpu.GPU
-	cpu          *cpu.GbcCPU
-	mmu          *mmu.GbcMMU
-	hDMA         *dma.HDMA
-	oamDMA       *dma.OAMDMA
-	io           inputoutput.IOHandler
-	apu          *apu.APU
-	timer        *timer.Timer
-	debugOptions *DebugOptions
-	config       *config.Config
-	cart         *cartridge.Cartridge
-	saveStore    saves.Store
-	cpuClockAcc  int
-	stepCount    int
-	inBootMode   bool
-	stopped      bool
+	gpu               *gpu.GPU
+	cpu               *cpu.GbcCPU
+	mmu               *mmu.GbcMMU
+	hDMA              *dma.HDMA
+	oamDMA            *dma.OAMDMA
+	io                inputoutput.IOHandler
+	apu               *apu.APU
+	timer             *timer.Timer
+	serial            *serial.Serial
+	debugOptions      *DebugOptions
+	config            *config.Config
+	cart              *cartridge.Cartridge
+	saveStore         saves.Store
+	recording         *audioRecording
+	channelRecordings [4]*audioRecording
+	vgmRecorder       *apu.VGMRecorder
+	vgmFilename       string
+	traceFile         *os.File
+	tracer            *cpu.Tracer
+	codeDataLog       *cdl.Logger
+	profiler          *profiler.Profiler
+	lockupHandler     func(err error)
+	recorderLock      sync.Mutex
+	cpuClockAcc       int
+	apuCycles         int //CPU cycles the APU is behind by, see apuPort
+	stepCount         int
+	inBootMode        bool
+	stopped           bool
 }
 
 func Init(cart *cartridge.Cartridge, saveStore saves.Store, conf *config.Config, ioHandler inputoutput.IOHandler) (*GomeboyColor, error) {
@@ -63,6 +84,7 @@ func Init(cart *cartridge.Cartridge, saveStore saves.Store, conf *config.Config,
 	if gbc.config.Debug {
 		log.Println("Emulator will start in debug mode")
 		gbc.debugOptions.debuggerOn = true
+		gbc.cpu.TrackCallStack(true)
 
 		//set breakpoint if defined
 		if b, err := utils.StringToWord(gbc.config.BreakOn); err != nil {
@@ -91,6 +113,33 @@ func Init(cart *cartridge.Cartridge, saveStore saves.Store, conf *config.Config,
 		log.Fatalln("io init failure\n\t", err)
 	}
 
+	sampleRate := gbc.io.GetAudioSampleRate()
+	if gbc.config.AudioRecordFile != "" {
+		if sampleRate == 0 {
+			sampleRate = DEFAULT_SAMPLE_RATE
+		}
+		if err := gbc.startRecording(gbc.config.AudioRecordFile, sampleRate); err != nil {
+			return nil, err
+		}
+	}
+	gbc.apu.SetSampleRate(sampleRate)
+
+	if gbc.config.TraceFile != "" {
+		if err := gbc.startTrace(gbc.config.TraceFile); err != nil {
+			return nil, err
+		}
+	}
+
+	if gbc.config.CodeDataLogFile != "" {
+		if err := gbc.startCodeDataLog(gbc.config.CodeDataLogFile); err != nil {
+			return nil, err
+		}
+	}
+
+	if gbc.config.ProfileFile != "" {
+		gbc.startProfiling()
+	}
+
 	log.Println("Completed setup")
 	log.Println(strings.Repeat("*", 120))
 
@@ -108,36 +157,105 @@ func (gbc *GomeboyColor) Run(frameRunnerWrapper func(func())) {
 	}
 }
 
+//Sets a function to be called when the CPU locks up after executing an illegal opcode. The
+//error is a *cpu.IllegalOpcodeError. The emulator keeps running (with the CPU hung, as on
+//hardware), so a crashed ROM can be detected and the emulator stopped
+func (gbc *GomeboyColor) SetLockupHandler(h func(err error)) {
+	gbc.lockupHandler = h
+}
+
+//Returns why the CPU has locked up, or nil if it is still running
+func (gbc *GomeboyColor) Err() error {
+	if err := gbc.cpu.LockupError(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (gbc *GomeboyColor) onLockup(err *cpu.IllegalOpcodeError) {
+	if gbc.lockupHandler != nil {
+		gbc.lockupHandler(err)
+	}
+}
+
 func (gbc *GomeboyColor) RunIO() {
 	gbc.io.Run()
 }
 
+//Runs a single instruction (or HDMA block). The rest of the system is advanced by tick as the
+//CPU spends each M-cycle, so memory accesses land on the right cycle relative to the GPU, APU,
+//timer and DMA
 func (gbc *GomeboyColor) Step() {
-	cycles := 0x00
-
 	if gbc.hDMA.IsRunning() {
 		gbc.hDMA.Step()
+		//the CPU is paused while the block is copied
+		gbc.cpu.Idle(dma.HDMA_BLOCK_CYCLES * gbc.cpu.Speed)
 	} else {
-		cycles = gbc.cpu.Step()
+		gbc.cpu.Step()
 	}
-	//GPU is unaffected by CPU speed changes
-	gbc.gpu.Step(cycles)
-	gbc.cpuClockAcc += cycles
-
-	//these are affected by CPU speed changes
-	gbc.oamDMA.Step(cycles / gbc.cpu.Speed)
 
 	gbc.stepCount++
 
 	gbc.checkBootModeStatus()
 }
 
+//Advances everything that runs alongside the CPU by a number of M-cycles (the timer is ticked
+//by the CPU itself)
+func (gbc *GomeboyColor) tick(cycles int) {
+	//GPU is unaffected by CPU speed changes, it runs off the system clock
+	var systemCycles int = cycles * 4 / gbc.cpu.Speed
+	gbc.gpu.Step(systemCycles)
+
+	//nothing sees the APU between accesses to its registers, so it's only caught up then
+	gbc.apuCycles += cycles * 4
+
+	//these are doubled along with the CPU so run off the same cycles
+	gbc.serial.Step(cycles)
+	gbc.oamDMA.Step(cycles)
+
+	gbc.cpuClockAcc += systemCycles
+}
+
+//Runs the APU for the cycles it has fallen behind the CPU by
+func (gbc *GomeboyColor) catchUpAPU() {
+	if gbc.apuCycles > 0 {
+		gbc.apu.Step(gbc.apuCycles)
+		gbc.apuCycles = 0
+	}
+}
+
+//APU converts CPU cycles to its own clock itself so that no cycles are lost in double speed mode,
+//the cycles run at the old speed have to be passed on before it changes
+func (gbc *GomeboyColor) onSpeedChange(speed int) {
+	gbc.catchUpAPU()
+	gbc.apu.SetCPUSpeed(speed)
+}
+
+//Connects the APU registers to the MMU, catching the APU up with the CPU before every access so
+//that reads and writes land on the cycle they are made on
+type apuPort struct {
+	*apu.APU
+	gbc *GomeboyColor
+}
+
+func (p apuPort) Read(address types.Word) byte {
+	p.gbc.catchUpAPU()
+	return p.APU.Read(address)
+}
+
+func (p apuPort) Write(address types.Word, value byte) {
+	p.gbc.catchUpAPU()
+	p.APU.Write(address, value)
+}
+
 func (gbc *GomeboyColor) Reset() {
 	log.Println("Resetting system")
 	gbc.cpu.Reset()
 	gbc.gpu.Reset()
 	gbc.mmu.Reset()
 	gbc.apu.Reset()
+	gbc.apuCycles = 0
+	gbc.serial.Reset()
 	gbc.io.GetKeyHandler().Reset()
 	gbc.setupBoot()
 }
@@ -153,8 +271,13 @@ func newGomeboyColor(cart *cartridge.Cartridge, conf *config.Config, saveStore s
 	gbc.timer = timer.NewTimer()
 	gbc.mmu = mmu.NewGbcMMU()
 	gbc.cpu = cpu.NewCPU(gbc.mmu, gbc.timer)
+	gbc.cpu.LinkClockHandler(gbc.tick)
+	gbc.cpu.LinkSpeedChangeHandler(gbc.onSpeedChange)
+	gbc.cpu.LinkBankMapper(gbc.cart.ROMBank)
+	gbc.cpu.LinkLockupHandler(gbc.onLockup)
 	gbc.hDMA = dma.NewHDMA(gbc.mmu)
 	gbc.oamDMA = dma.NewOAMDMA(gbc.mmu)
+	gbc.serial = serial.NewSerial()
 	gbc.stopped = false
 
 	gbc.gpu = gpu.NewGPU()
@@ -165,9 +288,12 @@ func newGomeboyColor(cart *cartridge.Cartridge, conf *config.Config, saveStore s
 	//mmu will process interrupt requests from GPU (i.e. it will set appropriate flags)
 	gbc.gpu.LinkIRQHandler(gbc.mmu)
 	gbc.timer.LinkIRQHandler(gbc.mmu)
+	gbc.serial.LinkIRQHandler(gbc.mmu)
 	gbc.io.GetKeyHandler().LinkIRQHandler(gbc.mmu)
 
-	gbc.mmu.ConnectPeripheral(gbc.apu, 0xFF10, 0xFF3F)
+	gbc.mmu.ConnectPeripheral(apuPort{gbc.apu, gbc}, 0xFF10, 0xFF3F)
+	gbc.mmu.ConnectCGBPeripheralOn(apuPort{gbc.apu, gbc}, apu.PCM12, apu.PCM34)
+	gbc.mmu.ConnectCGBPeripheralOn(gbc.cpu.SpeedSwitch, mmu.CGB_DOUBLE_SPEED_PREP_REG)
 	gbc.mmu.ConnectPeripheral(gbc.gpu, 0x8000, 0x9FFF)
 	gbc.mmu.ConnectPeripheral(gbc.gpu, 0xFE00, 0xFE9F)
 	gbc.mmu.ConnectPeripheral(gbc.gpu, 0xFF57, 0xFF6F)
@@ -176,6 +302,7 @@ func newGomeboyColor(cart *cartridge.Cartridge, conf *config.Config, saveStore s
 	gbc.mmu.ConnectPeripheralOn(gbc.gpu, 0xFF40, 0xFF41, 0xFF42, 0xFF43, 0xFF44, 0xFF45, 0xFF47, 0xFF48, 0xFF49, 0xFF4A, 0xFF4B, 0xFF4F)
 	gbc.mmu.ConnectPeripheralOn(gbc.io.GetKeyHandler(), 0xFF00)
 	gbc.mmu.ConnectPeripheralOn(gbc.timer, 0xFF04, 0xFF05, 0xFF06, 0xFF07)
+	gbc.mmu.ConnectPeripheralOn(gbc.serial, serial.SB_REGISTER, serial.SC_REGISTER)
 
 	return gbc
 }
@@ -184,7 +311,7 @@ func (gbc *GomeboyColor) doFrame() {
 	for gbc.cpuClockAcc < FRAME_CYCLES {
 		gbc.Step()
 	}
-
+	gbc.pushAudio()
 }
 
 func (gbc *GomeboyColor) doFrameWithDebug() {
@@ -198,6 +325,36 @@ func (gbc *GomeboyColor) doFrameWithDebug() {
 		}
 		gbc.Step()
 	}
+	gbc.pushAudio()
+}
+
+//Sends the audio generated during the last frame to the IO loop. If the IO loop
+//has fallen behind the batch is dropped rather than stalling the emulator
+func (gbc *GomeboyColor) pushAudio() {
+	gbc.catchUpAPU()
+
+	//the recorders are stopped from the IO loop when the emulator is closed
+	gbc.recorderLock.Lock()
+	for i, r := range gbc.channelRecordings {
+		if r != nil {
+			r.sink.PlaySamples(gbc.apu.ChannelSamples(i + 1))
+		}
+	}
+
+	samples := gbc.apu.Samples()
+	if gbc.recording != nil {
+		gbc.recording.sink.PlaySamples(samples)
+	}
+	gbc.recorderLock.Unlock()
+
+	if len(samples) == 0 {
+		return
+	}
+
+	select {
+	case gbc.io.GetAudioOutputChannel() <- samples:
+	default:
+	}
 }
 
 func (gbc *GomeboyColor) setupBoot() {
@@ -234,12 +391,16 @@ func (gbc *GomeboyColor) checkBootModeStatus() {
 func (gbc *GomeboyColor) setHardwareMode(isColor bool) {
 	if isColor {
 		gbc.cpu.R.A = 0x11
+		gbc.cpu.RunningColorGBHardware = true
 		gbc.gpu.RunningColorGBHardware = gbc.mmu.IsCartridgeColor()
 		gbc.mmu.RunningColorGBHardware = true
+		gbc.apu.RunningColorGBHardware = true
 	} else {
 		gbc.cpu.R.A = 0x01
+		gbc.cpu.RunningColorGBHardware = false
 		gbc.gpu.RunningColorGBHardware = false
 		gbc.mmu.RunningColorGBHardware = false
+		gbc.apu.RunningColorGBHardware = false
 	}
 }
 
@@ -259,6 +420,8 @@ func (gbc *GomeboyColor) setupWithoutBoot() {
 	gbc.mmu.WriteByte(0xFF05, 0x00)
 	gbc.mmu.WriteByte(0xFF06, 0x00)
 	gbc.mmu.WriteByte(0xFF07, 0x00)
+	//sound must be powered on before the other sound registers can be written
+	gbc.mmu.WriteByte(0xFF26, 0xF1)
 	gbc.mmu.WriteByte(0xFF10, 0x80)
 	gbc.mmu.WriteByte(0xFF11, 0xBF)
 	gbc.mmu.WriteByte(0xFF12, 0xF3)
@@ -276,7 +439,6 @@ func (gbc *GomeboyColor) setupWithoutBoot() {
 	gbc.mmu.WriteByte(0xFF23, 0xBF)
 	gbc.mmu.WriteByte(0xFF24, 0x77)
 	gbc.mmu.WriteByte(0xFF25, 0xF3)
-	gbc.mmu.WriteByte(0xFF26, 0xF1)
 	gbc.mmu.WriteByte(0xFF40, 0x91)
 	gbc.mmu.WriteByte(0xFF42, 0x00)
 	gbc.mmu.WriteByte(0xFF43, 0x00)
@@ -290,11 +452,294 @@ func (gbc *GomeboyColor) setupWithoutBoot() {
 	gbc.mmu.WriteByte(0xFFFF, 0x00)
 }
 
+//A WAV file that audio samples are being recorded to
+type audioRecording struct {
+	file *os.File
+	sink *inputoutput.WAVSink
+}
+
+func newAudioRecording(filename string, sampleRate int) (*audioRecording, error) {
+	f, err := os.Create(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	sink, err := inputoutput.NewWAVSink(f, sampleRate)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &audioRecording{f, sink}, nil
+}
+
+func (r *audioRecording) close() {
+	log.Println("Finished recording audio to", r.file.Name())
+	r.sink.Stop()
+	r.file.Close()
+}
+
+func (gbc *GomeboyColor) startRecording(filename string, sampleRate int) error {
+	log.Println("Recording audio to", filename)
+	recording, err := newAudioRecording(filename, sampleRate)
+	if err != nil {
+		return err
+	}
+
+	gbc.recording = recording
+	return nil
+}
+
+//Stops the recording of the mixed output and of every channel
+func (gbc *GomeboyColor) stopRecording() {
+	gbc.recorderLock.Lock()
+	defer gbc.recorderLock.Unlock()
+	if gbc.recording != nil {
+		gbc.recording.close()
+		gbc.recording = nil
+	}
+
+	for i, r := range gbc.channelRecordings {
+		if r != nil {
+			gbc.apu.SetChannelCapture(i+1, false)
+			r.close()
+			gbc.channelRecordings[i] = nil
+		}
+	}
+
+	if gbc.vgmRecorder != nil {
+		if err := gbc.saveVGMRecording(); err != nil {
+			log.Println("Could not save VGM recording:", err)
+		}
+	}
+}
+
+//Records a single sound channel (1-4) to its own WAV file
+func (gbc *GomeboyColor) startChannelRecording(channel int, filename string) error {
+	gbc.recorderLock.Lock()
+	defer gbc.recorderLock.Unlock()
+
+	if gbc.apu.IsChannelCaptured(channel) {
+		return fmt.Errorf("Channel %d is already being recorded", channel)
+	}
+
+	//without an audio sink samples are not normally generated
+	if gbc.apu.SampleRate() == 0 {
+		gbc.apu.SetSampleRate(DEFAULT_SAMPLE_RATE)
+	}
+
+	if err := gbc.apu.SetChannelCapture(channel, true); err != nil {
+		return err
+	}
+
+	log.Println("Recording channel", channel, "to", filename)
+	recording, err := newAudioRecording(filename, gbc.apu.SampleRate())
+	if err != nil {
+		gbc.apu.SetChannelCapture(channel, false)
+		return err
+	}
+
+	gbc.channelRecordings[channel-1] = recording
+	return nil
+}
+
+func (gbc *GomeboyColor) stopChannelRecording(channel int) error {
+	gbc.recorderLock.Lock()
+	defer gbc.recorderLock.Unlock()
+
+	if !gbc.apu.IsChannelCaptured(channel) {
+		return fmt.Errorf("Channel %d is not being recorded", channel)
+	}
+
+	gbc.apu.SetChannelCapture(channel, false)
+	gbc.channelRecordings[channel-1].close()
+	gbc.channelRecordings[channel-1] = nil
+	return nil
+}
+
+//Logs every write to the APU so it can be saved as a VGM file
+func (gbc *GomeboyColor) startVGMRecording(filename string) error {
+	gbc.recorderLock.Lock()
+	defer gbc.recorderLock.Unlock()
+	if gbc.vgmRecorder != nil {
+		return fmt.Errorf("Already recording APU writes to %s", gbc.vgmFilename)
+	}
+
+	log.Println("Recording APU writes to", filename)
+	gbc.vgmRecorder = apu.NewVGMRecorder(gbc.apu)
+	gbc.vgmFilename = filename
+	gbc.apu.RegisterObserver(gbc.vgmRecorder)
+	return nil
+}
+
+func (gbc *GomeboyColor) stopVGMRecording() error {
+	gbc.recorderLock.Lock()
+	defer gbc.recorderLock.Unlock()
+	if gbc.vgmRecorder == nil {
+		return errors.New("APU writes are not being recorded")
+	}
+
+	gbc.apu.UnregisterObserver(gbc.vgmRecorder)
+	return gbc.saveVGMRecording()
+}
+
+//the recorder lock must be held, the recorder is left registered with the APU when this is
+//called from the IO loop but ignores any writes once stopped
+func (gbc *GomeboyColor) saveVGMRecording() error {
+	gbc.vgmRecorder.Stop(gbc.apu.Clock())
+	defer func() {
+		gbc.vgmRecorder = nil
+		gbc.vgmFilename = ""
+	}()
+
+	f, err := os.Create(gbc.vgmFilename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	log.Println("Finished recording APU writes to", gbc.vgmFilename)
+	return gbc.vgmRecorder.WriteVGM(f)
+}
+
+//Writes a gameboy-doctor compatible trace of every executed instruction to a file
+func (gbc *GomeboyColor) startTrace(filename string) error {
+	gbc.recorderLock.Lock()
+	defer gbc.recorderLock.Unlock()
+	if gbc.tracer != nil {
+		return fmt.Errorf("Already tracing CPU to %s", gbc.traceFile.Name())
+	}
+
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+
+	log.Println("Tracing CPU to", filename)
+	gbc.traceFile = f
+	gbc.tracer = cpu.NewTracer(f)
+	gbc.cpu.SetTracer(gbc.tracer)
+	return nil
+}
+
+//the tracer is left attached to the CPU when this is called from the IO loop but ignores
+//any instructions once stopped
+func (gbc *GomeboyColor) stopTrace() error {
+	gbc.recorderLock.Lock()
+	defer gbc.recorderLock.Unlock()
+	if gbc.tracer == nil {
+		return errors.New("CPU is not being traced")
+	}
+
+	err := gbc.tracer.Stop()
+	if closeErr := gbc.traceFile.Close(); err == nil {
+		err = closeErr
+	}
+
+	log.Println("Finished tracing CPU to", gbc.traceFile.Name())
+	gbc.tracer = nil
+	gbc.traceFile = nil
+	return err
+}
+
+//Starts logging how each byte of the ROM is accessed. If mergeFrom holds a log from an
+//earlier session it is merged in so coverage builds up over time
+func (gbc *GomeboyColor) startCodeDataLog(mergeFrom string) error {
+	if gbc.codeDataLog != nil {
+		return errors.New("Already logging code/data accesses")
+	}
+
+	l := cdl.NewLogger(gbc.cart.ROMSize)
+	if mergeFrom != "" {
+		if f, err := os.Open(mergeFrom); err == nil {
+			defer f.Close()
+			if err := l.Import(f); err != nil {
+				return err
+			}
+			log.Println("Merged existing code/data log from", mergeFrom)
+		} else if !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	log.Println("Logging code/data accesses")
+	gbc.codeDataLog = l
+	gbc.cart.SetCodeDataLogger(l)
+	gbc.cpu.SetCodeDataLogger(l)
+	gbc.hDMA.SetCodeDataLogger(l)
+	gbc.oamDMA.SetCodeDataLogger(l)
+	return nil
+}
+
+//Writes the code/data log out as a CDL file, logging carries on afterwards
+func (gbc *GomeboyColor) saveCodeDataLog(filename string) error {
+	if gbc.codeDataLog == nil {
+		return errors.New("Code/data accesses are not being logged")
+	}
+
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	log.Println("Saving code/data log to", filename)
+	return gbc.codeDataLog.Export(f)
+}
+
+//Starts profiling where the CPU spends its time, does nothing if it is already being profiled
+func (gbc *GomeboyColor) startProfiling() {
+	if gbc.profiler != nil {
+		return
+	}
+
+	log.Println("Profiling CPU")
+	gbc.profiler = profiler.NewProfiler()
+	gbc.cpu.SetProfiler(gbc.profiler)
+}
+
+func (gbc *GomeboyColor) stopProfiling() {
+	log.Println("Stopped profiling CPU")
+	gbc.cpu.SetProfiler(nil)
+	gbc.profiler = nil
+}
+
+//Writes the profile collected so far as a pprof profile, profiling carries on afterwards
+func (gbc *GomeboyColor) saveProfile(filename string) error {
+	if gbc.profiler == nil {
+		return errors.New("CPU is not being profiled")
+	}
+
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	log.Println("Saving CPU profile to", filename)
+	return gbc.profiler.WritePprof(f)
+}
+
 func (gbc *GomeboyColor) onClose() {
 	//TODO need to figure this bit out (handle errors?)
 	w, _ := gbc.saveStore.Create(gbc.cart.ID)
 	defer w.Close()
 	gbc.mmu.SaveCartridgeRam(w)
+	gbc.stopRecording()
+	if gbc.tracer != nil {
+		if err := gbc.stopTrace(); err != nil {
+			log.Println("Could not save CPU trace:", err)
+		}
+	}
+	if gbc.config.CodeDataLogFile != "" {
+		if err := gbc.saveCodeDataLog(gbc.config.CodeDataLogFile); err != nil {
+			log.Println("Could not save code/data log:", err)
+		}
+	}
+	if gbc.config.ProfileFile != "" {
+		if err := gbc.saveProfile(gbc.config.ProfileFile); err != nil {
+			log.Println("Could not save CPU profile:", err)
+		}
+	}
 	gbc.stopped = true
 }
 
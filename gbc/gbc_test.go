@@ -0,0 +1,74 @@
+package gbc
+
+import (
+	"testing"
+
+	"github.com/djhworld/gomeboycolor/apu"
+	"github.com/djhworld/gomeboycolor/dma"
+	"github.com/stretchrcom/testify/assert"
+)
+
+//The OAM DMA is run by the clock handler, so a transfer that finishes partway through an
+//instruction is seen by the accesses the instruction makes after that point. LD A,(a16) reads
+//the address on its fourth M-cycle
+func TestOAMDMACompletesPartwayThroughInstruction(t *testing.T) {
+	var tests = []struct {
+		remaining int
+		expected  byte
+	}{
+		{4, 0x42},
+		{5, 0x00},
+	}
+
+	for _, test := range tests {
+		cart := assembleTestROM(t, "oamdma.gb", `
+			ORG 0x0100
+			LD A,(0xFE9F)
+		`)
+		gbc, io := newTestROMEmulator(cart, false)
+
+		gbc.mmu.WriteByte(0xC09F, 0x42)
+		gbc.mmu.WriteByte(dma.DMA_TRANSFER, 0xC0)
+		gbc.oamDMA.Step(dma.OAM_DMA_CYCLES - test.remaining)
+
+		gbc.Step()
+		assert.Equal(t, test.expected, gbc.cpu.R.A, "DMA with %d M-cycles left", test.remaining)
+		assert.Equal(t, test.remaining > 4, gbc.oamDMA.IsRunning(), "DMA with %d M-cycles left", test.remaining)
+		close(io.screen)
+	}
+}
+
+//PCM12 and PCM34 only exist on CGB hardware, on a DMG they are plain unused registers and the
+//channel outputs can't be seen
+func TestPCMRegistersOnlyReadChannelsOnCGB(t *testing.T) {
+	for _, colorMode := range []bool{false, true} {
+		cart := assembleTestROM(t, "pcm.gb", `
+			ORG 0x0100
+		loop:
+			JR loop
+		`)
+		gbc, io := newTestROMEmulator(cart, colorMode)
+
+		//a 50% duty square wave at full volume on channel 1
+		gbc.mmu.WriteByte(apu.NR52, 0x80)
+		gbc.mmu.WriteByte(apu.NR11, 0x80)
+		gbc.mmu.WriteByte(apu.NR12, 0xF0)
+		gbc.mmu.WriteByte(apu.NR14, 0x87)
+		gbc.mmu.WriteByte(apu.PCM12, 0x5A)
+
+		var seen map[byte]bool = make(map[byte]bool)
+		for i := 0; i < 1000; i++ {
+			gbc.Step()
+			seen[gbc.mmu.ReadByte(apu.PCM12)] = true
+		}
+
+		if colorMode {
+			//the write is ignored and the reads follow the wave
+			assert.Equal(t, map[byte]bool{0x00: true, 0x0F: true}, seen, "CGB")
+		} else {
+			assert.Len(t, seen, 1, "DMG")
+			assert.False(t, seen[0x0F], "DMG")
+		}
+		close(io.screen)
+	}
+}
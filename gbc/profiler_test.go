@@ -0,0 +1,30 @@
+package gbc
+
+import (
+	"testing"
+
+	"github.com/djhworld/gomeboycolor/profiler"
+	"github.com/stretchrcom/testify/assert"
+)
+
+func TestProfilerFollowsCalls(t *testing.T) {
+	gbc, io := newTestROMEmulator(bankedTestROM(t), false)
+	defer close(io.screen)
+
+	gbc.startProfiling()
+	cycles, _ := gbc.runTestROM(20000, func() bool {
+		return false
+	})
+	assert.Equal(t, cycles, gbc.profiler.Total())
+
+	var called profiler.FunctionProfile
+	for _, f := range gbc.profiler.Functions() {
+		if f.Function == (profiler.Location{Bank: 2, PC: 0x4010}) {
+			called = f
+		}
+	}
+	assert.Equal(t, 1, called.Calls)
+	//just the RET
+	assert.Equal(t, 16, called.Self)
+	assert.Empty(t, gbc.cpu.CallStack())
+}
@@ -0,0 +1,184 @@
+package gbc
+
+import (
+	"bytes"
+
+	"github.com/djhworld/gomeboycolor/cartridge"
+	"github.com/djhworld/gomeboycolor/config"
+	"github.com/djhworld/gomeboycolor/cpu"
+	"github.com/djhworld/gomeboycolor/inputoutput"
+	"github.com/djhworld/gomeboycolor/types"
+)
+
+//Strings Blargg's test ROMs print over the serial port once they have finished
+const (
+	SERIAL_TEST_PASSED string = "Passed"
+	SERIAL_TEST_FAILED string = "Failed"
+)
+
+//Opcode of LD B,B, used by mooneye's test ROMs as a software breakpoint to signal they have finished
+const MOONEYE_BREAKPOINT_OPCODE byte = 0x40
+
+//Values in B, C, D, E, H and L when a mooneye test passes, on failure they are all 0x42
+var MOONEYE_PASS_SIGNATURE [6]byte = [6]byte{3, 5, 8, 13, 21, 34}
+var MOONEYE_FAIL_SIGNATURE [6]byte = [6]byte{0x42, 0x42, 0x42, 0x42, 0x42, 0x42}
+
+//Outcome of running a test ROM headlessly
+type TestROMResult struct {
+	Passed   bool
+	TimedOut bool
+
+	//cycles of the 4.19MHz system clock that were run (the same units as FRAME_CYCLES)
+	Cycles int
+
+	//everything the ROM wrote out over the serial port
+	Output string
+
+	//state of the registers when the ROM finished
+	Registers cpu.Registers
+
+	//set when the ROM crashed by executing an illegal opcode, the test is stopped straight away
+	Err error
+}
+
+//IO handler used when running test ROMs, nothing is displayed and no input is ever given
+type testROMIO struct {
+	keyHandler *inputoutput.KeyHandler
+	screen     chan *types.Screen
+	audio      chan []int16
+}
+
+func newTestROMIO() *testROMIO {
+	return &testROMIO{
+		keyHandler: new(inputoutput.KeyHandler),
+		screen:     make(chan *types.Screen),
+		audio:      make(chan []int16, inputoutput.AUDIO_BUFFER_FRAMES),
+	}
+}
+
+func (i *testROMIO) Init(title string, screenSize int, onCloseHandler func()) error {
+	return nil
+}
+
+func (i *testROMIO) GetKeyHandler() *inputoutput.KeyHandler {
+	return i.keyHandler
+}
+
+func (i *testROMIO) GetScreenOutputChannel() chan *types.Screen {
+	return i.screen
+}
+
+func (i *testROMIO) GetAudioOutputChannel() chan []int16 {
+	return i.audio
+}
+
+func (i *testROMIO) GetAudioSampleRate() int {
+	return 0
+}
+
+func (i *testROMIO) GetAvgFrameRate() float32 {
+	return 0
+}
+
+//frames are thrown away as soon as the GPU sends them so it never blocks
+func (i *testROMIO) Run() {
+	for range i.screen {
+	}
+}
+
+//Collects the bytes written to the serial port and watches for the end of a Blargg test
+type serialCapture struct {
+	output   bytes.Buffer
+	finished bool
+	passed   bool
+}
+
+func (s *serialCapture) OnSerialTransfer(value byte) {
+	s.output.WriteByte(value)
+	if bytes.HasSuffix(s.output.Bytes(), []byte(SERIAL_TEST_PASSED)) {
+		s.finished, s.passed = true, true
+	} else if bytes.HasSuffix(s.output.Bytes(), []byte(SERIAL_TEST_FAILED)) {
+		s.finished = true
+	}
+}
+
+//Boots a cartridge without a display or the boot ROM, ready for running a test ROM
+func newTestROMEmulator(cart *cartridge.Cartridge, colorMode bool) (*GomeboyColor, *testROMIO) {
+	conf := &config.Config{
+		Title:      TITLE,
+		ScreenSize: 1,
+		SkipBoot:   true,
+		ColorMode:  colorMode,
+		Headless:   true,
+	}
+
+	io := newTestROMIO()
+	gbc := newGomeboyColor(cart, conf, nil, io)
+	gbc.mmu.LoadCartridge(gbc.cart)
+	gbc.debugOptions.Init(false)
+	gbc.gpu.LinkScreen(io.GetScreenOutputChannel())
+	gbc.setupBoot()
+	go io.Run()
+	return gbc, io
+}
+
+//Steps the emulator until done returns true, the CPU locks up or maxCycles have been run,
+//returning the number of cycles executed and whether the budget ran out
+func (gbc *GomeboyColor) runTestROM(maxCycles int, done func() bool) (int, bool) {
+	gbc.cpuClockAcc = 0
+	for gbc.cpuClockAcc < maxCycles {
+		gbc.Step()
+		if done() || gbc.cpu.Locked {
+			return gbc.cpuClockAcc, false
+		}
+	}
+	return gbc.cpuClockAcc, true
+}
+
+//Runs a test ROM that reports its result over the serial port (e.g. Blargg's cpu_instrs,
+//instr_timing and mem_timing). The ROM is run until it prints "Passed" or "Failed" or
+//maxCycles system clock cycles have run
+func RunSerialTestROM(cart *cartridge.Cartridge, colorMode bool, maxCycles int) *TestROMResult {
+	gbc, io := newTestROMEmulator(cart, colorMode)
+	defer close(io.screen)
+
+	capture := new(serialCapture)
+	gbc.serial.RegisterObserver(capture)
+
+	cycles, timedOut := gbc.runTestROM(maxCycles, func() bool {
+		return capture.finished
+	})
+
+	return &TestROMResult{
+		Passed:    capture.passed,
+		TimedOut:  timedOut,
+		Cycles:    cycles,
+		Output:    capture.output.String(),
+		Registers: gbc.cpu.R,
+		Err:       gbc.Err(),
+	}
+}
+
+//Runs one of mooneye's test ROMs until it executes LD B,B or maxCycles system clock cycles
+//have run. The test has passed when B, C, D, E, H and L hold the first Fibonacci numbers
+func RunMooneyeTestROM(cart *cartridge.Cartridge, colorMode bool, maxCycles int) *TestROMResult {
+	gbc, io := newTestROMEmulator(cart, colorMode)
+	defer close(io.screen)
+
+	capture := new(serialCapture)
+	gbc.serial.RegisterObserver(capture)
+
+	cycles, timedOut := gbc.runTestROM(maxCycles, func() bool {
+		return gbc.cpu.Opcode() == MOONEYE_BREAKPOINT_OPCODE
+	})
+
+	var r cpu.Registers = gbc.cpu.R
+	return &TestROMResult{
+		Passed:    !timedOut && !gbc.cpu.Locked && [6]byte{r.B, r.C, r.D, r.E, r.H, r.L} == MOONEYE_PASS_SIGNATURE,
+		TimedOut:  timedOut,
+		Cycles:    cycles,
+		Output:    capture.output.String(),
+		Registers: r,
+		Err:       gbc.Err(),
+	}
+}
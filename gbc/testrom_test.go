@@ -0,0 +1,235 @@
+package gbc
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/djhworld/gomeboycolor/asm"
+	"github.com/djhworld/gomeboycolor/cartridge"
+	"github.com/djhworld/gomeboycolor/cpu"
+	"github.com/stretchrcom/testify/assert"
+)
+
+//Blargg's test ROMs are not distributed with the emulator, any found in this directory
+//(or the one named by GOMEBOYCOLOR_BLARGG_ROMS) are run as part of the tests
+const BLARGG_ROM_DIR = "testdata/blargg"
+
+//mooneye's test ROMs are looked for in this directory (or the one named by
+//GOMEBOYCOLOR_MOONEYE_ROMS) and any subdirectories
+const MOONEYE_ROM_DIR = "testdata/mooneye"
+
+//three minutes of emulated time, cpu_instrs is the slowest and needs about one
+const BLARGG_MAX_CYCLES = 180 * 4194304
+
+//mooneye's tests finish within a few seconds
+const MOONEYE_MAX_CYCLES = 10 * 4194304
+
+//Assembles a ROM from source starting at address 0, the ROM is padded out to the size given
+//in its header (0x0148)
+func assembleTestROM(t testing.TB, name string, source string) *cartridge.Cartridge {
+	code, err := asm.Assemble(source, 0x0000)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var size int = 0x8000
+	if len(code) > 0x0148 {
+		size <<= code[0x0148]
+	}
+	var rom []byte = make([]byte, size)
+	copy(rom, code)
+
+	cart, err := cartridge.NewCartridge(name, rom)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cart
+}
+
+//Builds a ROM that prints message over the serial port, waiting for each byte to be shifted
+//out, before looping forever
+func serialTestROM(t *testing.T, message string) *cartridge.Cartridge {
+	return assembleTestROM(t, "serial.gb", fmt.Sprintf(`
+		ORG 0x0100
+		JP main
+
+		ORG 0x0150
+	main:
+		LD HL,message
+	next:
+		LD A,(HL+)
+		OR A
+		JR Z,done
+		LD (0xFF01),A
+		LD A,0x81
+		LD (0xFF02),A
+	wait:
+		LD A,(0xFF02)
+		BIT 7,A
+		JR NZ,wait
+		JR next
+	done:
+		JR done
+
+	message:
+		DB %q, 0
+	`, message))
+}
+
+func TestSerialTestROMPassed(t *testing.T) {
+	result := RunSerialTestROM(serialTestROM(t, "cpu_instrs\n\nPassed all tests\n"), false, 1000000)
+	assert.True(t, result.Passed)
+	assert.False(t, result.TimedOut)
+	assert.Equal(t, "cpu_instrs\n\nPassed", result.Output)
+}
+
+func TestSerialTestROMFailed(t *testing.T) {
+	result := RunSerialTestROM(serialTestROM(t, "01:ok 02:01\n\nFailed 1 tests."), false, 1000000)
+	assert.False(t, result.Passed)
+	assert.False(t, result.TimedOut)
+	assert.Equal(t, "01:ok 02:01\n\nFailed", result.Output)
+}
+
+func TestSerialTestROMTimesOut(t *testing.T) {
+	result := RunSerialTestROM(serialTestROM(t, "Running..."), false, 100000)
+	assert.False(t, result.Passed)
+	assert.True(t, result.TimedOut)
+	assert.Equal(t, "Running...", result.Output)
+	assert.True(t, result.Cycles >= 100000)
+}
+
+//Builds a ROM that loads the given values into B, C, D, E, H and L then executes LD B,B
+func mooneyeTestROM(t *testing.T, registers [6]byte, breakpoint bool) *cartridge.Cartridge {
+	var end string = "NOP"
+	if breakpoint {
+		end = "LD B,B"
+	}
+
+	return assembleTestROM(t, "mooneye.gb", fmt.Sprintf(`
+		ORG 0x0100
+		JP 0x0150
+
+		ORG 0x0150
+		LD B,%d
+		LD C,%d
+		LD D,%d
+		LD E,%d
+		LD H,%d
+		LD L,%d
+		%s
+	loop:
+		JR loop
+	`, registers[0], registers[1], registers[2], registers[3], registers[4], registers[5], end))
+}
+
+func TestMooneyeTestROMPassed(t *testing.T) {
+	result := RunMooneyeTestROM(mooneyeTestROM(t, MOONEYE_PASS_SIGNATURE, true), false, 100000)
+	assert.True(t, result.Passed)
+	assert.False(t, result.TimedOut)
+	assert.Equal(t, byte(34), result.Registers.L)
+}
+
+func TestMooneyeTestROMFailed(t *testing.T) {
+	result := RunMooneyeTestROM(mooneyeTestROM(t, MOONEYE_FAIL_SIGNATURE, true), false, 100000)
+	assert.False(t, result.Passed)
+	assert.False(t, result.TimedOut)
+	assert.Equal(t, byte(0x42), result.Registers.B)
+}
+
+func TestTestROMStopsOnLockup(t *testing.T) {
+	cart := assembleTestROM(t, "lockup.gb", `
+		ORG 0x0100
+		JP 0x0150
+
+		ORG 0x0150
+		DB 0xD3 ; illegal opcode
+	`)
+
+	result := RunMooneyeTestROM(cart, false, 100000)
+	assert.False(t, result.Passed)
+	assert.False(t, result.TimedOut)
+	assert.Equal(t, &cpu.IllegalOpcodeError{Opcode: 0xD3, Address: 0x0150}, result.Err)
+}
+
+func TestMooneyeTestROMTimesOut(t *testing.T) {
+	result := RunMooneyeTestROM(mooneyeTestROM(t, MOONEYE_PASS_SIGNATURE, false), false, 100000)
+	assert.False(t, result.Passed)
+	assert.True(t, result.TimedOut)
+}
+
+//Finds the test ROMs in dir (or the directory named by the environment variable) and
+//its subdirectories
+func findTestROMs(t *testing.T, dir string, env string) []string {
+	if d := os.Getenv(env); d != "" {
+		dir = d
+	}
+
+	var roms []string
+	filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() && (strings.HasSuffix(path, ".gb") || strings.HasSuffix(path, ".gbc")) {
+			roms = append(roms, path)
+		}
+		return nil
+	})
+
+	if len(roms) == 0 {
+		t.Skip("No test ROMs found in", dir)
+	}
+	return roms
+}
+
+func loadTestROM(t *testing.T, rom string) *cartridge.Cartridge {
+	contents, err := ioutil.ReadFile(rom)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cart, err := cartridge.NewCartridge(rom, contents)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cart
+}
+
+func TestBlarggROMs(t *testing.T) {
+	for _, rom := range findTestROMs(t, BLARGG_ROM_DIR, "GOMEBOYCOLOR_BLARGG_ROMS") {
+		rom := rom
+		t.Run(filepath.Base(rom), func(t *testing.T) {
+			cart := loadTestROM(t, rom)
+			result := RunSerialTestROM(cart, cart.IsColourGB, BLARGG_MAX_CYCLES)
+			if result.Err != nil {
+				t.Errorf("%s crashed: %v, output:\n%s", rom, result.Err, strings.TrimSpace(result.Output))
+			} else if !result.Passed {
+				t.Errorf("%s did not pass (timed out: %v) after %d cycles, output:\n%s", rom, result.TimedOut, result.Cycles, strings.TrimSpace(result.Output))
+			}
+		})
+	}
+}
+
+func TestMooneyeROMs(t *testing.T) {
+	for _, rom := range findTestROMs(t, MOONEYE_ROM_DIR, "GOMEBOYCOLOR_MOONEYE_ROMS") {
+		rom := rom
+		t.Run(filepath.Base(rom), func(t *testing.T) {
+			cart := loadTestROM(t, rom)
+			result := RunMooneyeTestROM(cart, cart.IsColourGB, MOONEYE_MAX_CYCLES)
+			switch {
+			case result.Passed:
+			case result.Err != nil:
+				t.Errorf("%s crashed: %v", rom, result.Err)
+			case result.TimedOut:
+				t.Errorf("%s did not reach LD B,B after %d cycles", rom, result.Cycles)
+			default:
+				r := result.Registers
+				if [6]byte{r.B, r.C, r.D, r.E, r.H, r.L} == MOONEYE_FAIL_SIGNATURE {
+					t.Errorf("%s failed", rom)
+				} else {
+					t.Errorf("%s finished with unexpected registers %v", rom, r)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package gbs
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/djhworld/gomeboycolor/types"
+	"github.com/djhworld/gomeboycolor/utils"
+)
+
+const NAME = "GBS"
+const PREFIX = NAME + ":"
+
+const (
+	HEADER_SIZE       int  = 0x70
+	SUPPORTED_VERSION byte = 1
+)
+
+var InvalidGBSFile error = errors.New("File is not a GBS file")
+var UnsupportedGBSVersion error = errors.New("Unsupported GBS version")
+
+//A GBS (Game Boy Sound) music rip. The file is a header followed by the code and data of the
+//sound driver taken from a game, which is loaded into ROM at the load address
+type File struct {
+	Version       byte
+	NumberOfSongs int
+	FirstSong     int //1 based
+	LoadAddress   types.Word
+	InitAddress   types.Word
+	PlayAddress   types.Word
+	StackPointer  types.Word
+	TimerModulo   byte
+	TimerControl  byte
+	Title         string
+	Author        string
+	Copyright     string
+	Data          []byte
+}
+
+func LoadFile(filename string) (*File, error) {
+	contents, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(contents)
+}
+
+func Parse(contents []byte) (*File, error) {
+	if len(contents) < HEADER_SIZE || string(contents[0:3]) != "GBS" {
+		return nil, InvalidGBSFile
+	}
+
+	f := new(File)
+	f.Version = contents[0x03]
+	if f.Version != SUPPORTED_VERSION {
+		return nil, UnsupportedGBSVersion
+	}
+
+	f.NumberOfSongs = int(contents[0x04])
+	f.FirstSong = int(contents[0x05])
+	f.LoadAddress = readWord(contents, 0x06)
+	f.InitAddress = readWord(contents, 0x08)
+	f.PlayAddress = readWord(contents, 0x0A)
+	f.StackPointer = readWord(contents, 0x0C)
+	f.TimerModulo = contents[0x0E]
+	f.TimerControl = contents[0x0F]
+	f.Title = readString(contents[0x10:0x30])
+	f.Author = readString(contents[0x30:0x50])
+	f.Copyright = readString(contents[0x50:0x70])
+	f.Data = contents[HEADER_SIZE:]
+
+	if f.NumberOfSongs == 0 {
+		return nil, errors.New(PREFIX + " File contains no songs")
+	}
+
+	if f.LoadAddress < MIN_LOAD_ADDRESS || f.LoadAddress > 0x7FFF {
+		return nil, fmt.Errorf("%s Load address %s is outside of ROM", PREFIX, f.LoadAddress)
+	}
+
+	if int(f.LoadAddress)+len(f.Data) > ROM_SIZE {
+		return nil, fmt.Errorf("%s %d bytes of data is too big to load at %s", PREFIX, len(f.Data), f.LoadAddress)
+	}
+
+	return f, nil
+}
+
+//Addresses in the header are little endian
+func readWord(contents []byte, offset int) types.Word {
+	return types.Word(utils.JoinBytes(contents[offset+1], contents[offset]))
+}
+
+//Strings in the header are padded with zeros
+func readString(b []byte) string {
+	return strings.TrimRight(string(b), "\x00")
+}
+
+//When bit 2 of the timer control is set the play routine is driven by the timer rather than vblank
+func (f *File) UsesTimer() bool {
+	return f.TimerControl&0x04 == 0x04
+}
+
+//Bit 7 of the timer control selects CGB double speed mode
+func (f *File) IsDoubleSpeed() bool {
+	return f.TimerControl&0x80 == 0x80
+}
+
+func (f *File) String() string {
+	var rate string = "VBlank"
+	if f.UsesTimer() {
+		rate = fmt.Sprintf("Timer (TAC: %s TMA: %s)", utils.ByteToString(f.TimerControl), utils.ByteToString(f.TimerModulo))
+	}
+
+	var header []string = []string{
+		fmt.Sprintf(utils.PadRight("Title:", 19, " ")+"%s", f.Title),
+		fmt.Sprintf(utils.PadRight("Author:", 19, " ")+"%s", f.Author),
+		fmt.Sprintf(utils.PadRight("Copyright:", 19, " ")+"%s", f.Copyright),
+		fmt.Sprintf(utils.PadRight("Songs:", 19, " ")+"%d (first song %d)", f.NumberOfSongs, f.FirstSong),
+		fmt.Sprintf(utils.PadRight("Load address:", 19, " ")+"%s", f.LoadAddress),
+		fmt.Sprintf(utils.PadRight("Init address:", 19, " ")+"%s", f.InitAddress),
+		fmt.Sprintf(utils.PadRight("Play address:", 19, " ")+"%s", f.PlayAddress),
+		fmt.Sprintf(utils.PadRight("Stack pointer:", 19, " ")+"%s", f.StackPointer),
+		fmt.Sprintf(utils.PadRight("Play rate:", 19, " ")+"%s", rate),
+	}
+
+	return fmt.Sprintln("\nGBS File") +
+		fmt.Sprintln(strings.Repeat("-", 100)) +
+		fmt.Sprintln(strings.Join(header, "\n")) +
+		fmt.Sprintln(strings.Repeat("-", 100))
+}
@@ -0,0 +1,257 @@
+package gbs
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/djhworld/gomeboycolor/apu"
+	"github.com/djhworld/gomeboycolor/cartridge"
+	"github.com/djhworld/gomeboycolor/constants"
+	"github.com/djhworld/gomeboycolor/cpu"
+	"github.com/djhworld/gomeboycolor/inputoutput"
+	"github.com/djhworld/gomeboycolor/mmu"
+	"github.com/djhworld/gomeboycolor/timer"
+	"github.com/djhworld/gomeboycolor/types"
+	"github.com/djhworld/gomeboycolor/utils"
+)
+
+const (
+	ROM_SIZE         int        = 32 * 0x4000 //every bank MBC1 can select
+	RAM_SIZE         int        = 0x2000
+	MIN_LOAD_ADDRESS types.Word = 0x0400
+
+	//init and play return to this address, while the PC is here the CPU is idle
+	RETURN_ADDRESS types.Word = 0x0100
+
+	//M-cycles between vblanks at normal speed
+	VBLANK_PERIOD int = 17556
+)
+
+//Plays GBS files on the CPU, MMU, timer and APU without a GPU or a real cartridge. The sound
+//driver's init routine is called when a track is selected, then its play routine is called at
+//the rate given in the header
+type Player struct {
+	file        *File
+	cpu         *cpu.GbcCPU
+	mmu         *mmu.GbcMMU
+	timer       *timer.Timer
+	apu         *apu.APU
+	track       int
+	vblankTimer int
+	cycles      int //cycles left to run, negative when the last instruction overran
+}
+
+func NewPlayer(file *File, sampleRate int) (*Player, error) {
+	p := new(Player)
+	p.file = file
+	p.mmu = mmu.NewGbcMMU()
+	p.timer = timer.NewTimer()
+	p.cpu = cpu.NewCPU(p.mmu, p.timer)
+	p.apu = apu.NewAPU()
+	p.cpu.LinkClockHandler(func(cycles int) {
+		p.apu.Step(cycles * 4)
+	})
+
+	p.mmu.LoadCartridge(newCartridge(file))
+	p.timer.LinkIRQHandler(p.mmu)
+	p.mmu.ConnectPeripheral(p.apu, 0xFF10, 0xFF3F)
+	p.mmu.ConnectCGBPeripheralOn(p.apu, apu.PCM12, apu.PCM34)
+	p.mmu.ConnectCGBPeripheralOn(p.cpu.SpeedSwitch, mmu.CGB_DOUBLE_SPEED_PREP_REG)
+	p.mmu.ConnectPeripheralOn(p.timer, timer.DIV_REGISTER, timer.TIMA_REGISTER, timer.TMA_REGISTER, timer.TAC_REGISTER)
+	p.apu.SetSampleRate(sampleRate)
+
+	if err := p.SelectTrack(file.FirstSong); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+//The GBS data is loaded into an MBC1 cartridge with RAM. The area below the load address holds
+//the RST vectors (which jump to the load address plus the vector as the GBS spec requires), the
+//interrupt vectors and the return address
+func newCartridge(f *File) *cartridge.Cartridge {
+	rom := make([]byte, ROM_SIZE)
+
+	for rst := 0x00; rst <= 0x38; rst += 0x08 {
+		hi, lo := utils.SplitIntoBytes(uint16(f.LoadAddress) + uint16(rst))
+		rom[rst], rom[rst+1], rom[rst+2] = 0xC3, lo, hi //JP nn
+	}
+
+	for irq := 0x40; irq <= 0x60; irq += 0x08 {
+		rom[irq] = 0xD9 //RETI
+	}
+	rom[RETURN_ADDRESS], rom[RETURN_ADDRESS+1] = 0x18, 0xFE //JR -2
+
+	copy(rom[f.LoadAddress:], f.Data)
+
+	return &cartridge.Cartridge{
+		Title:      f.Title,
+		IsColourGB: f.IsDoubleSpeed(),
+		Type:       cartridge.CartridgeTypes[cartridge.MBC_1_RAM],
+		ROMSize:    ROM_SIZE,
+		RAMSize:    RAM_SIZE,
+		Name:       f.Title,
+		MBC:        cartridge.NewMBC1(rom, ROM_SIZE, RAM_SIZE, false),
+	}
+}
+
+func (p *Player) File() *File {
+	return p.file
+}
+
+//The currently selected track (1 based)
+func (p *Player) Track() int {
+	return p.track
+}
+
+//Gives access to the APU so channels can be muted, soloed or captured
+func (p *Player) APU() *apu.APU {
+	return p.apu
+}
+
+//Resets the hardware and calls the init routine for the track (1 based)
+func (p *Player) SelectTrack(track int) error {
+	if track < 1 || track > p.file.NumberOfSongs {
+		return fmt.Errorf("%s Track %d is invalid, must be between 1 and %d", PREFIX, track, p.file.NumberOfSongs)
+	}
+
+	log.Println(PREFIX, "Selecting track", track, "of", p.file.NumberOfSongs)
+	p.track = track
+
+	p.mmu.Reset()
+	p.mmu.SetInBootMode(false)
+	p.mmu.RunningColorGBHardware = p.file.IsDoubleSpeed()
+	for addr := 0xA000; addr <= 0xDFFF; addr++ {
+		p.mmu.WriteByte(types.Word(addr), 0x00)
+	}
+	for addr := 0xFF80; addr <= 0xFFFE; addr++ {
+		p.mmu.WriteByte(types.Word(addr), 0x00)
+	}
+
+	//interrupts are never serviced, the player calls the play routine itself
+	p.mmu.WriteByte(constants.INTERRUPT_ENABLED_FLAG_ADDR, 0x00)
+	p.mmu.WriteByte(constants.INTERRUPT_FLAG_ADDR, 0x00)
+
+	p.mmu.WriteByte(timer.DIV_REGISTER, 0x00)
+	p.mmu.WriteByte(timer.TMA_REGISTER, p.file.TimerModulo)
+	p.mmu.WriteByte(timer.TIMA_REGISTER, p.file.TimerModulo)
+	p.mmu.WriteByte(timer.TAC_REGISTER, p.file.TimerControl&0x07)
+
+	p.apu.Reset()
+	p.apu.RunningColorGBHardware = p.file.IsDoubleSpeed()
+	p.mmu.WriteByte(apu.NR52, 0x80)
+	p.mmu.WriteByte(apu.NR50, 0x77)
+	p.mmu.WriteByte(apu.NR51, 0xFF)
+
+	p.cpu.Reset()
+	if p.file.IsDoubleSpeed() {
+		p.cpu.Speed = 2
+	}
+	p.apu.SetCPUSpeed(p.cpu.Speed)
+	p.cpu.InterruptsEnabled = false
+	p.cpu.SP = p.file.StackPointer
+	p.cpu.R.A = byte(track - 1)
+
+	//resetting the MMU leaves the cartridge alone, so undo any bank switch the last track made
+	p.mmu.WriteByte(0x2000, 0x01)
+	p.call(p.file.InitAddress)
+
+	p.vblankTimer = p.vblankPeriod()
+	p.cycles = 0
+	return nil
+}
+
+//Pushes the return address and jumps to the routine
+func (p *Player) call(addr types.Word) {
+	hi, lo := utils.SplitIntoBytes(uint16(RETURN_ADDRESS))
+	p.cpu.SP--
+	p.mmu.WriteByte(p.cpu.SP, hi)
+	p.cpu.SP--
+	p.mmu.WriteByte(p.cpu.SP, lo)
+	p.cpu.PC = addr
+}
+
+func (p *Player) inRoutine() bool {
+	return p.cpu.PC != RETURN_ADDRESS
+}
+
+//the vblank rate is fixed, so takes twice as many cycles in double speed mode
+func (p *Player) vblankPeriod() int {
+	return VBLANK_PERIOD * p.cpu.Speed
+}
+
+//Runs for the given number of M-cycles, calling the play routine whenever it is due
+func (p *Player) run(cycles int) {
+	p.cycles += cycles
+	for p.cycles > 0 {
+		var n int
+		if p.inRoutine() {
+			n = p.cpu.Step()
+		} else {
+			//nothing is running so skip ahead, but never past a timer overflow or vblank
+			n = 4
+			if !p.file.UsesTimer() && p.vblankTimer < n {
+				n = p.vblankTimer
+			}
+			p.cpu.Idle(n)
+		}
+
+		p.cycles -= n
+
+		//a play that is due while a routine is still running is dropped, like a missed interrupt
+		if p.isPlayDue(n) && !p.inRoutine() {
+			p.call(p.file.PlayAddress)
+		}
+	}
+}
+
+func (p *Player) isPlayDue(cycles int) bool {
+	if p.file.UsesTimer() {
+		var iflag byte = p.mmu.ReadByte(constants.INTERRUPT_FLAG_ADDR)
+		if iflag&constants.TIMER_OVERFLOW_IRQ == 0x00 {
+			return false
+		}
+		p.mmu.WriteByte(constants.INTERRUPT_FLAG_ADDR, iflag&^constants.TIMER_OVERFLOW_IRQ)
+		return true
+	}
+
+	p.vblankTimer -= cycles
+	if p.vblankTimer <= 0 {
+		p.vblankTimer += p.vblankPeriod()
+		return true
+	}
+	return false
+}
+
+//Plays the current track for the given duration and returns the interleaved stereo samples
+func (p *Player) Render(duration time.Duration) []int16 {
+	var samples []int16
+	p.render(duration, func(s []int16) {
+		samples = append(samples, s...)
+	})
+	return samples
+}
+
+//Plays the current track for the given duration, sending the samples to the sink a frame at a time
+func (p *Player) RenderTo(sink inputoutput.AudioSink, duration time.Duration) {
+	if rate := sink.SampleRate(); rate != p.apu.SampleRate() {
+		p.apu.SetSampleRate(rate)
+	}
+	p.render(duration, sink.PlaySamples)
+}
+
+func (p *Player) render(duration time.Duration, out func([]int16)) {
+	var cyclesPerSecond float64 = float64(apu.CLOCK_RATE/4) * float64(p.cpu.Speed)
+	var remaining int = int(duration.Seconds() * cyclesPerSecond)
+
+	for remaining > 0 {
+		n := p.vblankPeriod()
+		if remaining < n {
+			n = remaining
+		}
+		p.run(n)
+		remaining -= n
+		out(p.apu.Samples())
+	}
+}
@@ -0,0 +1,199 @@
+package gbs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/djhworld/gomeboycolor/asm"
+	"github.com/djhworld/gomeboycolor/types"
+	"github.com/stretchrcom/testify/assert"
+)
+
+//Builds a GBS file with a sound driver that stores the track number at 0xC000, starts a square
+//wave on channel 1 and counts calls to play at 0xC001
+func testGBS(tma, tac byte) []byte {
+	header := make([]byte, HEADER_SIZE)
+	copy(header, "GBS")
+	header[0x03] = 1
+	header[0x04] = 3    //songs
+	header[0x05] = 1    //first song
+	header[0x06] = 0x00 //load 0x0400
+	header[0x07] = 0x04
+	header[0x08] = 0x00 //init 0x0400
+	header[0x09] = 0x04
+	header[0x0A] = 0x20 //play 0x0420
+	header[0x0B] = 0x04
+	header[0x0C] = 0xFE //stack 0xDFFE
+	header[0x0D] = 0xDF
+	header[0x0E] = tma
+	header[0x0F] = tac
+	copy(header[0x10:], "Test Title")
+	copy(header[0x30:], "Test Author")
+	copy(header[0x50:], "Test Copyright")
+
+	data := make([]byte, 0x30)
+	copy(data, asm.MustAssemble(`
+		LD (0xC000),A
+		LD A,0x80
+		LD (0xFF11),A
+		LD A,0xF0
+		LD (0xFF12),A
+		LD A,0x00
+		LD (0xFF13),A
+		LD A,0x87
+		LD (0xFF14),A
+		RET
+
+		ORG 0x0420 ; play
+		LD HL,0xC001
+		INC (HL)
+		RET
+	`, 0x0400))
+	return append(header, data...)
+}
+
+func TestParseHeader(t *testing.T) {
+	//when
+	f, err := Parse(testGBS(0xAB, 0x04))
+
+	//then
+	assert.Nil(t, err)
+	assert.Equal(t, 3, f.NumberOfSongs)
+	assert.Equal(t, 1, f.FirstSong)
+	assert.Equal(t, types.Word(0x0400), f.LoadAddress)
+	assert.Equal(t, types.Word(0x0400), f.InitAddress)
+	assert.Equal(t, types.Word(0x0420), f.PlayAddress)
+	assert.Equal(t, types.Word(0xDFFE), f.StackPointer)
+	assert.Equal(t, byte(0xAB), f.TimerModulo)
+	assert.True(t, f.UsesTimer())
+	assert.False(t, f.IsDoubleSpeed())
+	assert.Equal(t, "Test Title", f.Title)
+	assert.Equal(t, "Test Author", f.Author)
+	assert.Equal(t, "Test Copyright", f.Copyright)
+	assert.Equal(t, 0x30, len(f.Data))
+}
+
+func TestParseRejectsInvalidFiles(t *testing.T) {
+	_, err := Parse([]byte("GBS"))
+	assert.Equal(t, InvalidGBSFile, err)
+
+	contents := testGBS(0, 0)
+	contents[0x03] = 2
+	_, err = Parse(contents)
+	assert.Equal(t, UnsupportedGBSVersion, err)
+
+	contents = testGBS(0, 0)
+	contents[0x07] = 0x00 //load address 0x0000
+	_, err = Parse(contents)
+	assert.NotNil(t, err)
+}
+
+func TestInitIsCalledWithTrackNumber(t *testing.T) {
+	//given
+	f, _ := Parse(testGBS(0, 0))
+	p, err := NewPlayer(f, 44100)
+	assert.Nil(t, err)
+
+	//when
+	p.Render(time.Millisecond)
+
+	//then
+	assert.Equal(t, byte(0), p.mmu.ReadByte(0xC000))
+
+	//when
+	err = p.SelectTrack(3)
+	p.Render(time.Millisecond)
+
+	//then
+	assert.Nil(t, err)
+	assert.Equal(t, 3, p.Track())
+	assert.Equal(t, byte(2), p.mmu.ReadByte(0xC000))
+}
+
+func TestSelectTrackMapsBankOneBackIn(t *testing.T) {
+	//given a driver with its init in bank 1 that switches to bank 2 on the first track
+	contents := testGBS(0, 0)
+	contents[0x08] = 0x00 //init 0x4000
+	contents[0x09] = 0x40
+	contents = append(contents, make([]byte, 2*0x4000-len(contents)+HEADER_SIZE)...)
+	copy(contents[HEADER_SIZE+0x4000-0x0400:], asm.MustAssemble(`
+		LD (0xC000),A
+		OR A
+		RET NZ
+		LD A,0x02
+		LD (0x2000),A
+		RET
+	`, 0x4000))
+	copy(contents[HEADER_SIZE+0x8000-0x0400:], asm.MustAssemble(`
+		LD A,0xEE
+		LD (0xC000),A
+		RET
+	`, 0x4000))
+
+	f, err := Parse(contents)
+	assert.Nil(t, err)
+	p, err := NewPlayer(f, 44100)
+	assert.Nil(t, err)
+	p.Render(time.Millisecond)
+	assert.Equal(t, byte(0xEE), p.mmu.ReadByte(0x4001))
+
+	//when
+	err = p.SelectTrack(2)
+	p.Render(time.Millisecond)
+
+	//then the second track's init runs from bank 1
+	assert.Nil(t, err)
+	assert.Equal(t, byte(1), p.mmu.ReadByte(0xC000))
+}
+
+func TestSelectInvalidTrack(t *testing.T) {
+	f, _ := Parse(testGBS(0, 0))
+	p, _ := NewPlayer(f, 44100)
+
+	assert.NotNil(t, p.SelectTrack(0))
+	assert.NotNil(t, p.SelectTrack(4))
+	assert.Equal(t, 1, p.Track())
+}
+
+func TestPlayIsCalledOnVBlank(t *testing.T) {
+	//given
+	f, _ := Parse(testGBS(0, 0))
+	p, _ := NewPlayer(f, 44100)
+
+	//when
+	p.Render(time.Second)
+
+	//then ~59.7hz
+	assert.Equal(t, byte(59), p.mmu.ReadByte(0xC001))
+}
+
+func TestPlayIsCalledOnTimerOverflow(t *testing.T) {
+	//given 4096hz / (256 - 0xC0) = 64hz
+	f, _ := Parse(testGBS(0xC0, 0x04))
+	p, _ := NewPlayer(f, 44100)
+
+	//when
+	p.Render(time.Second)
+
+	//then
+	assert.InDelta(t, 64, int(p.mmu.ReadByte(0xC001)), 1)
+}
+
+func TestRenderPlaysTheTrack(t *testing.T) {
+	//given
+	f, _ := Parse(testGBS(0, 0))
+	p, _ := NewPlayer(f, 44100)
+
+	//when
+	samples := p.Render(time.Second / 2)
+
+	//then
+	assert.InDelta(t, 44100, len(samples), 4)
+	var peak int16
+	for _, s := range samples {
+		if s > peak {
+			peak = s
+		}
+	}
+	assert.True(t, peak > 5000, "expected the square wave to be audible")
+}
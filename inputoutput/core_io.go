@@ -11,11 +11,17 @@ const PREFIX string = "IO"
 const SCREEN_WIDTH int = 160
 const SCREEN_HEIGHT int = 144
 
+// number of frames worth of audio that can be queued before
+// batches start getting dropped
+const AUDIO_BUFFER_FRAMES int = 4
+
 // IOHandler interface for handling all IO interations with the emulator
 type IOHandler interface {
 	Init(title string, screenSize int, onCloseHandler func()) error
 	GetKeyHandler() *KeyHandler
 	GetScreenOutputChannel() chan *types.Screen
+	GetAudioOutputChannel() chan []int16
+	GetAudioSampleRate() int
 	GetAvgFrameRate() float32
 	Run()
 }
@@ -25,6 +31,14 @@ type Display interface {
 	Stop()
 }
 
+// AudioSink receives batches of interleaved stereo (left, right) 16-bit
+// PCM samples at the sample rate it reports
+type AudioSink interface {
+	SampleRate() int
+	PlaySamples(samples []int16)
+	Stop()
+}
+
 // CoreIO contains all core functionality for running the IO event loop
 // all sub types should extend this type
 type CoreIO struct {
@@ -33,15 +47,18 @@ type CoreIO struct {
 	StopChannel    chan int
 	Headless       bool
 
-	audioOutputChannel  chan int
+	audioOutputChannel  chan []int16
 	screenOutputChannel chan *types.Screen
 	display             Display
+	audioSink           AudioSink
 	frameRateLock       int64
 	frameRateCounter    *metric.FPSCounter
 	frameRateReporter   func(float32)
 }
 
-func NewCoreIO(frameRateLock int64, headless bool, frameRateReporter func(float32), display Display) *CoreIO {
+// NewCoreIO creates the core IO event loop, audioSink can be nil if
+// the frontend does not support sound
+func NewCoreIO(frameRateLock int64, headless bool, frameRateReporter func(float32), display Display, audioSink AudioSink) *CoreIO {
 	i := new(CoreIO)
 	i.KeyHandler = new(KeyHandler)
 	i.StopChannel = make(chan int, 1)
@@ -49,8 +66,9 @@ func NewCoreIO(frameRateLock int64, headless bool, frameRateReporter func(float3
 	i.OnCloseHandler = nil
 
 	i.screenOutputChannel = make(chan *types.Screen)
-	i.audioOutputChannel = make(chan int)
+	i.audioOutputChannel = make(chan []int16, AUDIO_BUFFER_FRAMES)
 	i.display = display
+	i.audioSink = audioSink
 	i.frameRateLock = frameRateLock
 	i.frameRateCounter = metric.NewFPSCounter()
 	i.frameRateReporter = frameRateReporter
@@ -69,6 +87,21 @@ func (i *CoreIO) GetKeyHandler() *KeyHandler {
 	return i.KeyHandler
 }
 
+// GetAudioOutputChannel returns the channel to push batches of
+// audio samples to the IO event loop
+func (i *CoreIO) GetAudioOutputChannel() chan []int16 {
+	return i.audioOutputChannel
+}
+
+// GetAudioSampleRate returns the sample rate of the audio sink,
+// or 0 if there is no audio sink
+func (i *CoreIO) GetAudioSampleRate() int {
+	if i.audioSink == nil {
+		return 0
+	}
+	return i.audioSink.SampleRate()
+}
+
 func (i *CoreIO) GetAvgFrameRate() float32 {
 	return i.frameRateCounter.Avg()
 }
@@ -87,8 +120,15 @@ func (i *CoreIO) Run() {
 			<-fpsThrottler
 			i.display.DrawFrame(data)
 			frameCount++
+		case samples := <-i.audioOutputChannel:
+			if i.audioSink != nil {
+				i.audioSink.PlaySamples(samples)
+			}
 		case <-i.StopChannel:
 			i.display.Stop()
+			if i.audioSink != nil {
+				i.audioSink.Stop()
+			}
 			i.OnCloseHandler()
 			isRunning = false
 		case <-frameRateCountTicker:
@@ -0,0 +1,101 @@
+package inputoutput
+
+import (
+	"encoding/binary"
+	"io"
+	"log"
+)
+
+const (
+	WAV_HEADER_SIZE     int64 = 44
+	WAV_CHANNELS        int   = 2
+	WAV_BITS_PER_SAMPLE int   = 16
+)
+
+// WAVSink is an AudioSink that writes the samples it receives to a
+// 16-bit stereo PCM WAV file. The sizes in the header are filled in
+// when the sink is stopped
+type WAVSink struct {
+	w          io.WriteSeeker
+	sampleRate int
+	dataSize   uint32
+	err        error
+}
+
+func NewWAVSink(w io.WriteSeeker, sampleRate int) (*WAVSink, error) {
+	s := &WAVSink{w: w, sampleRate: sampleRate}
+	if err := s.writeHeader(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *WAVSink) SampleRate() int {
+	return s.sampleRate
+}
+
+// PlaySamples appends the interleaved samples to the data chunk, after
+// the first write error all further samples are discarded
+func (s *WAVSink) PlaySamples(samples []int16) {
+	if s.err != nil {
+		return
+	}
+
+	if s.err = binary.Write(s.w, binary.LittleEndian, samples); s.err != nil {
+		log.Println(PREFIX, "Could not write audio samples to WAV file:", s.err)
+		return
+	}
+	s.dataSize += uint32(len(samples) * 2)
+}
+
+func (s *WAVSink) Stop() {
+	if err := s.Close(); err != nil {
+		log.Println(PREFIX, "Could not finalise WAV file:", err)
+	}
+}
+
+// Close rewrites the header with the final sizes and moves the
+// writer back to the end of the file
+func (s *WAVSink) Close() error {
+	if s.err != nil {
+		return s.err
+	}
+
+	if _, err := s.w.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	if err := s.writeHeader(); err != nil {
+		return err
+	}
+
+	_, err := s.w.Seek(0, io.SeekEnd)
+	return err
+}
+
+func (s *WAVSink) writeHeader() error {
+	var blockAlign int = WAV_CHANNELS * WAV_BITS_PER_SAMPLE / 8
+
+	header := []interface{}{
+		[]byte("RIFF"),
+		uint32(WAV_HEADER_SIZE-8) + s.dataSize,
+		[]byte("WAVE"),
+		[]byte("fmt "),
+		uint32(16), // fmt chunk size
+		uint16(1),  // PCM
+		uint16(WAV_CHANNELS),
+		uint32(s.sampleRate),
+		uint32(s.sampleRate * blockAlign),
+		uint16(blockAlign),
+		uint16(WAV_BITS_PER_SAMPLE),
+		[]byte("data"),
+		s.dataSize,
+	}
+
+	for _, field := range header {
+		if err := binary.Write(s.w, binary.LittleEndian, field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
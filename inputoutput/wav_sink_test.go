@@ -0,0 +1,39 @@
+package inputoutput
+
+import (
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchrcom/testify/assert"
+)
+
+func TestWAVSinkWritesHeaderAndSamples(t *testing.T) {
+	//given
+	f, err := ioutil.TempFile("", "wavsink")
+	assert.Nil(t, err)
+	defer os.Remove(f.Name())
+	sink, err := NewWAVSink(f, 44100)
+	assert.Nil(t, err)
+
+	//when
+	sink.PlaySamples([]int16{1, -1, 2, -2})
+	sink.PlaySamples([]int16{3, -3})
+	sink.Stop()
+	f.Close()
+
+	//then
+	data, err := ioutil.ReadFile(f.Name())
+	assert.Nil(t, err)
+	assert.Equal(t, int(WAV_HEADER_SIZE)+12, len(data))
+	assert.Equal(t, "RIFF", string(data[0:4]))
+	assert.Equal(t, uint32(36+12), binary.LittleEndian.Uint32(data[4:8]))
+	assert.Equal(t, "WAVE", string(data[8:12]))
+	assert.Equal(t, uint16(2), binary.LittleEndian.Uint16(data[22:24]))
+	assert.Equal(t, uint32(44100), binary.LittleEndian.Uint32(data[24:28]))
+	assert.Equal(t, uint32(44100*4), binary.LittleEndian.Uint32(data[28:32]))
+	assert.Equal(t, "data", string(data[36:40]))
+	assert.Equal(t, uint32(12), binary.LittleEndian.Uint32(data[40:44]))
+	assert.Equal(t, int16(-3), int16(binary.LittleEndian.Uint16(data[54:56])))
+}
@@ -49,16 +49,16 @@ type GbcMMU struct {
 	interruptsEnabled byte
 	interruptsFlag    byte
 	peripheralsIO     [65536]components.Peripheral
+	cgbPeripheralsIO  map[types.Word]components.Peripheral //registers only present on CGB hardware
 
 	//CGB features
-	cgbWramBankSelectedRegister       byte
-	cgbDoubleSpeedPreparationRegister byte
-	RunningColorGBHardware            bool
-	serialTmp                         byte
+	cgbWramBankSelectedRegister byte
+	RunningColorGBHardware      bool
 }
 
 func NewGbcMMU() *GbcMMU {
 	var mmu *GbcMMU = new(GbcMMU)
+	mmu.cgbPeripheralsIO = make(map[types.Word]components.Peripheral)
 	mmu.Reset()
 	return mmu
 }
@@ -68,7 +68,6 @@ func (mmu *GbcMMU) Reset() {
 	mmu.inBootMode = true
 	mmu.interruptsFlag = 0x00
 	mmu.cgbWramBankSelectedRegister = 0x00
-	mmu.cgbDoubleSpeedPreparationRegister = 0x00
 	mmu.RunningColorGBHardware = false
 }
 
@@ -100,9 +99,6 @@ func (mmu *GbcMMU) WriteByte(addr types.Word, value byte) {
 		if addr >= 0xC000 && addr <= 0xDDFF {
 			mmu.internalRAMShadow[addr&(0xDDFF-0xC000)] = mmu.ReadByte(addr)
 		}
-	case addr == 0xFF01 || addr == 0xFF02:
-		//serial cable communication
-		mmu.serialTmp = ZERO
 	//INTERRUPT FLAG
 	case addr == 0xFF0F:
 		mmu.interruptsFlag = value
@@ -150,9 +146,6 @@ func (mmu *GbcMMU) ReadByte(addr types.Word) byte {
 	//DMA register
 	case addr == 0xFF46:
 		return mmu.DMARegister
-	case addr == 0xFF01 || addr == 0xFF02:
-		//serial cable communication
-		return mmu.serialTmp
 	//INTERRUPT FLAG
 	case addr == 0xFF0F:
 		return mmu.interruptsFlag
@@ -210,6 +203,15 @@ func (mmu *GbcMMU) ConnectPeripheralOn(p components.Peripheral, addrs ...types.W
 	}
 }
 
+//Connects peripherals to registers in the 0xFF4C - 0xFF7F area that only exist on CGB hardware,
+//when not running CGB hardware the peripheral is not visible
+func (mmu *GbcMMU) ConnectCGBPeripheralOn(p components.Peripheral, addrs ...types.Word) {
+	log.Printf("%s: Connecting MMU to %s to CGB only address(es): %s", PREFIX, p.Name(), addrs)
+	for _, addr := range addrs {
+		mmu.cgbPeripheralsIO[addr] = p
+	}
+}
+
 //Puts BIOS ROM into special area in MMU
 func (mmu *GbcMMU) LoadBIOS(data []byte) (bool, error) {
 	log.Println(PREFIX+": Loading", len(data), "byte BIOS ROM into MMU")
@@ -248,15 +250,17 @@ func (mmu *GbcMMU) LoadCartridgeRam(reader io.Reader) {
 
 //This area deals with registers (some only applicable to CGB hardware)
 func (mmu *GbcMMU) WriteByteToRegister(addr types.Word, value byte) {
+	if p := mmu.cgbPeripheralsIO[addr]; p != nil && mmu.RunningColorGBHardware {
+		p.Write(addr, value)
+		return
+	}
+
 	switch addr {
 	case DMG_STATUS_REG:
 		mmu.dmgStatusRegister = value
+	//on CGB hardware the CPU's speed switch register is connected here
 	case CGB_DOUBLE_SPEED_PREP_REG:
-		if mmu.RunningColorGBHardware == false {
-			log.Printf("%s: WARNING -> Cannot write to %s in non-CGB mode! ROM may have unexpected behaviour (ROM is probably unsupported in non-CGB mode)", PREFIX, CGB_WRAM_BANK_SELECT)
-		} else {
-			mmu.cgbDoubleSpeedPreparationRegister = value
-		}
+		log.Printf("%s: WARNING -> Cannot write to %s in non-CGB mode! ROM may have unexpected behaviour (ROM is probably unsupported in non-CGB mode)", PREFIX, CGB_WRAM_BANK_SELECT)
 	case CGB_INFRARED_PORT_REG:
 		log.Printf("%s: Attempting to write 0x%X to infrared port register (%s), this is currently unsupported", PREFIX, value, addr)
 	//Color GB Working RAM Bank Selection
@@ -274,14 +278,16 @@ func (mmu *GbcMMU) WriteByteToRegister(addr types.Word, value byte) {
 
 //This area deals with registers (some only applicable to CGB hardware)
 func (mmu *GbcMMU) ReadByteFromRegister(addr types.Word) byte {
+	if p := mmu.cgbPeripheralsIO[addr]; p != nil && mmu.RunningColorGBHardware {
+		return p.Read(addr)
+	}
+
 	switch addr {
 	case DMG_STATUS_REG:
 		return mmu.dmgStatusRegister
 	case CGB_DOUBLE_SPEED_PREP_REG:
-		if mmu.RunningColorGBHardware == false {
-			log.Fatalf("%s: WARNING -> Attempting to read from %s in non-CGB mode! ROM may have unexpected behaviour (ROM is probably unsupported in non-CGB mode)", PREFIX, addr)
-		}
-		return mmu.cgbDoubleSpeedPreparationRegister
+		log.Fatalf("%s: WARNING -> Attempting to read from %s in non-CGB mode! ROM may have unexpected behaviour (ROM is probably unsupported in non-CGB mode)", PREFIX, addr)
+		return 0x00
 	case CGB_INFRARED_PORT_REG:
 		log.Fatalf("%s: Attempting to read from infrared port register (%s), this is currently unsupported", PREFIX, addr)
 		return 0x00
@@ -361,6 +367,8 @@ func (mmu *GbcMMU) RequestInterrupt(interrupt byte) {
 		mmu.WriteByte(constants.INTERRUPT_FLAG_ADDR, oldVal|constants.LCD_IRQ)
 	case constants.TIMER_OVERFLOW_IRQ:
 		mmu.WriteByte(constants.INTERRUPT_FLAG_ADDR, oldVal|constants.TIMER_OVERFLOW_IRQ)
+	case constants.SERIAL_IRQ:
+		mmu.WriteByte(constants.INTERRUPT_FLAG_ADDR, oldVal|constants.SERIAL_IRQ)
 	case constants.JOYP_HILO_IRQ:
 		mmu.WriteByte(constants.INTERRUPT_FLAG_ADDR, oldVal|constants.JOYP_HILO_IRQ)
 	default:
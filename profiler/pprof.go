@@ -0,0 +1,215 @@
+package profiler
+
+import (
+	"compress/gzip"
+	"io"
+)
+
+//field numbers from pprof's profile.proto
+const (
+	profileSampleType  = 1
+	profileSample      = 2
+	profileLocation    = 4
+	profileFunction    = 5
+	profileStringTable = 6
+	profilePeriodType  = 11
+	profilePeriod      = 12
+
+	valueTypeType = 1
+	valueTypeUnit = 2
+
+	sampleLocationID = 1
+	sampleValue      = 2
+
+	locationID      = 1
+	locationAddress = 3
+	locationLine    = 4
+
+	lineFunctionID = 1
+	lineLine       = 2
+
+	functionID         = 1
+	functionName       = 2
+	functionSystemName = 3
+	functionStartLine  = 5
+)
+
+//Writes the call tree as a gzipped pprof profile so it can be explored with `go tool pprof`.
+//
+//Each function is named after its entry point as BANK:ADDRESS and the line number of every
+//instruction is its address, so `pprof -lines` shows the cost of each instruction
+func (p *Profiler) WritePprof(w io.Writer) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	e := newProfileEncoder()
+
+	cycles := e.valueType("cycles", "count")
+	e.b.message(profileSampleType, cycles)
+	e.b.message(profilePeriodType, cycles)
+	e.b.int64(profilePeriod, 1)
+
+	var walk func(n *Node)
+	walk = func(n *Node) {
+		for loc, c := range n.cycles {
+			var stack []uint64 = []uint64{e.location(n.function, loc)}
+			for caller := n; caller.parent != nil; caller = caller.parent {
+				stack = append(stack, e.location(caller.parent.function, caller.callSite))
+			}
+
+			var sample protobuf
+			sample.uint64s(sampleLocationID, stack)
+			sample.int64s(sampleValue, []int64{int64(c)})
+			e.b.message(profileSample, &sample)
+		}
+		for _, child := range n.children {
+			walk(child)
+		}
+	}
+	walk(p.root)
+
+	for _, s := range e.strings {
+		e.b.string(profileStringTable, s)
+	}
+
+	z := gzip.NewWriter(w)
+	if _, err := z.Write(e.b.data); err != nil {
+		return err
+	}
+	return z.Close()
+}
+
+type profileEncoder struct {
+	b         protobuf
+	strings   []string
+	stringIDs map[string]int64
+	functions map[Location]uint64
+	locations map[[2]Location]uint64
+}
+
+func newProfileEncoder() *profileEncoder {
+	return &profileEncoder{
+		strings:   []string{""},
+		stringIDs: map[string]int64{"": 0},
+		functions: make(map[Location]uint64),
+		locations: make(map[[2]Location]uint64),
+	}
+}
+
+func (e *profileEncoder) str(s string) int64 {
+	if id, ok := e.stringIDs[s]; ok {
+		return id
+	}
+	var id int64 = int64(len(e.strings))
+	e.strings = append(e.strings, s)
+	e.stringIDs[s] = id
+	return id
+}
+
+func (e *profileEncoder) valueType(typ, unit string) *protobuf {
+	var v protobuf
+	v.int64(valueTypeType, e.str(typ))
+	v.int64(valueTypeUnit, e.str(unit))
+	return &v
+}
+
+func (e *profileEncoder) function(function Location) uint64 {
+	if id, ok := e.functions[function]; ok {
+		return id
+	}
+
+	var id uint64 = uint64(len(e.functions) + 1)
+	e.functions[function] = id
+
+	var f protobuf
+	f.uint64(functionID, id)
+	f.int64(functionName, e.str(function.String()))
+	f.int64(functionSystemName, e.str(function.String()))
+	f.int64(functionStartLine, int64(function.PC))
+	e.b.message(profileFunction, &f)
+	return id
+}
+
+//an instruction within a function, the same address reached through different functions
+//(e.g. a shared tail) gets a location for each
+func (e *profileEncoder) location(function, instruction Location) uint64 {
+	var key [2]Location = [2]Location{function, instruction}
+	if id, ok := e.locations[key]; ok {
+		return id
+	}
+
+	var id uint64 = uint64(len(e.locations) + 1)
+	e.locations[key] = id
+
+	var line protobuf
+	line.uint64(lineFunctionID, e.function(function))
+	line.int64(lineLine, int64(instruction.PC))
+
+	var l protobuf
+	l.uint64(locationID, id)
+	l.uint64(locationAddress, uint64(instruction.Bank)<<16|uint64(instruction.PC))
+	l.message(locationLine, &line)
+	e.b.message(profileLocation, &l)
+	return id
+}
+
+//Just enough of the protocol buffer wire format to write a profile
+type protobuf struct {
+	data []byte
+}
+
+const (
+	wireVarint = 0
+	wireBytes  = 2
+)
+
+func (b *protobuf) varint(x uint64) {
+	for x >= 0x80 {
+		b.data = append(b.data, byte(x)|0x80)
+		x >>= 7
+	}
+	b.data = append(b.data, byte(x))
+}
+
+func (b *protobuf) tag(field int, wireType int) {
+	b.varint(uint64(field)<<3 | uint64(wireType))
+}
+
+func (b *protobuf) uint64(field int, x uint64) {
+	b.tag(field, wireVarint)
+	b.varint(x)
+}
+
+func (b *protobuf) int64(field int, x int64) {
+	b.uint64(field, uint64(x))
+}
+
+func (b *protobuf) uint64s(field int, xs []uint64) {
+	var packed protobuf
+	for _, x := range xs {
+		packed.varint(x)
+	}
+	b.bytes(field, packed.data)
+}
+
+func (b *protobuf) int64s(field int, xs []int64) {
+	var packed protobuf
+	for _, x := range xs {
+		packed.varint(uint64(x))
+	}
+	b.bytes(field, packed.data)
+}
+
+func (b *protobuf) string(field int, s string) {
+	b.bytes(field, []byte(s))
+}
+
+func (b *protobuf) bytes(field int, data []byte) {
+	b.tag(field, wireBytes)
+	b.varint(uint64(len(data)))
+	b.data = append(b.data, data...)
+}
+
+func (b *protobuf) message(field int, m *protobuf) {
+	b.bytes(field, m.data)
+}
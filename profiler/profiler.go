@@ -0,0 +1,248 @@
+package profiler
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/djhworld/gomeboycolor/types"
+)
+
+//Where an instruction lives, the bank is the ROM bank mapped at the address when it ran
+type Location struct {
+	Bank int
+	PC   types.Word
+}
+
+//Code that runs outside any call that has been seen (e.g. the main loop of a game) is
+//charged to this function
+var ROOT Location = Location{Bank: -1}
+
+func (l Location) String() string {
+	if l == ROOT {
+		return "[root]"
+	}
+	return fmt.Sprintf("%02X:%04X", l.Bank, uint16(l.PC))
+}
+
+//Adds up the cycles spent at each (bank, PC) and the function each instruction belongs to.
+//
+//The profiler does not follow calls itself, the CPU enters a function for each frame it
+//pushes on its call stack and charges every instruction to the function of the frame on top.
+//Reports can be written while the CPU is running on another goroutine
+type Profiler struct {
+	lock  sync.Mutex
+	root  *Node
+	total int
+}
+
+//One function in the call tree, the same function has a node for every distinct call path
+type Node struct {
+	function Location
+	callSite Location
+	parent   *Node
+	children map[[2]Location]*Node
+	cycles   map[Location]int
+	calls    int
+}
+
+func NewProfiler() *Profiler {
+	return &Profiler{root: newNode(ROOT, ROOT, nil)}
+}
+
+func newNode(function, callSite Location, parent *Node) *Node {
+	return &Node{
+		function: function,
+		callSite: callSite,
+		parent:   parent,
+		children: make(map[[2]Location]*Node),
+		cycles:   make(map[Location]int),
+	}
+}
+
+//Enters function from callSite in caller (nil is ROOT), returning the node that the
+//function's instructions are charged to
+func (p *Profiler) Enter(caller *Node, callSite Location, function Location) *Node {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if caller == nil {
+		caller = p.root
+	}
+
+	var key [2]Location = [2]Location{callSite, function}
+	child, ok := caller.children[key]
+	if !ok {
+		child = newNode(function, callSite, caller)
+		caller.children[key] = child
+	}
+	child.calls++
+	return child
+}
+
+//Charges cycles to the instruction at loc in function (nil is ROOT)
+func (p *Profiler) Instruction(function *Node, loc Location, cycles int) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if function == nil {
+		function = p.root
+	}
+	function.cycles[loc] += cycles
+	p.total += cycles
+}
+
+//Cycles charged since the profiler was started or reset
+func (p *Profiler) Total() int {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return p.total
+}
+
+type FunctionProfile struct {
+	Function Location
+
+	//cycles spent running the function itself and running it along with everything it calls
+	Self  int
+	Total int
+	Calls int
+}
+
+type InstructionProfile struct {
+	Location Location
+	Cycles   int
+}
+
+//Every function seen, most expensive (by self cycles) first
+func (p *Profiler) Functions() []FunctionProfile {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return p.functions()
+}
+
+func (p *Profiler) functions() []FunctionProfile {
+	var functions map[Location]*FunctionProfile = make(map[Location]*FunctionProfile)
+	var active map[Location]bool = make(map[Location]bool)
+
+	var walk func(n *Node) int
+	walk = func(n *Node) int {
+		f, ok := functions[n.function]
+		if !ok {
+			f = &FunctionProfile{Function: n.function}
+			functions[n.function] = f
+		}
+
+		var total int
+		for _, c := range n.cycles {
+			total += c
+		}
+		f.Self += total
+		f.Calls += n.calls
+
+		//recursive calls are already counted in the total of the outer call
+		var recursive bool = active[n.function]
+		active[n.function] = true
+		for _, child := range n.children {
+			total += walk(child)
+		}
+		if !recursive {
+			delete(active, n.function)
+			f.Total += total
+		}
+		return total
+	}
+	walk(p.root)
+
+	var result []FunctionProfile
+	for _, f := range functions {
+		result = append(result, *f)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Self != result[j].Self {
+			return result[i].Self > result[j].Self
+		}
+		return result[i].Function.String() < result[j].Function.String()
+	})
+	return result
+}
+
+//Every instruction that has run, most expensive first
+func (p *Profiler) Instructions() []InstructionProfile {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return p.instructions()
+}
+
+func (p *Profiler) instructions() []InstructionProfile {
+	var instructions map[Location]int = make(map[Location]int)
+	var walk func(n *Node)
+	walk = func(n *Node) {
+		for loc, c := range n.cycles {
+			instructions[loc] += c
+		}
+		for _, child := range n.children {
+			walk(child)
+		}
+	}
+	walk(p.root)
+
+	var result []InstructionProfile
+	for loc, c := range instructions {
+		result = append(result, InstructionProfile{loc, c})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Cycles != result[j].Cycles {
+			return result[i].Cycles > result[j].Cycles
+		}
+		return result[i].Location.String() < result[j].Location.String()
+	})
+	return result
+}
+
+//Writes the top functions and instructions as a plain text report, frameCycles is the
+//number of cycles in a frame so each can be shown as a share of the frame budget
+func (p *Profiler) WriteReport(w io.Writer, frameCycles int, top int) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	b := bufio.NewWriter(w)
+	var frames float64 = float64(p.total) / float64(frameCycles)
+	percent := func(cycles int) float64 {
+		if p.total == 0 {
+			return 0
+		}
+		return float64(cycles) * 100 / float64(p.total)
+	}
+	perFrame := func(cycles int) float64 {
+		if frames == 0 {
+			return 0
+		}
+		return float64(cycles) / frames
+	}
+
+	fmt.Fprintf(b, "Profiled %d cycles (%.2f frames)\n\n", p.total, frames)
+
+	fmt.Fprintln(b, "Functions")
+	fmt.Fprintln(b, strings.Repeat("-", 79))
+	fmt.Fprintf(b, "%10s %7s %9s %10s %7s %9s %8s  %s\n", "self", "self%", "/frame", "total", "total%", "/frame", "calls", "function")
+	for i, f := range p.functions() {
+		if i == top {
+			break
+		}
+		fmt.Fprintf(b, "%10d %6.2f%% %9.1f %10d %6.2f%% %9.1f %8d  %s\n", f.Self, percent(f.Self), perFrame(f.Self), f.Total, percent(f.Total), perFrame(f.Total), f.Calls, f.Function)
+	}
+
+	fmt.Fprintln(b)
+	fmt.Fprintln(b, "Instructions")
+	fmt.Fprintln(b, strings.Repeat("-", 79))
+	fmt.Fprintf(b, "%10s %7s %9s  %s\n", "cycles", "%", "/frame", "address")
+	for i, instr := range p.instructions() {
+		if i == top {
+			break
+		}
+		fmt.Fprintf(b, "%10d %6.2f%% %9.1f  %s\n", instr.Cycles, percent(instr.Cycles), perFrame(instr.Cycles), instr.Location)
+	}
+
+	return b.Flush()
+}
@@ -0,0 +1,119 @@
+package profiler
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/stretchrcom/testify/assert"
+)
+
+func findFunction(p *Profiler, function Location) FunctionProfile {
+	for _, f := range p.Functions() {
+		if f.Function == function {
+			return f
+		}
+	}
+	return FunctionProfile{}
+}
+
+//main loop at 0x0150 calls 0x4000, which calls 0x4100 twice
+func profileNestedCalls() *Profiler {
+	p := NewProfiler()
+	p.Instruction(nil, Location{0, 0x0150}, 4)
+	p.Instruction(nil, Location{0, 0x0151}, 24)
+
+	outer := p.Enter(nil, Location{0, 0x0151}, Location{1, 0x4000})
+	for i := 0; i < 2; i++ {
+		p.Instruction(outer, Location{1, 0x4000}, 8)
+		p.Instruction(outer, Location{1, 0x4001}, 24)
+		inner := p.Enter(outer, Location{1, 0x4001}, Location{1, 0x4100})
+		p.Instruction(inner, Location{1, 0x4100}, 100)
+		p.Instruction(inner, Location{1, 0x4101}, 16)
+	}
+	p.Instruction(outer, Location{1, 0x4004}, 16)
+	p.Instruction(nil, Location{0, 0x0154}, 4)
+	return p
+}
+
+func TestInstructionsAreChargedToTheirFunction(t *testing.T) {
+	p := profileNestedCalls()
+
+	assert.Equal(t, 4+24+2*(8+24+100+16)+16+4, p.Total())
+
+	root := findFunction(p, ROOT)
+	assert.Equal(t, 4+24+4, root.Self)
+	assert.Equal(t, p.Total(), root.Total)
+
+	outer := findFunction(p, Location{1, 0x4000})
+	assert.Equal(t, 1, outer.Calls)
+	assert.Equal(t, 2*(8+24)+16, outer.Self)
+	assert.Equal(t, 2*(8+24+100+16)+16, outer.Total)
+
+	inner := findFunction(p, Location{1, 0x4100})
+	assert.Equal(t, 2, inner.Calls)
+	assert.Equal(t, 2*(100+16), inner.Self)
+	assert.Equal(t, inner.Self, inner.Total)
+}
+
+func TestInstructionHotSpots(t *testing.T) {
+	p := profileNestedCalls()
+	instructions := p.Instructions()
+	assert.Equal(t, InstructionProfile{Location{1, 0x4100}, 200}, instructions[0])
+}
+
+func TestCallsFromDifferentSitesAreSeparateNodes(t *testing.T) {
+	p := NewProfiler()
+	a := p.Enter(nil, Location{0, 0x0150}, Location{0, 0x2000})
+	b := p.Enter(nil, Location{0, 0x0160}, Location{0, 0x2000})
+	assert.False(t, a == b)
+	assert.True(t, a == p.Enter(nil, Location{0, 0x0150}, Location{0, 0x2000}))
+
+	p.Instruction(a, Location{0, 0x2000}, 8)
+	p.Instruction(b, Location{0, 0x2000}, 8)
+	f := findFunction(p, Location{0, 0x2000})
+	assert.Equal(t, 3, f.Calls)
+	assert.Equal(t, 16, f.Self)
+}
+
+func TestRecursiveCallsAreNotCountedTwice(t *testing.T) {
+	p := NewProfiler()
+	outer := p.Enter(nil, Location{0, 0x0150}, Location{0, 0x2000})
+	p.Instruction(outer, Location{0, 0x2000}, 24)
+	inner := p.Enter(outer, Location{0, 0x2000}, Location{0, 0x2000})
+	p.Instruction(inner, Location{0, 0x2000}, 10)
+	p.Instruction(inner, Location{0, 0x2001}, 16)
+	p.Instruction(outer, Location{0, 0x2001}, 16)
+
+	f := findFunction(p, Location{0, 0x2000})
+	assert.Equal(t, 2, f.Calls)
+	assert.Equal(t, 24+10+16+16, f.Self)
+	assert.Equal(t, f.Self, f.Total)
+}
+
+func TestWriteReport(t *testing.T) {
+	var b bytes.Buffer
+	assert.Nil(t, profileNestedCalls().WriteReport(&b, 70224, 2))
+
+	report := b.String()
+	assert.True(t, strings.HasPrefix(report, "Profiled 344 cycles"))
+	assert.Contains(t, report, "01:4100")
+	assert.NotContains(t, report, "[root]")
+}
+
+func TestWritePprof(t *testing.T) {
+	var b bytes.Buffer
+	assert.Nil(t, profileNestedCalls().WritePprof(&b))
+
+	z, err := gzip.NewReader(&b)
+	assert.Nil(t, err)
+	data, err := ioutil.ReadAll(z)
+	assert.Nil(t, err)
+
+	//the string table holds the name of every function
+	for _, name := range []string{"cycles", "[root]", "01:4000", "01:4100"} {
+		assert.True(t, bytes.Contains(data, []byte(name)), name)
+	}
+}
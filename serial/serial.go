@@ -0,0 +1,116 @@
+package serial
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/djhworld/gomeboycolor/components"
+	"github.com/djhworld/gomeboycolor/constants"
+	"github.com/djhworld/gomeboycolor/types"
+)
+
+const (
+	SB_REGISTER types.Word = 0xFF01 //serial transfer data
+	SC_REGISTER types.Word = 0xFF02 //serial transfer control
+)
+
+const (
+	NAME   = "SERIAL"
+	PREFIX = NAME + ":"
+)
+
+//M-cycles taken to shift out all 8 bits using the internal 8192hz clock
+const TRANSFER_CYCLES int = 1024
+
+//Interested parties can be told about every byte sent out over the link cable
+type SerialObserver interface {
+	OnSerialTransfer(value byte)
+}
+
+//Serial port with nothing connected to the other end of the link cable. Transfers that use
+//the internal clock complete as normal with 0xFF shifted in, transfers that rely on an
+//external clock never complete
+type Serial struct {
+	data         byte
+	control      byte
+	transferring bool
+	cycles       int
+	irqHandler   components.IRQHandler
+	observers    []SerialObserver
+}
+
+func NewSerial() *Serial {
+	var s *Serial = new(Serial)
+	s.Reset()
+	return s
+}
+
+func (s *Serial) Name() string {
+	return NAME
+}
+
+func (s *Serial) RegisterObserver(o SerialObserver) {
+	s.observers = append(s.observers, o)
+}
+
+func (s *Serial) Step(cycles int) {
+	if !s.transferring {
+		return
+	}
+
+	s.cycles += cycles
+	if s.cycles >= TRANSFER_CYCLES {
+		s.transferring = false
+		s.cycles = 0
+		s.data = 0xFF
+		s.control &= 0x7F
+		s.irqHandler.RequestInterrupt(constants.SERIAL_IRQ)
+	}
+}
+
+func (s *Serial) Read(address types.Word) byte {
+	switch address {
+	case SB_REGISTER:
+		return s.data
+	case SC_REGISTER:
+		//unused bits read back as 1
+		return s.control | 0x7E
+	default:
+		panic(fmt.Sprintln("Serial module is not set up to handle address", address))
+	}
+}
+
+func (s *Serial) Write(address types.Word, value byte) {
+	switch address {
+	case SB_REGISTER:
+		s.data = value
+	case SC_REGISTER:
+		s.control = value & 0x81
+		if value&0x81 == 0x81 {
+			s.startTransfer()
+		}
+	default:
+		panic(fmt.Sprintln("Serial module is not set up to handle address", address))
+	}
+}
+
+func (s *Serial) startTransfer() {
+	for _, o := range s.observers {
+		o.OnSerialTransfer(s.data)
+	}
+	s.transferring = true
+	s.cycles = 0
+}
+
+func (s *Serial) LinkIRQHandler(m components.IRQHandler) {
+	s.irqHandler = m
+	log.Println(PREFIX, "Linked IRQ Handler to Serial")
+}
+
+func (s *Serial) Reset() {
+	log.Println(PREFIX, "Resetting", NAME)
+	s.data = 0x00
+	s.control = 0x00
+	s.transferring = false
+	s.cycles = 0
+}
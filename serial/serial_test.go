@@ -0,0 +1,60 @@
+package serial
+
+import (
+	"testing"
+
+	"github.com/djhworld/gomeboycolor/constants"
+	"github.com/stretchrcom/testify/assert"
+)
+
+type mockIRQHandler struct {
+	requested []byte
+}
+
+func (m *mockIRQHandler) RequestInterrupt(interrupt byte) {
+	m.requested = append(m.requested, interrupt)
+}
+
+type mockObserver struct {
+	sent []byte
+}
+
+func (m *mockObserver) OnSerialTransfer(value byte) {
+	m.sent = append(m.sent, value)
+}
+
+func setupSerial() (*Serial, *mockIRQHandler, *mockObserver) {
+	s := NewSerial()
+	irq := new(mockIRQHandler)
+	observer := new(mockObserver)
+	s.LinkIRQHandler(irq)
+	s.RegisterObserver(observer)
+	return s, irq, observer
+}
+
+func TestInternalClockTransferCompletes(t *testing.T) {
+	s, irq, observer := setupSerial()
+	s.Write(SB_REGISTER, 0x41)
+	s.Write(SC_REGISTER, 0x81)
+	assert.Equal(t, []byte{0x41}, observer.sent)
+	assert.Equal(t, byte(0xFF), s.Read(SC_REGISTER))
+
+	s.Step(TRANSFER_CYCLES - 1)
+	assert.Empty(t, irq.requested)
+
+	s.Step(1)
+	assert.Equal(t, []byte{constants.SERIAL_IRQ}, irq.requested)
+	assert.Equal(t, byte(0x7F), s.Read(SC_REGISTER))
+	assert.Equal(t, byte(0xFF), s.Read(SB_REGISTER))
+}
+
+func TestExternalClockTransferNeverCompletes(t *testing.T) {
+	s, irq, observer := setupSerial()
+	s.Write(SB_REGISTER, 0x41)
+	s.Write(SC_REGISTER, 0x80)
+	s.Step(TRANSFER_CYCLES * 10)
+
+	assert.Empty(t, observer.sent)
+	assert.Empty(t, irq.requested)
+	assert.Equal(t, byte(0x41), s.Read(SB_REGISTER))
+}